@@ -19,6 +19,10 @@ const (
 	Dtb
 	UEFIBinary
 	WindowsBootMgr
+	UKI
+	Cmdline
+	BootEntry
+	Microcode
 	_end_boot_categories = 0x8000
 
 	// 0x8001 - 0x8FFF reserved for bios artifacts
@@ -44,6 +48,37 @@ type Handler interface {
 	ParseClaims(jsonClaims []byte) (interface{}, error)
 	// Check matching between requirements and claims for a given artifact
 	Check(requirements interface{}, claims interface{}) error
+	// Evaluate each requirement that is set against the claims for a given
+	// artifact, without stopping at the first one that is not met.
+	// Check() fails with the error of the first result that is not met.
+	Evaluate(requirements interface{}, claims interface{}) (Results, error)
+}
+
+// Define the outcome of an artifact requirement check
+type Result struct {
+	// requirement name, as defined in the requirements JSON format
+	Requirement string
+	// nil if the requirement is met
+	Err error
+}
+
+// Define the outcome of all the requirement checks for an artifact
+type Results []Result
+
+// Add the outcome of a requirement check
+func (r *Results) Add(requirement string, err error) {
+	*r = append(*r, Result{Requirement: requirement, Err: err})
+}
+
+// Return the error of the first requirement that is not met, if any
+func (r Results) Err() error {
+	for _, res := range r {
+		if res.Err != nil {
+			return fmt.Errorf("%s requirement not met: %w", res.Requirement, res.Err)
+		}
+	}
+
+	return nil
 }
 
 // Define the list of registered artifact handlers
@@ -0,0 +1,143 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package artifacttest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// Valid artifact hash, for testing
+const Hash = "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59"
+
+// Define an invalid requirements, or claims, JSON document along with the
+// field error expected from its parsing.
+type Invalid struct {
+	JSON string
+	// path of the invalid field
+	Path string
+	// kind of field error
+	Err error
+}
+
+// Define the expected outcome of a requirement evaluation
+type Outcome struct {
+	Requirement string
+	Passed      bool
+}
+
+// Return the registered artifact handler for a given category
+func Handler(t testing.TB, category uint) artifact.Handler {
+	t.Helper()
+
+	h, err := artifact.GetHandler(category)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func checkFieldError(t testing.TB, v Invalid, err error) {
+	t.Helper()
+
+	var fe *artifact.FieldError
+
+	if !errors.As(err, &fe) || fe.Path != v.Path || !errors.Is(err, v.Err) {
+		t.Fatalf("unexpected error for %s: %v", v.JSON, err)
+	}
+}
+
+// Ensure that each invalid requirements document is rejected with the
+// expected field error
+func NegativeParseRequirements(t testing.TB, category uint, invalid []Invalid) {
+	t.Helper()
+
+	h := Handler(t, category)
+
+	for _, v := range invalid {
+		// error expected here as the requirements are not valid
+		_, err := h.ParseRequirements([]byte(v.JSON))
+		checkFieldError(t, v, err)
+	}
+}
+
+// Ensure that each invalid claims document is rejected with the expected
+// field error
+func NegativeParseClaims(t testing.TB, category uint, invalid []Invalid) {
+	t.Helper()
+
+	h := Handler(t, category)
+
+	for _, v := range invalid {
+		// error expected here as the claims are not valid
+		_, err := h.ParseClaims([]byte(v.JSON))
+		checkFieldError(t, v, err)
+	}
+}
+
+// Ensure that each requirements document is not met by the claims
+func NegativeCheck(t testing.TB, category uint, claims []byte, requirements []string) {
+	t.Helper()
+
+	h := Handler(t, category)
+
+	parsedClaims, err := h.ParseClaims(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range requirements {
+		parsedRequirements, err := h.ParseRequirements([]byte(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// error expected here as the requirement is not met
+		if err = h.Check(parsedRequirements, parsedClaims); err == nil {
+			t.Fatalf("unmet requirement has been accepted: %s", r)
+		}
+	}
+}
+
+// Evaluate requirements against claims, and ensure that the results
+// match, in order, the expected outcomes.
+func Evaluate(t testing.TB, category uint, requirements []byte, claims []byte, expected []Outcome) artifact.Results {
+	t.Helper()
+
+	h := Handler(t, category)
+
+	parsedRequirements, err := h.ParseRequirements(requirements)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedClaims, err := h.ParseClaims(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := h.Evaluate(parsedRequirements, parsedClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != len(expected) {
+		t.Fatalf("unexpected number of results: %d", len(res))
+	}
+
+	for i, r := range res {
+		if r.Requirement != expected[i].Requirement || expected[i].Passed != (r.Err == nil) {
+			t.Fatalf("unexpected result for %s requirement: %v", r.Requirement, r.Err)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,169 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package boot_entry
+
+import (
+	"fmt"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+	"github.com/usbarmory/boot-transparency/artifact/cmdline"
+)
+
+// Boot entry formats
+const (
+	// Boot Loader Specification type #1 entry (e.g. systemd-boot)
+	BLS = "bls"
+	// GRUB configuration menu entry
+	GRUB = "grub"
+)
+
+// Define the BootEntry handler
+type BootEntry struct{}
+
+// Register the handler for the BootEntry category
+func init() {
+	h := BootEntry{}
+	artifact.Add(&h, artifact.BootEntry)
+}
+
+func checkFormat(format string) error {
+	switch format {
+	case "", BLS, GRUB:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s or %s expected", artifact.ErrInvalidFormat, BLS, GRUB)
+}
+
+// Parse requirements for the BootEntry category
+func (h *BootEntry) ParseRequirements(jsonRequirements []byte) (interface{}, error) {
+	var r Requirements
+
+	if err := artifact.Unmarshal(jsonRequirements, &r); err != nil {
+		return nil, err
+	}
+
+	if err := checkFormat(r.Format); err != nil {
+		return nil, artifact.AtPath("format", err)
+	}
+
+	if r.Options != nil {
+		if err := r.Options.Validate(); err != nil {
+			return nil, artifact.AtPath("options", err)
+		}
+	}
+
+	return &r, nil
+}
+
+// Parse claims for the BootEntry category
+func (h *BootEntry) ParseClaims(jsonClaims []byte) (interface{}, error) {
+	var c Claims
+
+	if err := artifact.Unmarshal(jsonClaims, &c); err != nil {
+		return nil, err
+	}
+
+	if err := checkFormat(c.Format); err != nil {
+		return nil, artifact.AtPath("format", err)
+	}
+
+	if _, err := cmdline.Tokenize(c.Options); err != nil {
+		return nil, artifact.AtPath("options", fmt.Errorf("%w: %v", artifact.ErrInvalidFormat, err))
+	}
+
+	return &c, nil
+}
+
+// Check matching between requirements and claims for the BootEntry category
+func (h *BootEntry) Check(require interface{}, claim interface{}) error {
+	res, err := h.Evaluate(require, claim)
+
+	if err != nil {
+		return err
+	}
+
+	return res.Err()
+}
+
+// Evaluate requirements against claims for the BootEntry category
+func (h *BootEntry) Evaluate(require interface{}, claim interface{}) (res artifact.Results, err error) {
+	if _, ok := require.(*Requirements); !ok {
+		return nil, fmt.Errorf("invalid·policy requirements for BootEntry")
+	}
+
+	if _, ok := claim.(*Claims); !ok {
+		return nil, fmt.Errorf("invalid·claims for BootEntry")
+	}
+
+	r := require.(*Requirements)
+	c := claim.(*Claims)
+
+	// evaluate all the supported policy requirements for BootEntry
+	if r.Hash != "" {
+		res.Add("hash", artifact.CheckHash(r.Hash, c.Hash))
+	}
+
+	if r.Format != "" {
+		res.Add("format", checkAllowed("format", []string{r.Format}, c.Format))
+	}
+
+	if len(r.Title) > 0 {
+		res.Add("title", checkAllowed("title", r.Title, c.Title))
+	}
+
+	if len(r.Linux) > 0 {
+		res.Add("linux", checkAllowed("kernel image", r.Linux, c.Linux))
+	}
+
+	if len(r.Initrd) > 0 {
+		res.Add("initrd", checkInitrd(r.Initrd, c.Initrd))
+	}
+
+	if len(r.Devicetree) > 0 {
+		res.Add("devicetree", checkDevicetree(r.Devicetree, c.Devicetree))
+	}
+
+	if r.Options != nil {
+		options, err := r.Options.Evaluate(c.Options)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid·claims for BootEntry: %v", err)
+		}
+
+		for _, o := range options {
+			res.Add("options."+o.Requirement, o.Err)
+		}
+	}
+
+	return
+}
+
+func checkAllowed(name string, require []string, claim string) error {
+	if !artifact.CheckElementInclusion(require, claim) {
+		return fmt.Errorf("%s %q does not met requirements", name, claim)
+	}
+
+	return nil
+}
+
+func checkDevicetree(require []string, claim string) error {
+	if claim == "" {
+		return nil
+	}
+
+	return checkAllowed("device tree", require, claim)
+}
+
+func checkInitrd(require []string, claim []string) error {
+	if err := artifact.CheckArrayInclusion(require, claim); err != nil {
+		return fmt.Errorf("initrd %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package boot_entry
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+	"github.com/usbarmory/boot-transparency/artifact/artifacttest"
+)
+
+var testClaims = []byte(`{
+    "file_name": "loader/entries/debian-6.14.0-29-generic.conf",
+    "hash": "` + artifacttest.Hash + `",
+    "format": "bls",
+    "title": "Debian GNU/Linux 13",
+    "version": "6.14.0-29-generic",
+    "linux": "/vmlinuz-6.14.0-29-generic",
+    "initrd": ["/intel-ucode.img", "/initrd.img-6.14.0-29-generic"],
+    "options": "root=/dev/sda1 ro lockdown=integrity"
+}`)
+
+func TestBootEntryParseRequirements(t *testing.T) {
+	r := []byte(`{"format": "bls", "linux": ["/vmlinuz-6.14.0-29-generic"], "initrd": ["/initrd.img-6.14.0-29-generic"], "options": {"required": ["lockdown=integrity"], "forbidden": ["init"]}}`)
+
+	h, err := artifact.GetHandler(artifact.BootEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.ParseRequirements(r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeBootEntryParseRequirements(t *testing.T) {
+	artifacttest.NegativeParseRequirements(t, artifact.BootEntry, []artifacttest.Invalid{
+		{JSON: `{"hash": "8ba6bc3d"}`, Path: "hash", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"format": "lilo"}`, Path: "format", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"options": {"required": ["ro", "=integrity"]}}`, Path: "options.required[1]", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"options": {"include": ["ro"]}}`, Path: "options.include", Err: artifact.ErrUnknownField},
+	})
+}
+
+func TestBootEntryParseClaims(t *testing.T) {
+	h, err := artifact.GetHandler(artifact.BootEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.ParseClaims(testClaims); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeBootEntryParseClaims(t *testing.T) {
+	h, err := artifact.GetHandler(artifact.BootEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []string{
+		// the hash is mandatory
+		`{"linux": "/vmlinuz"}`,
+		// the format is not supported
+		`{"hash": "` + artifacttest.Hash + `", "format": "lilo"}`,
+		// the options quote is not terminated
+		`{"hash": "` + artifacttest.Hash + `", "options": "init=\"/bin/sh"}`,
+	} {
+		// error expected here as the claims are not valid
+		if _, err := h.ParseClaims([]byte(c)); err == nil {
+			t.Fatalf("invalid claims have been accepted: %s", c)
+		}
+	}
+}
+
+func TestBootEntryCheck(t *testing.T) {
+	r := []byte(`{
+    "hash": "` + artifacttest.Hash + `",
+    "format": "bls",
+    "title": ["Debian GNU/Linux 13"],
+    "linux": ["/vmlinuz-6.14.0-29-generic"],
+    "initrd": ["/intel-ucode.img", "/initrd.img-6.14.0-29-generic"],
+    "devicetree": ["/imx6ulz-usbarmory.dtb"],
+    "options": {"required": ["lockdown=integrity"], "forbidden": ["init"]}
+}`)
+
+	h, err := artifact.GetHandler(artifact.BootEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedRequirements, err := h.ParseRequirements(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedClaims, err := h.ParseClaims(testClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = h.Check(parsedRequirements, parsedClaims); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeBootEntryCheck(t *testing.T) {
+	artifacttest.NegativeCheck(t, artifact.BootEntry, testClaims, []string{
+		// the hash does not match
+		`{"hash": "` + artifacttest.Hash[:127] + `0"}`,
+		// the format does not match
+		`{"format": "grub"}`,
+		// the title is not allowed
+		`{"title": ["Debian GNU/Linux 13 (recovery mode)"]}`,
+		// the kernel image is not allowed
+		`{"linux": ["/vmlinuz-6.14.0-30-generic"]}`,
+		// the microcode initrd is not allowed
+		`{"initrd": ["/initrd.img-6.14.0-29-generic"]}`,
+		// the options include a forbidden argument
+		`{"options": {"forbidden": ["lockdown"]}}`,
+	})
+}
+
+func TestNegativeBootEntryCheckInitArguments(t *testing.T) {
+	r := []byte(`{"options": {"required": ["lockdown=integrity"]}}`)
+	c := []byte(`{"hash": "` + artifacttest.Hash + `", "format": "bls", "linux": "/vmlinuz-6.14.0-29-generic", "options": "root=/dev/sda1 ro -- lockdown=integrity"}`)
+
+	h, err := artifact.GetHandler(artifact.BootEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedRequirements, err := h.ParseRequirements(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedClaims, err := h.ParseClaims(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the arguments following "--" are passed to
+	// init, and not interpreted by the kernel
+	if err = h.Check(parsedRequirements, parsedClaims); err == nil {
+		t.Fatal("init argument has been accepted as kernel one")
+	}
+}
+
+func TestBootEntryEvaluate(t *testing.T) {
+	r := []byte(`{"linux": ["/vmlinuz-6.14.0-29-generic"], "options": {"required": ["ro"], "strict": true}}`)
+
+	artifacttest.Evaluate(t, artifact.BootEntry, r, testClaims, []artifacttest.Outcome{
+		{Requirement: "linux", Passed: true},
+		{Requirement: "options.required", Passed: true},
+		{Requirement: "options.strict", Passed: false},
+	})
+}
+
+func TestBootEntryExtractClaimsBLS(t *testing.T) {
+	h := &BootEntry{}
+
+	entry := []byte(`# Boot Loader Specification type #1 entry
+title      Debian GNU/Linux 13
+version    6.14.0-29-generic
+linux      /vmlinuz-6.14.0-29-generic
+initrd     /intel-ucode.img
+initrd     /initrd.img-6.14.0-29-generic
+options    root=/dev/sda1 ro
+options    lockdown=integrity
+devicetree /imx6ulz-usbarmory.dtb
+`)
+
+	c, err := h.ExtractClaims(&artifact.File{Name: "loader/entries/debian.conf", Data: entry, Hash: artifacttest.Hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := c.(*Claims)
+
+	if claims.Format != BLS || claims.Title != "Debian GNU/Linux 13" || claims.Version != "6.14.0-29-generic" || claims.Linux != "/vmlinuz-6.14.0-29-generic" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	if !slices.Equal(claims.Initrd, []string{"/intel-ucode.img", "/initrd.img-6.14.0-29-generic"}) || claims.Options != "root=/dev/sda1 ro lockdown=integrity" || claims.Devicetree != "/imx6ulz-usbarmory.dtb" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	// error expected here as the entry does not include a kernel
+	if _, err = h.ExtractClaims(&artifact.File{Data: []byte("title Debian\n")}); err == nil {
+		t.Fatal("claims have been extracted from an entry without kernel")
+	}
+}
+
+func TestBootEntryExtractClaimsGRUB(t *testing.T) {
+	h := &BootEntry{}
+
+	entry := []byte(`set timeout=0
+
+menuentry 'Debian GNU/Linux, with Linux 6.14.0-29-generic' --class debian $menuentry_id_option 'gnulinux-6.14.0-29-generic' {
+	insmod ext2
+	echo	'Loading Linux 6.14.0-29-generic ...'
+	linux	/vmlinuz-6.14.0-29-generic root=/dev/sda1 ro dyndbg="file drivers/usb/* +p"
+	initrd	/intel-ucode.img /initrd.img-6.14.0-29-generic
+}
+`)
+
+	c, err := h.ExtractClaims(&artifact.File{Name: "grub/custom.cfg", Data: entry, Hash: artifacttest.Hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := c.(*Claims)
+
+	if claims.Format != GRUB || claims.Title != "Debian GNU/Linux, with Linux 6.14.0-29-generic" || claims.Linux != "/vmlinuz-6.14.0-29-generic" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	if !slices.Equal(claims.Initrd, []string{"/intel-ucode.img", "/initrd.img-6.14.0-29-generic"}) || claims.Options != `root=/dev/sda1 ro dyndbg="file drivers/usb/* +p"` {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	// error expected here as the configuration includes multiple entries
+	if _, err = h.ExtractClaims(&artifact.File{Data: append(entry, entry...)}); err == nil {
+		t.Fatal("claims have been extracted from multiple entries")
+	}
+}
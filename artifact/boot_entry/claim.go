@@ -0,0 +1,38 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package boot_entry
+
+// Supported claims for BootEntry artifact
+type Claims struct {
+	// filename of the artifact (e.g. loader/entries/debian.conf)
+	FileName string `json:"file_name,omitempty"`
+
+	// SHA-512 hash of the artifact, mandatory
+	Hash string `json:"hash,omitempty" validate:"required,hash"`
+
+	// entry format (i.e. bls or grub)
+	Format string `json:"format,omitempty"`
+
+	// human-readable entry title
+	Title string `json:"title,omitempty"`
+
+	// entry version, as set by the Boot Loader Specification version field
+	Version string `json:"version,omitempty"`
+
+	// path of the kernel image (e.g. /vmlinuz-6.14.0-29-generic)
+	Linux string `json:"linux,omitempty"`
+
+	// path(s) of the initial ramdisk(s), in loading order
+	Initrd []string `json:"initrd,omitempty"`
+
+	// kernel command line
+	Options string `json:"options,omitempty"`
+
+	// path of the device tree blob
+	Devicetree string `json:"devicetree,omitempty"`
+}
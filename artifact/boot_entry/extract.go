@@ -0,0 +1,176 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package boot_entry
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+	"github.com/usbarmory/boot-transparency/artifact/cmdline"
+)
+
+// Return the first word of a line and the remaining, trimmed, text
+func command(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	i := strings.IndexAny(line, " \t")
+
+	if i < 0 {
+		return line, ""
+	}
+
+	return line[:i], strings.TrimSpace(line[i+1:])
+}
+
+// Return the configuration lines, without comments and empty lines
+func lines(data []byte) (l []string) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		l = append(l, line)
+	}
+
+	return
+}
+
+// Parse a Boot Loader Specification type #1 entry, options lines are
+// concatenated as defined by the specification.
+func parseBLS(data []byte, c *Claims) {
+	var options []string
+
+	for _, line := range lines(data) {
+		key, value := command(line)
+
+		switch key {
+		case "title":
+			c.Title = value
+		case "version":
+			c.Version = value
+		case "linux":
+			c.Linux = value
+		case "initrd":
+			c.Initrd = append(c.Initrd, strings.Fields(value)...)
+		case "options":
+			options = append(options, value)
+		case "devicetree":
+			c.Devicetree = value
+		}
+	}
+
+	c.Options = strings.Join(options, " ")
+}
+
+// Return the menu entry title, which is its first, possibly quoted, argument
+func grubTitle(args string) string {
+	if args == "" {
+		return ""
+	}
+
+	if q := args[0]; q == '\'' || q == '"' {
+		if title, _, ok := strings.Cut(args[1:], string(q)); ok {
+			return title
+		}
+	}
+
+	title, _ := command(args)
+
+	return title
+}
+
+// Parse a GRUB configuration menu entry, the configuration must include
+// a single menuentry block.
+func parseGRUB(data []byte, c *Claims) error {
+	entries := 0
+	inEntry := false
+
+	for _, line := range lines(data) {
+		cmd, args := command(line)
+
+		if cmd == "menuentry" {
+			if entries++; entries > 1 {
+				return fmt.Errorf("multiple menu entries")
+			}
+
+			c.Title = grubTitle(args)
+			inEntry = strings.HasSuffix(args, "{")
+
+			continue
+		}
+
+		if !inEntry {
+			if cmd == "{" {
+				inEntry = entries == 1
+			}
+
+			continue
+		}
+
+		switch cmd {
+		case "}":
+			inEntry = false
+		case "linux", "linux16", "linuxefi":
+			c.Linux, c.Options = command(args)
+		case "initrd", "initrd16", "initrdefi":
+			c.Initrd = append(c.Initrd, strings.Fields(args)...)
+		case "devicetree":
+			c.Devicetree = args
+		}
+	}
+
+	return nil
+}
+
+// Return true if the configuration includes a GRUB menu entry
+func isGRUB(data []byte) bool {
+	for _, line := range lines(data) {
+		if cmd, _ := command(line); cmd == "menuentry" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Extract claims for the BootEntry category, the entry is parsed either as
+// a GRUB configuration, when including a menuentry block, or as a Boot
+// Loader Specification type #1 entry.
+func (h *BootEntry) ExtractClaims(f *artifact.File) (interface{}, error) {
+	c := &Claims{
+		FileName: f.Name,
+		Hash:     f.Hash,
+	}
+
+	if isGRUB(f.Data) {
+		c.Format = GRUB
+
+		if err := parseGRUB(f.Data, c); err != nil {
+			return nil, fmt.Errorf("invalid boot entry: %v", err)
+		}
+	} else {
+		c.Format = BLS
+		parseBLS(f.Data, c)
+	}
+
+	if c.Linux == "" {
+		return nil, fmt.Errorf("invalid boot entry: kernel image not found")
+	}
+
+	if _, err := cmdline.Tokenize(c.Options); err != nil {
+		return nil, fmt.Errorf("invalid boot entry options: %v", err)
+	}
+
+	return c, nil
+}
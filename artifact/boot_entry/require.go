@@ -0,0 +1,39 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package boot_entry
+
+import (
+	"github.com/usbarmory/boot-transparency/artifact/cmdline"
+)
+
+// Supported requirements for BootEntry artifact
+type Requirements struct {
+	// required SHA-512 hash of the artifact
+	Hash string `json:"hash,omitempty" validate:"hash"`
+
+	// allowed entry format (i.e. bls or grub)
+	Format string `json:"format,omitempty"`
+
+	// list of allowed entry titles
+	Title []string `json:"title,omitempty"`
+
+	// list of allowed kernel image paths
+	Linux []string `json:"linux,omitempty"`
+
+	// list of allowed initial ramdisk paths, all the claimed ones must be
+	// included
+	Initrd []string `json:"initrd,omitempty"`
+
+	// list of allowed device tree blob paths, entries without device tree
+	// are always allowed
+	Devicetree []string `json:"devicetree,omitempty"`
+
+	// kernel command line requirements, as supported by the Cmdline
+	// category
+	Options *cmdline.Requirements `json:"options,omitempty"`
+}
@@ -0,0 +1,118 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package cmdline
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Define a kernel command line argument
+type Argument struct {
+	// argument key (e.g. lockdown)
+	Key string
+
+	// argument value, without quotes (e.g. integrity)
+	Value string
+
+	// true if the argument has a value, even if empty (i.e. key=)
+	HasValue bool
+}
+
+// Return the argument in key=value form, without quotes
+func (a Argument) String() string {
+	if !a.HasValue {
+		return a.Key
+	}
+
+	return a.Key + "=" + a.Value
+}
+
+// Return true if the argument has a given key, as for kernel parameters
+// dashes and underscores are equivalent (e.g. module.sig_enforce matches
+// module.sig-enforce).
+func (a Argument) Is(key string) bool {
+	return normalizeKey(a.Key) == normalizeKey(key)
+}
+
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(key, "-", "_")
+}
+
+// Return the argument represented by a single, unquoted, key or key=value
+// string.
+func ParseArgument(s string) Argument {
+	key, value, hasValue := strings.Cut(s, "=")
+
+	return Argument{
+		Key:      key,
+		Value:    value,
+		HasValue: hasValue,
+	}
+}
+
+func isSpace(c rune) bool {
+	switch c {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+
+	return false
+}
+
+// Return the arguments of a kernel command line. Arguments are separated by
+// whitespace, while double quotes group whitespace within an argument
+// (e.g. dyndbg="file drivers/usb/* +p") and are removed.
+//
+// Return error if:
+//   - a double quote is not terminated
+func Tokenize(cmdline string) (args []Argument, err error) {
+	var token strings.Builder
+
+	inToken := false
+	inQuote := false
+
+	for _, c := range cmdline {
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+			inToken = true
+		case isSpace(c) && !inQuote:
+			if inToken {
+				args = append(args, ParseArgument(token.String()))
+				token.Reset()
+				inToken = false
+			}
+		default:
+			token.WriteRune(c)
+			inToken = true
+		}
+	}
+
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+
+	if inToken {
+		args = append(args, ParseArgument(token.String()))
+	}
+
+	return
+}
+
+// Return the arguments interpreted by the kernel, that is the ones before
+// a bare "--" argument, as any following one is passed to init.
+func KernelArguments(args []Argument) []Argument {
+	for i, arg := range args {
+		if arg.Key == "--" && !arg.HasValue {
+			return args[:i]
+		}
+	}
+
+	return args
+}
@@ -0,0 +1,21 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package cmdline
+
+// Supported claims for Cmdline artifact
+type Claims struct {
+	// filename of the artifact, if the command line is read from a file
+	// (e.g. cmdline.txt)
+	FileName string `json:"file_name,omitempty"`
+
+	// SHA-512 hash of the artifact, if the command line is read from a file
+	Hash string `json:"hash,omitempty" validate:"hash"`
+
+	// kernel command line
+	Cmdline string `json:"cmdline"`
+}
@@ -0,0 +1,266 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package cmdline
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// Define the Cmdline handler
+type Cmdline struct{}
+
+// Register the handler for the Cmdline category
+func init() {
+	h := Cmdline{}
+	artifact.Add(&h, artifact.Cmdline)
+}
+
+func checkArgument(arg string) error {
+	if ParseArgument(arg).Key == "" {
+		return fmt.Errorf("%w: argument key expected", artifact.ErrInvalidFormat)
+	}
+
+	return nil
+}
+
+// Validate the requirements, as done when parsing them, for requirements
+// embedded in other categories ones (e.g. boot entry options).
+//
+// Return error if:
+//   - the required command line is not valid
+//   - any argument key is not set
+func (r *Requirements) Validate() error {
+	if _, err := Tokenize(r.Cmdline); err != nil {
+		return artifact.AtPath("cmdline", fmt.Errorf("%w: %v", artifact.ErrInvalidFormat, err))
+	}
+
+	for i, arg := range r.Required {
+		if err := checkArgument(arg); err != nil {
+			return artifact.AtPath(fmt.Sprintf("required[%d]", i), err)
+		}
+	}
+
+	for i, arg := range r.Forbidden {
+		if err := checkArgument(arg); err != nil {
+			return artifact.AtPath(fmt.Sprintf("forbidden[%d]", i), err)
+		}
+	}
+
+	for key := range r.Values {
+		if key == "" {
+			return artifact.AtPath("values", fmt.Errorf("%w: argument key expected", artifact.ErrInvalidFormat))
+		}
+	}
+
+	return nil
+}
+
+// Parse requirements for the Cmdline category
+func (h *Cmdline) ParseRequirements(jsonRequirements []byte) (interface{}, error) {
+	var r Requirements
+
+	if err := artifact.Unmarshal(jsonRequirements, &r); err != nil {
+		return nil, err
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// Parse claims for the Cmdline category
+func (h *Cmdline) ParseClaims(jsonClaims []byte) (interface{}, error) {
+	var c Claims
+
+	if err := artifact.Unmarshal(jsonClaims, &c); err != nil {
+		return nil, err
+	}
+
+	if _, err := Tokenize(c.Cmdline); err != nil {
+		return nil, artifact.AtPath("cmdline", fmt.Errorf("%w: %v", artifact.ErrInvalidFormat, err))
+	}
+
+	return &c, nil
+}
+
+// Check matching between requirements and claims for the Cmdline category
+func (h *Cmdline) Check(require interface{}, claim interface{}) error {
+	res, err := h.Evaluate(require, claim)
+
+	if err != nil {
+		return err
+	}
+
+	return res.Err()
+}
+
+// Evaluate requirements against claims for the Cmdline category
+func (h *Cmdline) Evaluate(require interface{}, claim interface{}) (res artifact.Results, err error) {
+	if _, ok := require.(*Requirements); !ok {
+		return nil, fmt.Errorf("invalid·policy requirements for Cmdline")
+	}
+
+	if _, ok := claim.(*Claims); !ok {
+		return nil, fmt.Errorf("invalid·claims for Cmdline")
+	}
+
+	r := require.(*Requirements)
+	c := claim.(*Claims)
+
+	return r.Evaluate(c.Cmdline)
+}
+
+// Evaluate the requirements against a command line, for requirements
+// embedded in other categories ones (e.g. boot entry options).
+//
+// Only the exact-match mode considers the arguments passed to init (i.e.
+// following a bare "--"), all other requirements are evaluated against
+// the arguments interpreted by the kernel (see KernelArguments()).
+//
+// Return error if:
+//   - the command line is not valid
+func (r *Requirements) Evaluate(cmdline string) (res artifact.Results, err error) {
+	all, err := Tokenize(cmdline)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid command line: %v", err)
+	}
+
+	// evaluate all the supported policy requirements for Cmdline
+	if r.Cmdline != "" {
+		res.Add("cmdline", checkCmdline(r.Cmdline, all))
+	}
+
+	args := KernelArguments(all)
+
+	for _, arg := range r.Required {
+		res.Add("required", checkRequired(ParseArgument(arg), args))
+	}
+
+	for _, arg := range r.Forbidden {
+		res.Add("forbidden", checkForbidden(ParseArgument(arg), args))
+	}
+
+	// sort the keys for a deterministic evaluation order
+	keys := make([]string, 0, len(r.Values))
+
+	for key := range r.Values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		res.Add("values", checkValues(key, r.Values[key], args))
+	}
+
+	if r.Strict {
+		res.Add("strict", checkStrict(r, args))
+	}
+
+	return
+}
+
+// Check that the command line matches the required one, argument by argument
+func checkCmdline(require string, claim []Argument) error {
+	args, err := Tokenize(require)
+
+	if err != nil {
+		return err
+	}
+
+	if !slices.Equal(args, claim) {
+		return fmt.Errorf("command line does not match the required one")
+	}
+
+	return nil
+}
+
+// Check that a required argument is present, when a value is required it
+// must match the value of the last occurrence of the key, as later
+// arguments override earlier ones.
+func checkRequired(require Argument, claim []Argument) error {
+	for i := len(claim) - 1; i >= 0; i-- {
+		if !claim[i].Is(require.Key) {
+			continue
+		}
+
+		if require.HasValue && (!claim[i].HasValue || claim[i].Value != require.Value) {
+			return fmt.Errorf("%s argument is set to %q, %q required", require.Key, claim[i].String(), require.String())
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%s argument is not present", require.String())
+}
+
+// Check that a forbidden argument is not present, any occurrence of the key
+// is forbidden unless a value is given.
+func checkForbidden(require Argument, claim []Argument) error {
+	for _, arg := range claim {
+		if !arg.Is(require.Key) {
+			continue
+		}
+
+		if !require.HasValue || (arg.HasValue && arg.Value == require.Value) {
+			return fmt.Errorf("%s argument not allowed", arg.String())
+		}
+	}
+
+	return nil
+}
+
+// Check that all the occurrences of a given key have an allowed value
+func checkValues(key string, require []string, claim []Argument) error {
+	for _, arg := range claim {
+		if !arg.Is(key) {
+			continue
+		}
+
+		if !arg.HasValue || !slices.Contains(require, arg.Value) {
+			return fmt.Errorf("%s argument value does not met requirements", arg.String())
+		}
+	}
+
+	return nil
+}
+
+// Check that only the arguments listed in the required, or allowed values,
+// requirements are present
+func checkStrict(require *Requirements, claim []Argument) error {
+	for _, arg := range claim {
+		allowed := false
+
+		for _, r := range require.Required {
+			if arg.Is(ParseArgument(r).Key) {
+				allowed = true
+				break
+			}
+		}
+
+		for key := range require.Values {
+			if arg.Is(key) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return fmt.Errorf("%s argument not allowed in strict mode", arg.String())
+		}
+	}
+
+	return nil
+}
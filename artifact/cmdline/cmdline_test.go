@@ -0,0 +1,214 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package cmdline
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+	"github.com/usbarmory/boot-transparency/artifact/artifacttest"
+)
+
+var testClaims = []byte(`{
+    "file_name": "cmdline.txt",
+    "hash": "` + artifacttest.Hash + `",
+    "cmdline": "root=/dev/sda1 ro lockdown=none module.sig-enforce=1 lockdown=integrity dyndbg=\"file drivers/usb/* +p\" quiet"
+}`)
+
+func TestTokenize(t *testing.T) {
+	args, err := Tokenize(` root=/dev/sda1  ro	dyndbg="file drivers/usb/* +p" "console=ttyS0 115200" empty= `)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Argument{
+		{Key: "root", Value: "/dev/sda1", HasValue: true},
+		{Key: "ro"},
+		{Key: "dyndbg", Value: "file drivers/usb/* +p", HasValue: true},
+		{Key: "console", Value: "ttyS0 115200", HasValue: true},
+		{Key: "empty", HasValue: true},
+	}
+
+	if !slices.Equal(args, expected) {
+		t.Fatalf("unexpected arguments: %+v", args)
+	}
+
+	// error expected here as the quote is not terminated
+	if _, err = Tokenize(`root=/dev/sda1 dyndbg="file`); err == nil {
+		t.Fatal("unterminated quote has been accepted")
+	}
+}
+
+func TestCmdlineParseRequirements(t *testing.T) {
+	r := []byte(`{"required": ["ro", "lockdown=integrity"], "forbidden": ["init", "module.sig_enforce=0"], "values": {"console": ["ttyS0,115200"]}}`)
+
+	h, err := artifact.GetHandler(artifact.Cmdline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.ParseRequirements(r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeCmdlineParseRequirements(t *testing.T) {
+	artifacttest.NegativeParseRequirements(t, artifact.Cmdline, []artifacttest.Invalid{
+		{JSON: `{"cmdline": "root=/dev/sda1 dyndbg=\"file"}`, Path: "cmdline", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"required": ["ro", "=integrity"]}`, Path: "required[1]", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"forbidden": [""]}`, Path: "forbidden[0]", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"values": {"": ["integrity"]}}`, Path: "values", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"include": ["ro"]}`, Path: "include", Err: artifact.ErrUnknownField},
+	})
+}
+
+func TestCmdlineParseClaims(t *testing.T) {
+	h, err := artifact.GetHandler(artifact.Cmdline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.ParseClaims(testClaims); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeCmdlineParseClaims(t *testing.T) {
+	c := []byte(`{"cmdline": "root=/dev/sda1 init=\"/bin/sh"}`)
+
+	h, err := artifact.GetHandler(artifact.Cmdline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected: the command line quote is not terminated
+	if _, err := h.ParseClaims(c); !errors.Is(err, artifact.ErrInvalidFormat) {
+		t.Fatal(err)
+	}
+}
+
+func TestCmdlineCheck(t *testing.T) {
+	r := []byte(`{
+    "cmdline": "root=/dev/sda1   ro lockdown=none module.sig-enforce=1 lockdown=integrity \"dyndbg=file drivers/usb/* +p\" quiet",
+    "required": ["root=/dev/sda1", "lockdown=integrity", "module.sig_enforce=1"],
+    "forbidden": ["init", "module.sig_enforce=0"],
+    "values": {"lockdown": ["none", "integrity", "confidentiality"], "dyndbg": ["file drivers/usb/* +p"]},
+    "strict": false
+}`)
+
+	h, err := artifact.GetHandler(artifact.Cmdline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedRequirements, err := h.ParseRequirements(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedClaims, err := h.ParseClaims(testClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = h.Check(parsedRequirements, parsedClaims); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeCmdlineCheck(t *testing.T) {
+	artifacttest.NegativeCheck(t, artifact.Cmdline, testClaims, []string{
+		// the command line does not match
+		`{"cmdline": "root=/dev/sda1 ro"}`,
+		// the required argument is not present
+		`{"required": ["nosmt"]}`,
+		// the effective lockdown value is the last one
+		`{"required": ["lockdown=none"]}`,
+		// the argument is present without value
+		`{"required": ["quiet=1"]}`,
+		// the argument is forbidden regardless of its value
+		`{"forbidden": ["module.sig_enforce"]}`,
+		// the argument is forbidden with the given value
+		`{"forbidden": ["lockdown=none"]}`,
+		// not all the lockdown occurrences have an allowed value
+		`{"values": {"lockdown": ["integrity"]}}`,
+		// only root, ro and lockdown arguments are allowed
+		`{"required": ["root", "ro"], "values": {"lockdown": ["none", "integrity"]}, "strict": true}`,
+	})
+}
+
+func TestCmdlineEvaluate(t *testing.T) {
+	r := []byte(`{"required": ["ro", "lockdown=integrity"], "forbidden": ["init"], "values": {"lockdown": ["integrity"]}, "strict": true}`)
+
+	artifacttest.Evaluate(t, artifact.Cmdline, r, testClaims, []artifacttest.Outcome{
+		{Requirement: "required", Passed: true},
+		{Requirement: "required", Passed: true},
+		{Requirement: "forbidden", Passed: true},
+		{Requirement: "values", Passed: false},
+		{Requirement: "strict", Passed: false},
+	})
+}
+
+func TestCmdlineInitArguments(t *testing.T) {
+	c := []byte(`{"cmdline": "root=/dev/sda1 quiet -- lockdown=integrity init=/bin/sh"}`)
+
+	h, err := artifact.GetHandler(artifact.Cmdline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := h.ParseClaims(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the arguments following "--" are passed to init, and not
+	// interpreted by the kernel, except for the exact-match mode
+	for _, v := range []struct {
+		requirements string
+		passed       bool
+	}{
+		{`{"cmdline": "root=/dev/sda1 quiet -- lockdown=integrity init=/bin/sh"}`, true},
+		{`{"cmdline": "root=/dev/sda1 quiet"}`, false},
+		{`{"required": ["lockdown=integrity"]}`, false},
+		{`{"forbidden": ["init"]}`, true},
+		{`{"values": {"lockdown": ["none"]}}`, true},
+		{`{"required": ["root", "quiet"], "strict": true}`, true},
+	} {
+		requirements, err := h.ParseRequirements([]byte(v.requirements))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = h.Check(requirements, claims); v.passed != (err == nil) {
+			t.Fatalf("unexpected result for %s: %v", v.requirements, err)
+		}
+	}
+}
+
+func TestCmdlineExtractClaims(t *testing.T) {
+	h := &Cmdline{}
+
+	c, err := h.ExtractClaims(&artifact.File{Name: "cmdline.txt", Data: []byte("console=serial0,115200 root=/dev/mmcblk0p2 rootwait\n"), Hash: artifacttest.Hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := c.(*Claims)
+
+	if claims.FileName != "cmdline.txt" || claims.Hash != artifacttest.Hash || claims.Cmdline != "console=serial0,115200 root=/dev/mmcblk0p2 rootwait" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	// error expected here as the quote is not terminated
+	if _, err = h.ExtractClaims(&artifact.File{Data: []byte(`init="/bin/sh`)}); err == nil {
+		t.Fatal("claims have been extracted from an invalid command line")
+	}
+}
@@ -0,0 +1,31 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package cmdline
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// Extract claims for the Cmdline category, the command line is read from a
+// file holding it (e.g. cmdline.txt) with surrounding whitespace removed.
+func (h *Cmdline) ExtractClaims(f *artifact.File) (interface{}, error) {
+	cmdline := string(bytes.TrimSpace(bytes.TrimRight(f.Data, "\x00")))
+
+	if _, err := Tokenize(cmdline); err != nil {
+		return nil, fmt.Errorf("invalid command line: %v", err)
+	}
+
+	return &Claims{
+		FileName: f.Name,
+		Hash:     f.Hash,
+		Cmdline:  cmdline,
+	}, nil
+}
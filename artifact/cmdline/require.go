@@ -0,0 +1,33 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package cmdline
+
+// Supported requirements for Cmdline artifact
+type Requirements struct {
+	// allow only a given command line (i.e. exact-match mode), the
+	// arguments are compared once tokenized, therefore regardless of the
+	// whitespace separating them
+	Cmdline string `json:"cmdline,omitempty"`
+
+	// arguments that must be present, either as key (e.g. quiet) or as
+	// key and value (e.g. lockdown=integrity). The value of the last
+	// occurrence of the key must match, as it is the effective one.
+	Required []string `json:"required,omitempty"`
+
+	// arguments that must not be present, either as key (e.g. init) to
+	// forbid any value, or as key and value (e.g. module.sig_enforce=0)
+	Forbidden []string `json:"forbidden,omitempty"`
+
+	// allowed values, for each given key, all the occurrences of the key
+	// must have one of the allowed values
+	Values map[string][]string `json:"values,omitempty"`
+
+	// allow only the arguments whose key is listed in the required, or
+	// allowed values, requirements
+	Strict bool `json:"strict,omitempty"`
+}
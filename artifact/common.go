@@ -177,3 +177,26 @@ func CheckStringNotInclude(require string, claim string) (err error) {
 
 	return
 }
+
+// Check if the architecture requirement is met
+func CheckArchitecture(require string, claim string) (err error) {
+	if require == "" {
+		return
+	}
+
+	if require != claim {
+		return fmt.Errorf("architecture %q does not met requirement", claim)
+	}
+
+	return
+}
+
+// Check if the tainted requirement is met, tainted artifacts are
+// allowed only when explicitly required
+func CheckTainted(require bool, claim bool) (err error) {
+	if claim && !require {
+		return fmt.Errorf("tainted artifact not allowed")
+	}
+
+	return
+}
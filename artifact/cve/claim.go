@@ -0,0 +1,44 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package cve
+
+// Supported vulnerability statuses
+const (
+	// the bundle is affected by the vulnerability, which is not fixed
+	Affected = "affected"
+	// the bundle includes the vulnerability fix
+	Fixed = "fixed"
+	// the bundle is not affected by the vulnerability (e.g. the vulnerable
+	// code is not built)
+	NotAffected = "not_affected"
+)
+
+// Define a vulnerability claimed for the bundle
+type Vulnerability struct {
+	// CVE identifier (e.g. CVE-2025-38236), mandatory
+	ID string `json:"id" validate:"required"`
+
+	// vulnerability status (i.e. affected, fixed or not_affected), mandatory
+	Status string `json:"status" validate:"required"`
+
+	// CVSS base score, from 0.0 to 10.0
+	CVSS *float64 `json:"cvss,omitempty"`
+
+	// CVSS vector string (e.g. CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H)
+	Vector string `json:"cvss_vector,omitempty"`
+}
+
+// Supported claims for CVE artifact
+type Claims struct {
+	// vulnerabilities affecting, or fixed by, the bundle
+	CVEs []Vulnerability `json:"cves,omitempty"`
+
+	// timestamp in RFC3339 format (e.g. "2025-10-12T23:20:50.52Z") of the
+	// vulnerability assessment
+	Timestamp string `json:"timestamp,omitempty" validate:"timestamp"`
+}
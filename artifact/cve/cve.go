@@ -0,0 +1,212 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package cve
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// CVE identifier format (see https://www.cve.org/ResourcesSupport/Glossary)
+var cveID = regexp.MustCompile(`^CVE-[0-9]{4}-[0-9]{4,}$`)
+
+// Define the CVE handler
+type CVE struct{}
+
+// Register the handler for the CVE category
+func init() {
+	h := CVE{}
+	artifact.Add(&h, artifact.CVE)
+}
+
+func checkID(id string) error {
+	if !cveID.MatchString(id) {
+		return fmt.Errorf("%w: CVE identifier expected", artifact.ErrInvalidFormat)
+	}
+
+	return nil
+}
+
+func checkScore(score *float64) error {
+	if score != nil && (*score < 0 || *score > 10) {
+		return fmt.Errorf("%w: CVSS score between 0.0 and 10.0 expected", artifact.ErrInvalidFormat)
+	}
+
+	return nil
+}
+
+// Parse requirements for the CVE category
+func (h *CVE) ParseRequirements(jsonRequirements []byte) (interface{}, error) {
+	var r Requirements
+
+	if err := artifact.Unmarshal(jsonRequirements, &r); err != nil {
+		return nil, err
+	}
+
+	if err := checkScore(r.MaxUnfixedCVSS); err != nil {
+		return nil, artifact.AtPath("max_unfixed_cvss", err)
+	}
+
+	for i, id := range r.MustFix {
+		if err := checkID(id); err != nil {
+			return nil, artifact.AtPath(fmt.Sprintf("must_fix[%d]", i), err)
+		}
+	}
+
+	for i, id := range r.Forbidden {
+		if err := checkID(id); err != nil {
+			return nil, artifact.AtPath(fmt.Sprintf("forbidden[%d]", i), err)
+		}
+	}
+
+	return &r, nil
+}
+
+// Parse claims for the CVE category
+func (h *CVE) ParseClaims(jsonClaims []byte) (interface{}, error) {
+	var c Claims
+
+	if err := artifact.Unmarshal(jsonClaims, &c); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+
+	for i, v := range c.CVEs {
+		path := fmt.Sprintf("cves[%d]", i)
+
+		if err := checkID(v.ID); err != nil {
+			return nil, artifact.AtPath(path+".id", err)
+		}
+
+		if seen[v.ID] {
+			return nil, artifact.AtPath(path+".id", fmt.Errorf("%w: duplicate %s claim", artifact.ErrInvalidFormat, v.ID))
+		}
+
+		seen[v.ID] = true
+
+		switch v.Status {
+		case Affected, Fixed, NotAffected:
+		default:
+			return nil, artifact.AtPath(path+".status", fmt.Errorf("%w: %s, %s or %s expected", artifact.ErrInvalidFormat, Affected, Fixed, NotAffected))
+		}
+
+		if err := checkScore(v.CVSS); err != nil {
+			return nil, artifact.AtPath(path+".cvss", err)
+		}
+	}
+
+	return &c, nil
+}
+
+// Check matching between requirements and claims for the CVE category
+func (h *CVE) Check(require interface{}, claim interface{}) error {
+	res, err := h.Evaluate(require, claim)
+
+	if err != nil {
+		return err
+	}
+
+	return res.Err()
+}
+
+// Evaluate requirements against claims for the CVE category
+func (h *CVE) Evaluate(require interface{}, claim interface{}) (res artifact.Results, err error) {
+	if _, ok := require.(*Requirements); !ok {
+		return nil, fmt.Errorf("invalid·policy requirements for CVE")
+	}
+
+	if _, ok := claim.(*Claims); !ok {
+		return nil, fmt.Errorf("invalid·claims for CVE")
+	}
+
+	r := require.(*Requirements)
+	c := claim.(*Claims)
+
+	// evaluate all the supported policy requirements for CVE
+	if r.MaxUnfixedCVSS != nil {
+		res.Add("max_unfixed_cvss", checkMaxUnfixedCVSS(*r.MaxUnfixedCVSS, c.CVEs))
+	}
+
+	if len(r.MustFix) > 0 {
+		res.Add("must_fix", checkMustFix(r.MustFix, c.CVEs))
+	}
+
+	if len(r.Forbidden) > 0 {
+		res.Add("forbidden", checkForbidden(r.Forbidden, c.CVEs))
+	}
+
+	if r.MinTimestamp != "" {
+		res.Add("min_timestamp", artifact.CheckMinTimestamp(r.MinTimestamp, c.Timestamp))
+	}
+
+	return
+}
+
+// Return the claimed vulnerability with the given identifier, if any
+func find(claim []Vulnerability, id string) *Vulnerability {
+	for i, v := range claim {
+		if v.ID == id {
+			return &claim[i]
+		}
+	}
+
+	return nil
+}
+
+// Check that no affected vulnerability exceeds the maximum CVSS score,
+// affected vulnerabilities without score are considered critical
+func checkMaxUnfixedCVSS(require float64, claim []Vulnerability) error {
+	for _, v := range claim {
+		if v.Status != Affected {
+			continue
+		}
+
+		if v.CVSS == nil {
+			return fmt.Errorf("unfixed %s has no CVSS score", v.ID)
+		}
+
+		if *v.CVSS > require {
+			return fmt.Errorf("unfixed %s CVSS score %.1f does not met max unfixed CVSS requirement", v.ID, *v.CVSS)
+		}
+	}
+
+	return nil
+}
+
+// Check that the required vulnerabilities are claimed as fixed, or not
+// affecting the bundle
+func checkMustFix(require []string, claim []Vulnerability) error {
+	for _, id := range require {
+		v := find(claim, id)
+
+		if v == nil {
+			return fmt.Errorf("%s fix is not claimed", id)
+		}
+
+		if v.Status == Affected {
+			return fmt.Errorf("%s is not fixed", id)
+		}
+	}
+
+	return nil
+}
+
+// Check that the forbidden vulnerabilities are not claimed as affecting the
+// bundle
+func checkForbidden(require []string, claim []Vulnerability) error {
+	for _, id := range require {
+		if v := find(claim, id); v != nil && v.Status == Affected {
+			return fmt.Errorf("%s not allowed", id)
+		}
+	}
+
+	return nil
+}
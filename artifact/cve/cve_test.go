@@ -0,0 +1,165 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package cve
+
+import (
+	"testing"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+	"github.com/usbarmory/boot-transparency/artifact/artifacttest"
+)
+
+var testClaims = []byte(`{
+    "cves": [
+        {"id": "CVE-2025-38236", "status": "fixed", "cvss": 7.8, "cvss_vector": "CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H"},
+        {"id": "CVE-2024-1086", "status": "not_affected"},
+        {"id": "CVE-2025-21756", "status": "affected", "cvss": 4.4}
+    ],
+    "timestamp": "2025-10-21T12:00:00Z"
+}`)
+
+func TestCVEParseRequirements(t *testing.T) {
+	r := []byte(`{"max_unfixed_cvss": 0, "must_fix": ["CVE-2025-38236"], "forbidden": ["CVE-2025-21756"], "min_timestamp": "2025-10-01T00:00:00Z"}`)
+
+	h, err := artifact.GetHandler(artifact.CVE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requirements, err := h.ParseRequirements(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a zero maximum score is a valid requirement
+	if s := requirements.(*Requirements).MaxUnfixedCVSS; s == nil || *s != 0 {
+		t.Fatalf("unexpected max unfixed CVSS requirement: %v", s)
+	}
+}
+
+func TestNegativeCVEParseRequirements(t *testing.T) {
+	artifacttest.NegativeParseRequirements(t, artifact.CVE, []artifacttest.Invalid{
+		{JSON: `{"max_unfixed_cvss": 11}`, Path: "max_unfixed_cvss", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"max_unfixed_cvss": "7.0"}`, Path: "max_unfixed_cvss", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"must_fix": ["CVE-2025-38236", "cve-2025-1"]}`, Path: "must_fix[1]", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"forbidden": ["2025-21756"]}`, Path: "forbidden[0]", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"must_fixed": ["CVE-2025-38236"]}`, Path: "must_fixed", Err: artifact.ErrUnknownField},
+	})
+}
+
+func TestCVEParseClaims(t *testing.T) {
+	h, err := artifact.GetHandler(artifact.CVE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := h.ParseClaims(testClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c := claims.(*Claims); len(c.CVEs) != 3 || c.CVEs[1].CVSS != nil || *c.CVEs[2].CVSS != 4.4 {
+		t.Fatalf("unexpected claims: %+v", c)
+	}
+
+	// bundles without known vulnerabilities
+	if _, err = h.ParseClaims([]byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeCVEParseClaims(t *testing.T) {
+	artifacttest.NegativeParseClaims(t, artifact.CVE, []artifacttest.Invalid{
+		{JSON: `{"cves": [{"status": "fixed"}]}`, Path: "cves[0].id", Err: artifact.ErrMissingField},
+		{JSON: `{"cves": [{"id": "CVE-2025-38236"}]}`, Path: "cves[0].status", Err: artifact.ErrMissingField},
+		{JSON: `{"cves": [{"id": "CVE-25-38236", "status": "fixed"}]}`, Path: "cves[0].id", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"cves": [{"id": "CVE-2025-38236", "status": "patched"}]}`, Path: "cves[0].status", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"cves": [{"id": "CVE-2025-38236", "status": "fixed", "cvss": -1}]}`, Path: "cves[0].cvss", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"cves": [{"id": "CVE-2025-38236", "status": "fixed"}, {"id": "CVE-2025-38236", "status": "affected"}]}`, Path: "cves[1].id", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"cves": [], "timestamp": "2025-10-21"}`, Path: "timestamp", Err: artifact.ErrInvalidFormat},
+	})
+}
+
+func TestCVECheck(t *testing.T) {
+	r := []byte(`{"max_unfixed_cvss": 5.0, "must_fix": ["CVE-2025-38236", "CVE-2024-1086"], "forbidden": ["CVE-2023-0001"], "min_timestamp": "2025-10-01T00:00:00Z"}`)
+
+	h, err := artifact.GetHandler(artifact.CVE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedRequirements, err := h.ParseRequirements(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedClaims, err := h.ParseClaims(testClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = h.Check(parsedRequirements, parsedClaims); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeCVECheck(t *testing.T) {
+	artifacttest.NegativeCheck(t, artifact.CVE, testClaims, []string{
+		// the unfixed CVE-2025-21756 score exceeds the maximum one
+		`{"max_unfixed_cvss": 4.0}`,
+		// CVE-2025-21756 is not fixed
+		`{"must_fix": ["CVE-2025-21756"]}`,
+		// the CVE-2025-0001 fix is not claimed
+		`{"must_fix": ["CVE-2025-0001"]}`,
+		// CVE-2025-21756 affects the bundle
+		`{"forbidden": ["CVE-2025-21756"]}`,
+		// the vulnerability assessment is too old
+		`{"min_timestamp": "2025-11-01T00:00:00Z"}`,
+	})
+}
+
+func TestNegativeCVECheckUnscored(t *testing.T) {
+	r := []byte(`{"max_unfixed_cvss": 10.0}`)
+	c := []byte(`{"cves": [{"id": "CVE-2025-21756", "status": "affected"}]}`)
+
+	h, err := artifact.GetHandler(artifact.CVE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedRequirements, err := h.ParseRequirements(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedClaims, err := h.ParseClaims(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as unscored unfixed vulnerabilities are not allowed
+	if err = h.Check(parsedRequirements, parsedClaims); err == nil {
+		t.Fatal("unscored unfixed vulnerability has been accepted")
+	}
+}
+
+func TestCVEEvaluate(t *testing.T) {
+	r := []byte(`{"max_unfixed_cvss": 4.0, "must_fix": ["CVE-2025-38236"], "forbidden": ["CVE-2025-21756"], "min_timestamp": "2025-10-01T00:00:00Z"}`)
+
+	res := artifacttest.Evaluate(t, artifact.CVE, r, testClaims, []artifacttest.Outcome{
+		{Requirement: "max_unfixed_cvss", Passed: false},
+		{Requirement: "must_fix", Passed: true},
+		{Requirement: "forbidden", Passed: false},
+		{Requirement: "min_timestamp", Passed: true},
+	})
+
+	// error expected here as some requirements are not met
+	if err := res.Err(); err == nil {
+		t.Fatal("unmet requirements have been accepted")
+	}
+}
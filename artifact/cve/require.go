@@ -0,0 +1,27 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package cve
+
+// Supported requirements for CVE artifact
+type Requirements struct {
+	// maximum CVSS base score allowed for affected vulnerabilities, from
+	// 0.0 to 10.0. Affected vulnerabilities without score are not allowed.
+	MaxUnfixedCVSS *float64 `json:"max_unfixed_cvss,omitempty"`
+
+	// CVE identifiers of the vulnerabilities that must be claimed as
+	// fixed, or not affecting the bundle
+	MustFix []string `json:"must_fix,omitempty"`
+
+	// CVE identifiers of the vulnerabilities that must not affect the
+	// bundle, regardless of their score
+	Forbidden []string `json:"forbidden,omitempty"`
+
+	// required minimum timestamp in RFC3339 format of the vulnerability
+	// assessment
+	MinTimestamp string `json:"min_timestamp,omitempty" validate:"timestamp"`
+}
@@ -8,12 +8,15 @@
 package dtb
 
 import (
-	"encoding/json"
+	"encoding/binary"
 	"fmt"
 
 	"github.com/usbarmory/boot-transparency/artifact"
 )
 
+// Flattened Device Tree header magic
+const fdtMagic = 0xd00dfeed
+
 // Define the Dtb handler
 type Dtb struct{}
 
@@ -27,7 +30,7 @@ func init() {
 func (h *Dtb) ParseRequirements(jsonRequirements []byte) (interface{}, error) {
 	var r Requirements
 
-	if err := json.Unmarshal(jsonRequirements, &r); err != nil {
+	if err := artifact.Unmarshal(jsonRequirements, &r); err != nil {
 		return nil, err
 	}
 
@@ -38,81 +41,99 @@ func (h *Dtb) ParseRequirements(jsonRequirements []byte) (interface{}, error) {
 func (h *Dtb) ParseClaims(jsonClaims []byte) (interface{}, error) {
 	var c Claims
 
-	if err := json.Unmarshal(jsonClaims, &c); err != nil {
+	if err := artifact.Unmarshal(jsonClaims, &c); err != nil {
 		return nil, err
 	}
 
 	return &c, nil
 }
 
+// Extract claims for the Dtb category
+func (h *Dtb) ExtractClaims(f *artifact.File) (interface{}, error) {
+	if len(f.Data) < 4 || binary.BigEndian.Uint32(f.Data) != fdtMagic {
+		return nil, fmt.Errorf("invalid device tree blob")
+	}
+
+	c := &Claims{
+		FileName: f.Name,
+		Hash:     f.Hash,
+		Size:     uint64(len(f.Data)),
+	}
+
+	return c, nil
+}
+
 // Check matching between requirements and claims for the Dtb category
-func (h *Dtb) Check(require interface{}, claim interface{}) (err error) {
+func (h *Dtb) Check(require interface{}, claim interface{}) error {
+	res, err := h.Evaluate(require, claim)
+
+	if err != nil {
+		return err
+	}
+
+	return res.Err()
+}
+
+// Evaluate requirements against claims for the Dtb category
+func (h *Dtb) Evaluate(require interface{}, claim interface{}) (res artifact.Results, err error) {
 	if _, ok := require.(*Requirements); !ok {
-		return fmt.Errorf("invalid·policy requirements for Dtb")
+		return nil, fmt.Errorf("invalid·policy requirements for Dtb")
 	}
 
 	if _, ok := claim.(*Claims); !ok {
-		return fmt.Errorf("invalid·claims for Dtb")
+		return nil, fmt.Errorf("invalid·claims for Dtb")
 	}
 
 	r := require.(*Requirements)
 	c := claim.(*Claims)
 
-	// check all the supported policy requirements for Dtb
-	if err = artifact.CheckHash(r.Hash, c.Hash); err != nil {
-		return
+	// evaluate all the supported policy requirements for Dtb
+	if r.Hash != "" {
+		res.Add("hash", artifact.CheckHash(r.Hash, c.Hash))
 	}
 
-	if err = artifact.CheckMinVersion(r.MinVersion, c.Version); err != nil {
-		return
+	if r.MinVersion != "" {
+		res.Add("min_version", artifact.CheckMinVersion(r.MinVersion, c.Version))
 	}
 
-	if err = artifact.CheckMaxVersion(r.MaxVersion, c.Version); err != nil {
-		return
+	if r.MaxVersion != "" {
+		res.Add("max_version", artifact.CheckMaxVersion(r.MaxVersion, c.Version))
 	}
 
-	if r.Architecture != "" && r.Architecture != c.Architecture {
-		return fmt.Errorf("architecture %q does·not·met·requirement", c.Architecture)
+	if r.Architecture != "" {
+		res.Add("architecture", artifact.CheckArchitecture(r.Architecture, c.Architecture))
 	}
 
-	if err = artifact.CheckArrayInclusion(r.License, c.License); err != nil {
-		return fmt.Errorf("license requirement not met: %q", err)
+	if len(r.License) > 0 {
+		res.Add("license", artifact.CheckArrayInclusion(r.License, c.License))
 	}
 
-	if err = artifact.CheckMinTimestamp(r.MinTimestamp, c.Timestamp); err != nil {
-		return
+	if r.MinTimestamp != "" {
+		res.Add("min_timestamp", artifact.CheckMinTimestamp(r.MinTimestamp, c.Timestamp))
 	}
 
-	if err = artifact.CheckStringMatch(r.Metadata, c.Metadata); err != nil {
-		return fmt.Errorf("metadata matching requirement not met")
+	if r.Metadata != "" {
+		res.Add("metadata", artifact.CheckStringMatch(r.Metadata, c.Metadata))
 	}
 
 	for _, requireMetadata := range r.MetadataInclude {
-		if err = artifact.CheckStringInclude(requireMetadata, c.Metadata); err != nil {
-			return fmt.Errorf("metadata inclusion requirement not met: %q", err)
-		}
+		res.Add("metadata_include", artifact.CheckStringInclude(requireMetadata, c.Metadata))
 	}
 
 	for _, requireMetadata := range r.MetadataNotInclude {
-		if err = artifact.CheckStringNotInclude(requireMetadata, c.Metadata); err != nil {
-			return fmt.Errorf("metadata non-inclusion requirement not met: %q", err)
-		}
+		res.Add("metadata_not_include", artifact.CheckStringNotInclude(requireMetadata, c.Metadata))
 	}
 
-	if err = artifact.CheckStringMatch(r.Dts, c.Dts); err != nil {
-		return fmt.Errorf("dts matching requirement not met")
+	if r.Dts != "" {
+		res.Add("dts", artifact.CheckStringMatch(r.Dts, c.Dts))
 	}
 
 	for _, requireDts := range r.DtsInclude {
-		if err = artifact.CheckStringInclude(requireDts, c.Dts); err != nil {
-			return fmt.Errorf("dts inclusion requirement not met: %q", err)
-		}
+		res.Add("dts_include", artifact.CheckStringInclude(requireDts, c.Dts))
 	}
 
 	for _, requireDts := range r.DtsNotInclude {
-		if err := artifact.CheckStringNotInclude(requireDts, c.Dts); err != nil {
-			return fmt.Errorf("dts non-inclusion requirement not met: %q", err)
-		}
+		res.Add("dts_not_include", artifact.CheckStringNotInclude(requireDts, c.Dts))
 	}
 
 	return
@@ -0,0 +1,133 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package artifact
+
+import (
+	"bytes"
+	"debug/pe"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Define an artifact file, from which claims can be extracted
+type File struct {
+	// file name, as claimed in the statement
+	Name string
+
+	// file contents
+	Data []byte
+
+	// SHA-512 hash of the file contents, in hex format
+	Hash string
+}
+
+// Define an optional interface for artifact handlers supporting the
+// extraction of claims from artifact files.
+type Extractor interface {
+	// Return the claims extracted from a given artifact file, claims
+	// that cannot be extracted are left unset.
+	ExtractClaims(f *File) (interface{}, error)
+}
+
+// Return the claims extracted, by the registered artifact handler for a
+// given category, from an artifact file.
+//
+// Return error if:
+//   - the handler is not registered
+//   - the handler does not support claims extraction
+//   - the extraction fails
+func ExtractClaims(c uint, f *File) (interface{}, error) {
+	h, err := GetHandler(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	e, ok := h.(Extractor)
+
+	if !ok {
+		return nil, fmt.Errorf("claims extraction not supported for %d artifact category", c)
+	}
+
+	return e.ExtractClaims(f)
+}
+
+// Linux kernel release candidate suffix (e.g. "-rc1")
+var releaseCandidate = regexp.MustCompile(`^-rc([0-9]+)`)
+
+// Return the Semantic Versioning 2.0.0 form of a version string, if any.
+// Only the major.minor.patch part of the version, and any release
+// candidate suffix, is compared. A release candidate suffix is retained as
+// a pre-release, so that it precedes the final release, while any other
+// distribution suffix is retained as build metadata, so that it does not
+// affect version requirements (e.g. "6.14.0-rc1" is returned as
+// "v6.14.0-rc.1" and "6.14.0-29-generic" as "v6.14.0+29-generic").
+func SemanticVersion(version string) string {
+	version = strings.TrimPrefix(version, "v")
+
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		core, suffix := version[:i], version[i:]
+
+		// the numeric identifier ensures that rc10 follows rc9
+		if m := releaseCandidate.FindStringSubmatch(suffix); m != nil {
+			core += "-rc." + m[1]
+			suffix = suffix[len(m[0]):]
+		}
+
+		version = core
+
+		if len(suffix) > 1 {
+			version += "+" + strings.ReplaceAll(suffix[1:], "+", ".")
+		}
+	}
+
+	version = "v" + version
+
+	if !semver.IsValid(version) {
+		return ""
+	}
+
+	return version
+}
+
+// Return the architecture, using the EFI specification vocabulary, of a
+// PE/COFF binary.
+//
+// Return error if:
+//   - the binary parsing fails
+//   - the machine type is not supported
+func PEArchitecture(data []byte) (string, error) {
+	f, err := pe.NewFile(bytes.NewReader(data))
+
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	switch f.Machine {
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return "IA32", nil
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return "x64", nil
+	case pe.IMAGE_FILE_MACHINE_IA64:
+		return "IA64", nil
+	case pe.IMAGE_FILE_MACHINE_ARM, pe.IMAGE_FILE_MACHINE_ARMNT, pe.IMAGE_FILE_MACHINE_THUMB:
+		return "ARM", nil
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return "AA64", nil
+	case pe.IMAGE_FILE_MACHINE_RISCV64:
+		return "RISCV64", nil
+	case pe.IMAGE_FILE_MACHINE_LOONGARCH64:
+		return "LOONGARCH64", nil
+	}
+
+	return "", fmt.Errorf("unsupported PE machine type %#x", f.Machine)
+}
@@ -10,13 +10,13 @@ package initrd
 // Supported policy requirements for Initrd artifact
 type Requirements struct {
 	// required SHA-512 hash of the artifact
-	Hash string `json:"hash,omitempty"`
+	Hash string `json:"hash,omitempty" validate:"hash"`
 
 	// required minimum version, expressed using Semantic Versioning 2.0.0 (see semver.org)
-	MinVersion string `json:"min_version,omitempty"`
+	MinVersion string `json:"min_version,omitempty" validate:"version"`
 
 	// maximum allowed version, expressed using Semantic Versioning 2.0.0 (see semver.org)
-	MaxVersion string `json:"max_version,omitempty"`
+	MaxVersion string `json:"max_version,omitempty" validate:"version"`
 
 	// allowed architecture, the architecture vocabulary is the one defined by the EFI specification (i.e. IA32, x64, IA64, ARM, AA64, ...)
 	Architecture string `json:"architecture,omitempty"`
@@ -32,7 +32,7 @@ type Requirements struct {
 
 	// allow only artifacts where the claimed timestamp is more recent than the one specified here
 	// in RFC3339 format (e.g. "1985-04-12T23:20:50.52Z")
-	MinTimestamp string `json:"min_timestamp,omitempty"`
+	MinTimestamp string `json:"min_timestamp,omitempty" validate:"timestamp"`
 
 	// allow only artifacts that are claiming a given set of metadata (i.e. match check)
 	Metadata string `json:"metadata,omitempty"`
@@ -12,11 +12,14 @@ type Claims struct {
 	// filename of the artifact
 	FileName string `json:"file_name,omitempty"`
 
-	// SHA-512 hash of the artifact
-	Hash string `json:"hash,omitempty"`
+	// SHA-512 hash of the artifact, mandatory
+	Hash string `json:"hash,omitempty" validate:"required,hash"`
+
+	// size of the artifact, in bytes
+	Size uint64 `json:"size,omitempty"`
 
 	// artifact version, using Semantic Versioning 2.0.0 (see semver.org)
-	Version string `json:"version,omitempty"`
+	Version string `json:"version,omitempty" validate:"version"`
 
 	// the architecture vocabulary is the one defined by the EFI specification (i.e. IA32, x64, IA64, ARM, AA64, ...)
 	Architecture string `json:"architecture,omitempty"`
@@ -33,7 +36,7 @@ type Claims struct {
 	// timestamp in RFC3339 format (e.g. "1985-04-12T23:20:50.52Z"): "2025-10-12T23:20:50.52Z"
 	// the claimant can decide to use this field to expose any relevant timestamp for the artifact
 	// (e.g. the releasing date, tha building time, ...) that should be verified by the boot policy
-	Timestamp string `json:"timestamp,omitempty"`
+	Timestamp string `json:"timestamp,omitempty" validate:"timestamp"`
 
 	// public URLs to download the source code required to build the artifact
 	SourceURLs []string `json:"source_urls,omitempty"`
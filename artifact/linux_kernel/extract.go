@@ -0,0 +1,119 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package linux_kernel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// x86 boot protocol (see Documentation/arch/x86/boot.rst)
+const (
+	x86HeaderMagic   = 0x53726448 // "HdrS"
+	x86XLoadFlags    = 0x236
+	x86KernelVersion = 0x20e
+	xlfKernel64      = 1 << 0
+)
+
+// arm64 and RISC-V image headers (see Documentation/arch/arm64/booting.rst)
+const (
+	imageMagicOffset = 0x38
+	arm64ImageMagic  = 0x644d5241 // "ARM\x64"
+	riscvImageMagic  = 0x05435352 // "RSC\x05"
+)
+
+// arm zImage header (see arch/arm/boot/compressed/head.S)
+const (
+	zImageMagicOffset = 0x24
+	zImageMagic       = 0x016f2818
+)
+
+// banner of uncompressed kernel images
+var linuxBanner = []byte("Linux version ")
+
+func u16(data []byte, off int) uint16 {
+	if len(data) < off+2 {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint16(data[off:])
+}
+
+func u32(data []byte, off int) uint32 {
+	if len(data) < off+4 {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint32(data[off:])
+}
+
+// Return the NUL, or space, terminated string at a given offset
+func cstring(data []byte, off int) string {
+	if off < 0 || off >= len(data) {
+		return ""
+	}
+
+	s := data[off:]
+
+	if i := bytes.IndexAny(s, "\x00 \n"); i >= 0 {
+		s = s[:i]
+	}
+
+	return string(s)
+}
+
+// Return the architecture and the version, when available, of a kernel image
+func parseImage(data []byte) (arch string, version string) {
+	switch {
+	case u32(data, 0x202) == x86HeaderMagic:
+		arch = "IA32"
+
+		// protocol 2.12 and later
+		if u16(data, 0x206) >= 0x020c && u16(data, x86XLoadFlags)&xlfKernel64 != 0 {
+			arch = "x64"
+		}
+
+		if off := int(u16(data, x86KernelVersion)); off != 0 {
+			version = cstring(data, off+0x200)
+		}
+	case u32(data, imageMagicOffset) == arm64ImageMagic:
+		arch = "AA64"
+	case u32(data, imageMagicOffset) == riscvImageMagic:
+		arch = "RISCV64"
+	case u32(data, zImageMagicOffset) == zImageMagic:
+		arch = "ARM"
+	}
+
+	if version == "" {
+		if i := bytes.Index(data, linuxBanner); i >= 0 {
+			version = cstring(data, i+len(linuxBanner))
+		}
+	}
+
+	return arch, strings.TrimSpace(version)
+}
+
+// Extract claims for the LinuxKernel category, the architecture and version
+// are extracted from the x86 boot protocol setup header, the arm64, arm or
+// RISC-V image headers and the banner of uncompressed images.
+func (h *LinuxKernel) ExtractClaims(f *artifact.File) (interface{}, error) {
+	arch, version := parseImage(f.Data)
+
+	c := &Claims{
+		FileName:     f.Name,
+		Hash:         f.Hash,
+		Size:         uint64(len(f.Data)),
+		Version:      artifact.SemanticVersion(version),
+		Architecture: arch,
+	}
+
+	return c, nil
+}
@@ -8,6 +8,7 @@
 package linux_kernel
 
 import (
+	"encoding/binary"
 	"testing"
 
 	"github.com/usbarmory/boot-transparency/artifact"
@@ -103,3 +104,157 @@ func TestNegativeLinuxKernelCheck(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestLinuxKernelEvaluate(t *testing.T) {
+	r := []byte(`{"min_version": "v6.14.0-28-generic", "architecture":"arm64", "license": ["GPL-2.0-only"]}`)
+
+	c := []byte(`{"file_name": "vmlinuz-6.14.0-29-generic", "hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59", "version":"v6.14.0-29-generic" ,"architecture":"x64", "tainted": true, "license": ["GPL-2.0-only"]}`)
+
+	h, err := artifact.GetHandler(artifact.LinuxKernel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requirements, err := h.ParseRequirements(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := h.ParseClaims(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := h.Evaluate(requirements, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the tainted requirement is always evaluated
+	expected := map[string]bool{
+		"min_version":  true,
+		"architecture": false,
+		"tainted":      false,
+		"license":      true,
+	}
+
+	if len(res) != len(expected) {
+		t.Fatalf("unexpected number of results: %d", len(res))
+	}
+
+	for _, r := range res {
+		if passed, ok := expected[r.Requirement]; !ok || passed != (r.Err == nil) {
+			t.Fatalf("unexpected result for %s requirement: %v", r.Requirement, r.Err)
+		}
+	}
+
+	// error expected here as some requirements are not met
+	if err = res.Err(); err == nil {
+		t.Fatal("unmet requirements have been accepted")
+	}
+}
+
+func TestLinuxKernelExtractClaims(t *testing.T) {
+	// x86 bzImage setup header
+	bzImage := make([]byte, 0x400)
+	binary.LittleEndian.PutUint32(bzImage[0x202:], x86HeaderMagic)
+	binary.LittleEndian.PutUint16(bzImage[0x206:], 0x020f)
+	binary.LittleEndian.PutUint16(bzImage[x86KernelVersion:], 0x100)
+	binary.LittleEndian.PutUint16(bzImage[x86XLoadFlags:], xlfKernel64)
+	copy(bzImage[0x300:], "6.14.0-29-generic (buildd@lcy02-amd64-093) #29~24.04.1-Ubuntu SMP\x00")
+
+	// arm64 uncompressed Image
+	image := make([]byte, 0x100)
+	binary.LittleEndian.PutUint32(image[imageMagicOffset:], arm64ImageMagic)
+	image = append(image, "Linux version 6.12.47-0-lts (buildozer@build-3-22-aarch64) #1-Alpine SMP\x00"...)
+
+	for _, v := range []struct {
+		data         []byte
+		version      string
+		architecture string
+	}{
+		{bzImage, "v6.14.0+29-generic", "x64"},
+		{image, "v6.12.47+0-lts", "AA64"},
+		{[]byte("unknown"), "", ""},
+	} {
+		h := &LinuxKernel{}
+		f := &artifact.File{Name: "vmlinuz", Data: v.data, Hash: "8ba6bc3d"}
+
+		c, err := h.ExtractClaims(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims := c.(*Claims)
+
+		if claims.FileName != f.Name || claims.Hash != f.Hash || claims.Size != uint64(len(v.data)) {
+			t.Fatalf("unexpected file claims: %+v", claims)
+		}
+
+		if claims.Version != v.version || claims.Architecture != v.architecture {
+			t.Fatalf("unexpected claims: %+v, expected %s %s", claims, v.version, v.architecture)
+		}
+	}
+}
+
+func TestLinuxKernelExtractVersion(t *testing.T) {
+	older := artifact.SemanticVersion("6.14.0-29-generic")
+	newer := artifact.SemanticVersion("6.14.1-3-generic")
+
+	// the distribution suffix does not affect the version comparison
+	if err := artifact.CheckMinVersion("v6.14.0", older); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := artifact.CheckMinVersion(older, newer); err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the claimed version is older than the
+	// required one
+	if err := artifact.CheckMinVersion(newer, older); err == nil {
+		t.Fatalf("version %s has been accepted as newer than %s", older, newer)
+	}
+}
+
+func TestNegativeLinuxKernelCheckReleaseCandidate(t *testing.T) {
+	bzImage := make([]byte, 0x400)
+	binary.LittleEndian.PutUint32(bzImage[0x202:], x86HeaderMagic)
+	binary.LittleEndian.PutUint16(bzImage[0x206:], 0x020f)
+	binary.LittleEndian.PutUint16(bzImage[x86KernelVersion:], 0x100)
+	copy(bzImage[0x300:], "6.14.0-rc1-00012-g0123456789ab (user@host) #1 SMP\x00")
+
+	h, err := artifact.GetHandler(artifact.LinuxKernel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := (&LinuxKernel{}).ExtractClaims(&artifact.File{Name: "vmlinuz", Data: bzImage})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := claims.(*Claims).Version; v != "v6.14.0-rc.1+00012-g0123456789ab" {
+		t.Fatalf("unexpected version: %s", v)
+	}
+
+	for _, v := range []struct {
+		requirements string
+		passed       bool
+	}{
+		{`{"min_version": "v6.13.0"}`, true},
+		{`{"min_version": "v6.14.0-rc.1"}`, true},
+		// a release candidate precedes the final release
+		{`{"min_version": "v6.14.0"}`, false},
+		{`{"min_version": "v6.14.0-rc.10"}`, false},
+	} {
+		requirements, err := h.ParseRequirements([]byte(v.requirements))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = h.Check(requirements, claims); v.passed != (err == nil) {
+			t.Fatalf("unexpected result for %s: %v", v.requirements, err)
+		}
+	}
+}
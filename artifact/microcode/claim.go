@@ -0,0 +1,36 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package microcode
+
+// Define a microcode update, for a given processor signature
+type Update struct {
+	// processor signature (i.e. CPUID leaf 1 EAX), in hex format (e.g. 0x000906ea)
+	Signature string `json:"signature" validate:"required"`
+
+	// update revision, in hex format (e.g. 0x000000f8)
+	Revision string `json:"revision" validate:"required"`
+
+	// update release date, in YYYY-MM-DD format (e.g. 2024-02-05)
+	Date string `json:"date,omitempty"`
+}
+
+// Supported claims for Microcode artifact
+type Claims struct {
+	// filename of the artifact (e.g. kernel/x86/microcode/GenuineIntel.bin)
+	FileName string `json:"file_name,omitempty"`
+
+	// SHA-512 hash of the artifact, mandatory
+	Hash string `json:"hash,omitempty" validate:"required,hash"`
+
+	// processor vendor (i.e. intel or amd)
+	Vendor string `json:"vendor,omitempty"`
+
+	// microcode updates included in the artifact, the same update is
+	// listed once for each supported processor signature
+	Updates []Update `json:"updates,omitempty"`
+}
@@ -0,0 +1,233 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package microcode
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// Intel microcode update format (see Intel SDM Vol. 3A, 10.11.1)
+const (
+	intelHeaderSize    = 48
+	intelExtHeaderSize = 20
+	intelExtSigSize    = 12
+	// default data size, for updates with zero data and total size
+	intelDefaultData = 2000
+)
+
+// AMD microcode container format (see Linux arch/x86/kernel/cpu/microcode/amd.c)
+const (
+	amdMagic        = 0x00414d44
+	amdEquivTable   = 0
+	amdPatch        = 1
+	amdEquivSize    = 16
+	amdSectionSize  = 8
+	amdPatchMinSize = 32
+)
+
+// Return the YYYY-MM-DD date of a BCD encoded 0xMMDDYYYY date, as used by
+// both Intel and AMD microcode updates.
+func bcdDate(d uint32) (string, error) {
+	date := fmt.Sprintf("%04x-%02x-%02x", d&0xffff, d>>24, (d>>16)&0xff)
+
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return "", fmt.Errorf("invalid date %#08x", d)
+	}
+
+	return date, nil
+}
+
+func hex32(v uint32) string {
+	return fmt.Sprintf("0x%08x", v)
+}
+
+// Return the sum of the 32-bit words of an Intel update, which must be zero
+func intelChecksum(data []byte) (sum uint32) {
+	for i := 0; i+4 <= len(data); i += 4 {
+		sum += binary.LittleEndian.Uint32(data[i:])
+	}
+
+	return
+}
+
+// Parse concatenated Intel microcode updates, including the extended
+// signature tables.
+func parseIntel(data []byte) (updates []Update, err error) {
+	for len(data) > 0 {
+		if len(data) < intelHeaderSize {
+			return nil, errors.New("truncated header")
+		}
+
+		if binary.LittleEndian.Uint32(data[0:]) != 1 || binary.LittleEndian.Uint32(data[20:]) != 1 {
+			return nil, errors.New("unsupported header version")
+		}
+
+		revision := binary.LittleEndian.Uint32(data[4:])
+		signature := binary.LittleEndian.Uint32(data[12:])
+		// sizes are checked as uint64, so that they cannot overflow, or
+		// wrap as negative int values on 32-bit architectures
+		dataSize := uint64(binary.LittleEndian.Uint32(data[28:]))
+		totalSize := uint64(binary.LittleEndian.Uint32(data[32:]))
+
+		if dataSize == 0 {
+			dataSize = intelDefaultData
+			totalSize = intelDefaultData + intelHeaderSize
+		}
+
+		if totalSize < dataSize+intelHeaderSize || totalSize%4 != 0 || totalSize > uint64(len(data)) {
+			return nil, errors.New("invalid update size")
+		}
+
+		if intelChecksum(data[:totalSize]) != 0 {
+			return nil, errors.New("invalid update checksum")
+		}
+
+		date, err := bcdDate(binary.LittleEndian.Uint32(data[8:]))
+
+		if err != nil {
+			return nil, err
+		}
+
+		signatures := []uint32{signature}
+
+		if ext := data[intelHeaderSize+dataSize : totalSize]; len(ext) > 0 {
+			if len(ext) < intelExtHeaderSize {
+				return nil, errors.New("truncated extended signature table")
+			}
+
+			count := uint64(binary.LittleEndian.Uint32(ext[0:]))
+
+			if uint64(len(ext)) < intelExtHeaderSize+count*intelExtSigSize {
+				return nil, errors.New("truncated extended signature table")
+			}
+
+			for i := uint64(0); i < count; i++ {
+				signatures = append(signatures, binary.LittleEndian.Uint32(ext[intelExtHeaderSize+i*intelExtSigSize:]))
+			}
+		}
+
+		for _, sig := range signatures {
+			updates = append(updates, Update{
+				Signature: hex32(sig),
+				Revision:  hex32(revision),
+				Date:      date,
+			})
+		}
+
+		data = data[totalSize:]
+	}
+
+	return
+}
+
+// Parse concatenated AMD microcode containers, the patch processor
+// signatures are resolved through the container equivalence table.
+func parseAMD(data []byte) (updates []Update, err error) {
+	for len(data) > 0 {
+		if len(data) < 4+amdSectionSize || binary.LittleEndian.Uint32(data[0:]) != amdMagic {
+			return nil, errors.New("invalid container magic")
+		}
+
+		if binary.LittleEndian.Uint32(data[4:]) != amdEquivTable {
+			return nil, errors.New("missing equivalence table")
+		}
+
+		size := uint64(binary.LittleEndian.Uint32(data[8:]))
+		data = data[4+amdSectionSize:]
+
+		if size > uint64(len(data)) {
+			return nil, errors.New("truncated equivalence table")
+		}
+
+		// equivalence identifier to processor signatures
+		equiv := make(map[uint16][]uint32)
+
+		for table := data[:size]; len(table) >= amdEquivSize; table = table[amdEquivSize:] {
+			sig := binary.LittleEndian.Uint32(table[0:])
+
+			if sig == 0 {
+				break
+			}
+
+			id := binary.LittleEndian.Uint16(table[12:])
+			equiv[id] = append(equiv[id], sig)
+		}
+
+		data = data[size:]
+
+		for len(data) >= amdSectionSize && binary.LittleEndian.Uint32(data[0:]) == amdPatch {
+			size = uint64(binary.LittleEndian.Uint32(data[4:]))
+			data = data[amdSectionSize:]
+
+			if size < amdPatchMinSize || size > uint64(len(data)) {
+				return nil, errors.New("invalid patch size")
+			}
+
+			patch := data[:size]
+			data = data[size:]
+
+			date, err := bcdDate(binary.LittleEndian.Uint32(patch[0:]))
+
+			if err != nil {
+				return nil, err
+			}
+
+			revision := binary.LittleEndian.Uint32(patch[4:])
+			id := binary.LittleEndian.Uint16(patch[24:])
+
+			if len(equiv[id]) == 0 {
+				return nil, fmt.Errorf("patch %#08x processor is not in the equivalence table", revision)
+			}
+
+			for _, sig := range equiv[id] {
+				updates = append(updates, Update{
+					Signature: hex32(sig),
+					Revision:  hex32(revision),
+					Date:      date,
+				})
+			}
+		}
+	}
+
+	return
+}
+
+// Extract claims for the Microcode category, the updates are parsed either
+// from an AMD container, when starting with its magic, or from Intel
+// microcode updates.
+func (h *Microcode) ExtractClaims(f *artifact.File) (interface{}, error) {
+	var err error
+
+	c := &Claims{
+		FileName: f.Name,
+		Hash:     f.Hash,
+	}
+
+	if len(f.Data) >= 4 && binary.LittleEndian.Uint32(f.Data) == amdMagic {
+		c.Vendor = AMD
+		c.Updates, err = parseAMD(f.Data)
+	} else {
+		c.Vendor = Intel
+		c.Updates, err = parseIntel(f.Data)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s microcode: %v", c.Vendor, err)
+	}
+
+	if len(c.Updates) == 0 {
+		return nil, fmt.Errorf("invalid microcode: no update found")
+	}
+
+	return c, nil
+}
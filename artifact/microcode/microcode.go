@@ -0,0 +1,212 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package microcode
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// Processor vendors
+const (
+	Intel = "intel"
+	AMD   = "amd"
+)
+
+// Define the Microcode handler
+type Microcode struct{}
+
+// Register the handler for the Microcode category
+func init() {
+	h := Microcode{}
+	artifact.Add(&h, artifact.Microcode)
+}
+
+// Return the value of a 32-bit hex string, with 0x prefix (e.g. 0x000906ea)
+func parseHex(s string) (uint32, error) {
+	h, ok := strings.CutPrefix(s, "0x")
+
+	if !ok {
+		return 0, fmt.Errorf("%w: 0x prefixed hex value expected", artifact.ErrInvalidFormat)
+	}
+
+	v, err := strconv.ParseUint(h, 16, 32)
+
+	if err != nil {
+		return 0, fmt.Errorf("%w: 32-bit hex value expected", artifact.ErrInvalidFormat)
+	}
+
+	return uint32(v), nil
+}
+
+func checkVendor(vendor string) error {
+	switch vendor {
+	case "", Intel, AMD:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s or %s expected", artifact.ErrInvalidFormat, Intel, AMD)
+}
+
+// Parse requirements for the Microcode category
+func (h *Microcode) ParseRequirements(jsonRequirements []byte) (interface{}, error) {
+	var r Requirements
+
+	if err := artifact.Unmarshal(jsonRequirements, &r); err != nil {
+		return nil, err
+	}
+
+	if err := checkVendor(r.Vendor); err != nil {
+		return nil, artifact.AtPath("vendor", err)
+	}
+
+	for sig, rev := range r.MinRevision {
+		if _, err := parseHex(sig); err != nil {
+			return nil, artifact.AtPath("min_revision", err)
+		}
+
+		if _, err := parseHex(rev); err != nil {
+			return nil, artifact.AtPath("min_revision."+sig, err)
+		}
+	}
+
+	return &r, nil
+}
+
+// Parse claims for the Microcode category
+func (h *Microcode) ParseClaims(jsonClaims []byte) (interface{}, error) {
+	var c Claims
+
+	if err := artifact.Unmarshal(jsonClaims, &c); err != nil {
+		return nil, err
+	}
+
+	if err := checkVendor(c.Vendor); err != nil {
+		return nil, artifact.AtPath("vendor", err)
+	}
+
+	for i, u := range c.Updates {
+		path := fmt.Sprintf("updates[%d]", i)
+
+		if _, err := parseHex(u.Signature); err != nil {
+			return nil, artifact.AtPath(path+".signature", err)
+		}
+
+		if _, err := parseHex(u.Revision); err != nil {
+			return nil, artifact.AtPath(path+".revision", err)
+		}
+
+		if u.Date == "" {
+			continue
+		}
+
+		if _, err := time.Parse(time.DateOnly, u.Date); err != nil {
+			return nil, artifact.AtPath(path+".date", fmt.Errorf("%w: YYYY-MM-DD date expected", artifact.ErrInvalidFormat))
+		}
+	}
+
+	return &c, nil
+}
+
+// Check matching between requirements and claims for the Microcode category
+func (h *Microcode) Check(require interface{}, claim interface{}) error {
+	res, err := h.Evaluate(require, claim)
+
+	if err != nil {
+		return err
+	}
+
+	return res.Err()
+}
+
+// Evaluate requirements against claims for the Microcode category
+func (h *Microcode) Evaluate(require interface{}, claim interface{}) (res artifact.Results, err error) {
+	if _, ok := require.(*Requirements); !ok {
+		return nil, fmt.Errorf("invalid·policy requirements for Microcode")
+	}
+
+	if _, ok := claim.(*Claims); !ok {
+		return nil, fmt.Errorf("invalid·claims for Microcode")
+	}
+
+	r := require.(*Requirements)
+	c := claim.(*Claims)
+
+	// evaluate all the supported policy requirements for Microcode
+	if r.Hash != "" {
+		res.Add("hash", artifact.CheckHash(r.Hash, c.Hash))
+	}
+
+	if r.Vendor != "" {
+		res.Add("vendor", checkVendorMatch(r.Vendor, c.Vendor))
+	}
+
+	// sort the signatures for a deterministic evaluation order
+	signatures := make([]string, 0, len(r.MinRevision))
+
+	for sig := range r.MinRevision {
+		signatures = append(signatures, sig)
+	}
+
+	sort.Strings(signatures)
+
+	for _, sig := range signatures {
+		res.Add("min_revision", checkMinRevision(sig, r.MinRevision[sig], c.Updates))
+	}
+
+	return
+}
+
+func checkVendorMatch(require string, claim string) error {
+	if require != claim {
+		return fmt.Errorf("vendor %q does not met requirements", claim)
+	}
+
+	return nil
+}
+
+// Check that all the updates for a given processor signature have at least
+// the required revision
+func checkMinRevision(signature string, require string, claim []Update) error {
+	sig, err := parseHex(signature)
+
+	if err != nil {
+		return err
+	}
+
+	minRevision, err := parseHex(require)
+
+	if err != nil {
+		return err
+	}
+
+	found := false
+
+	for _, u := range claim {
+		if s, err := parseHex(u.Signature); err != nil || s != sig {
+			continue
+		}
+
+		found = true
+
+		if rev, err := parseHex(u.Revision); err != nil || rev < minRevision {
+			return fmt.Errorf("%s revision %s does not met min revision requirement", signature, u.Revision)
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("%s update is not claimed", signature)
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package microcode
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+	"github.com/usbarmory/boot-transparency/artifact/artifacttest"
+)
+
+var testClaims = []byte(`{
+    "file_name": "kernel/x86/microcode/GenuineIntel.bin",
+    "hash": "` + artifacttest.Hash + `",
+    "vendor": "intel",
+    "updates": [
+        {"signature": "0x000906ea", "revision": "0x000000f8", "date": "2024-02-05"},
+        {"signature": "0x000906eb", "revision": "0x000000f8", "date": "2024-02-05"},
+        {"signature": "0x000b0671", "revision": "0x00000129", "date": "2025-02-06"}
+    ]
+}`)
+
+func TestMicrocodeParseRequirements(t *testing.T) {
+	r := []byte(`{"vendor": "intel", "min_revision": {"0x000906ea": "0x000000f8", "0x000b0671": "0x00000129"}}`)
+
+	h, err := artifact.GetHandler(artifact.Microcode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.ParseRequirements(r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeMicrocodeParseRequirements(t *testing.T) {
+	artifacttest.NegativeParseRequirements(t, artifact.Microcode, []artifacttest.Invalid{
+		{JSON: `{"vendor": "via"}`, Path: "vendor", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"min_revision": {"000906ea": "0x000000f8"}}`, Path: "min_revision", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"min_revision": {"0x000906ea": "0x1000000f8"}}`, Path: "min_revision.0x000906ea", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"min_revision": {"0x000906ea": 248}}`, Path: "min_revision.0x000906ea", Err: artifact.ErrInvalidFormat},
+	})
+}
+
+func TestMicrocodeParseClaims(t *testing.T) {
+	h, err := artifact.GetHandler(artifact.Microcode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.ParseClaims(testClaims); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeMicrocodeParseClaims(t *testing.T) {
+	artifacttest.NegativeParseClaims(t, artifact.Microcode, []artifacttest.Invalid{
+		{JSON: `{"hash": "` + artifacttest.Hash + `", "updates": [{"signature": "0x000906ea"}]}`, Path: "updates[0].revision", Err: artifact.ErrMissingField},
+		{JSON: `{"hash": "` + artifacttest.Hash + `", "updates": [{"signature": "906ea", "revision": "0xf8"}]}`, Path: "updates[0].signature", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"hash": "` + artifacttest.Hash + `", "updates": [{"signature": "0x000906ea", "revision": "0xf8", "date": "02/05/2024"}]}`, Path: "updates[0].date", Err: artifact.ErrInvalidFormat},
+	})
+}
+
+func TestMicrocodeCheck(t *testing.T) {
+	r := []byte(`{"hash": "` + artifacttest.Hash + `", "vendor": "intel", "min_revision": {"0x000906ea": "0xf0", "0x000B0671": "0x00000129"}}`)
+
+	h, err := artifact.GetHandler(artifact.Microcode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedRequirements, err := h.ParseRequirements(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedClaims, err := h.ParseClaims(testClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = h.Check(parsedRequirements, parsedClaims); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeMicrocodeCheck(t *testing.T) {
+	artifacttest.NegativeCheck(t, artifact.Microcode, testClaims, []string{
+		// the hash does not match
+		`{"hash": "` + artifacttest.Hash[:127] + `0"}`,
+		// the vendor does not match
+		`{"vendor": "amd"}`,
+		// the update revision is older than the required one
+		`{"min_revision": {"0x000906eb": "0x00000100"}}`,
+		// no update is claimed for the processor signature
+		`{"min_revision": {"0x000a0652": "0x000000f8"}}`,
+	})
+}
+
+func TestMicrocodeEvaluate(t *testing.T) {
+	r := []byte(`{"vendor": "intel", "min_revision": {"0x000906ea": "0x00000100", "0x000b0671": "0x00000129"}}`)
+
+	artifacttest.Evaluate(t, artifact.Microcode, r, testClaims, []artifacttest.Outcome{
+		{Requirement: "vendor", Passed: true},
+		{Requirement: "min_revision", Passed: false},
+		{Requirement: "min_revision", Passed: true},
+	})
+}
+
+// Return an Intel microcode update, with the given extended signatures
+func intelUpdate(signature uint32, revision uint32, date uint32, ext ...uint32) []byte {
+	const dataSize = 64
+
+	size := intelHeaderSize + dataSize
+
+	if len(ext) > 0 {
+		size += intelExtHeaderSize + len(ext)*intelExtSigSize
+	}
+
+	u := make([]byte, size)
+	binary.LittleEndian.PutUint32(u[0:], 1)
+	binary.LittleEndian.PutUint32(u[4:], revision)
+	binary.LittleEndian.PutUint32(u[8:], date)
+	binary.LittleEndian.PutUint32(u[12:], signature)
+	binary.LittleEndian.PutUint32(u[20:], 1)
+	binary.LittleEndian.PutUint32(u[28:], dataSize)
+	binary.LittleEndian.PutUint32(u[32:], uint32(size))
+
+	if len(ext) > 0 {
+		table := u[intelHeaderSize+dataSize:]
+		binary.LittleEndian.PutUint32(table, uint32(len(ext)))
+
+		for i, sig := range ext {
+			binary.LittleEndian.PutUint32(table[intelExtHeaderSize+i*intelExtSigSize:], sig)
+		}
+	}
+
+	// set the checksum for a zero sum of the update words
+	binary.LittleEndian.PutUint32(u[16:], -intelChecksum(u))
+
+	return u
+}
+
+// Return an AMD microcode container, with a patch for each given
+// processor signature
+func amdContainer(revision uint32, date uint32, signatures ...uint32) []byte {
+	c := binary.LittleEndian.AppendUint32(nil, amdMagic)
+	c = binary.LittleEndian.AppendUint32(c, amdEquivTable)
+	c = binary.LittleEndian.AppendUint32(c, uint32((len(signatures)+1)*amdEquivSize))
+
+	for i, sig := range signatures {
+		entry := make([]byte, amdEquivSize)
+		binary.LittleEndian.PutUint32(entry[0:], sig)
+		binary.LittleEndian.PutUint16(entry[12:], uint16(0xa000+i))
+		c = append(c, entry...)
+	}
+
+	// equivalence table terminator
+	c = append(c, make([]byte, amdEquivSize)...)
+
+	for i := range signatures {
+		patch := make([]byte, 64)
+		binary.LittleEndian.PutUint32(patch[0:], date)
+		binary.LittleEndian.PutUint32(patch[4:], revision+uint32(i))
+		binary.LittleEndian.PutUint16(patch[24:], uint16(0xa000+i))
+
+		c = binary.LittleEndian.AppendUint32(c, amdPatch)
+		c = binary.LittleEndian.AppendUint32(c, uint32(len(patch)))
+		c = append(c, patch...)
+	}
+
+	return c
+}
+
+func TestMicrocodeExtractClaimsIntel(t *testing.T) {
+	h := &Microcode{}
+
+	data := append(intelUpdate(0x000906ea, 0xf8, 0x02052024, 0x000906eb), intelUpdate(0x000b0671, 0x129, 0x02062025)...)
+
+	c, err := h.ExtractClaims(&artifact.File{Name: "GenuineIntel.bin", Data: data, Hash: artifacttest.Hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := c.(*Claims)
+
+	expected := []Update{
+		{Signature: "0x000906ea", Revision: "0x000000f8", Date: "2024-02-05"},
+		{Signature: "0x000906eb", Revision: "0x000000f8", Date: "2024-02-05"},
+		{Signature: "0x000b0671", Revision: "0x00000129", Date: "2025-02-06"},
+	}
+
+	if claims.Vendor != Intel || !slices.Equal(claims.Updates, expected) {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	// error expected here as the update checksum is not valid
+	data[intelHeaderSize] ^= 1
+
+	if _, err = h.ExtractClaims(&artifact.File{Data: data}); err == nil {
+		t.Fatal("claims have been extracted from an invalid update")
+	}
+
+	// error expected here as the update is truncated
+	if _, err = h.ExtractClaims(&artifact.File{Data: intelUpdate(0x000906ea, 0xf8, 0x02052024)[:100]}); err == nil {
+		t.Fatal("claims have been extracted from a truncated update")
+	}
+}
+
+func TestMicrocodeExtractClaimsAMD(t *testing.T) {
+	h := &Microcode{}
+
+	data := append(amdContainer(0x0a20102b, 0x03082024, 0x00a20f12, 0x00a50f00), amdContainer(0x0b404023, 0x04152025, 0x00b40f40)...)
+
+	c, err := h.ExtractClaims(&artifact.File{Name: "AuthenticAMD.bin", Data: data, Hash: artifacttest.Hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := c.(*Claims)
+
+	expected := []Update{
+		{Signature: "0x00a20f12", Revision: "0x0a20102b", Date: "2024-03-08"},
+		{Signature: "0x00a50f00", Revision: "0x0a20102c", Date: "2024-03-08"},
+		{Signature: "0x00b40f40", Revision: "0x0b404023", Date: "2025-04-15"},
+	}
+
+	if claims.Vendor != AMD || !slices.Equal(claims.Updates, expected) {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	// error expected here as the patch processor is not in the equivalence table
+	data = amdContainer(0x0a20102b, 0x03082024, 0x00a20f12)
+	binary.LittleEndian.PutUint16(data[len(data)-64+24:], 0xb000)
+
+	if _, err = h.ExtractClaims(&artifact.File{Data: data}); err == nil {
+		t.Fatal("claims have been extracted from an invalid container")
+	}
+}
+
+func TestNegativeMicrocodeExtractClaimsSize(t *testing.T) {
+	h := &Microcode{}
+
+	// Return an Intel update with a word set at the given offset, and a
+	// valid checksum
+	intel := func(off int, v uint32) []byte {
+		u := intelUpdate(0x000906ea, 0xf8, 0x02052024, 0x000906eb)
+		binary.LittleEndian.PutUint32(u[off:], v)
+		binary.LittleEndian.PutUint32(u[16:], 0)
+		binary.LittleEndian.PutUint32(u[16:], -intelChecksum(u))
+		return u
+	}
+
+	// Return an AMD container with a word set at the given offset
+	amd := func(off int, v uint32) []byte {
+		c := amdContainer(0x0a20102b, 0x03082024, 0x00a20f12)
+		binary.LittleEndian.PutUint32(c[off:], v)
+		return c
+	}
+
+	for _, data := range [][]byte{
+		// data size
+		intel(28, 0xffffffff),
+		// total size
+		intel(32, 0xffffffff),
+		intel(32, 0xfffffffc),
+		// extended signature count
+		intel(intelHeaderSize+64, 0xffffffff),
+		// equivalence table size
+		amd(8, 0xffffffff),
+		// patch size
+		amd(4+amdSectionSize+2*amdEquivSize+4, 0xffffffff),
+	} {
+		// error expected here as the size exceeds the update
+		if _, err := h.ExtractClaims(&artifact.File{Data: data}); err == nil {
+			t.Fatal("claims have been extracted from an update with invalid size")
+		}
+	}
+}
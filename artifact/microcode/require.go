@@ -0,0 +1,23 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package microcode
+
+// Supported requirements for Microcode artifact
+type Requirements struct {
+	// required SHA-512 hash of the artifact
+	Hash string `json:"hash,omitempty" validate:"hash"`
+
+	// allowed processor vendor (i.e. intel or amd)
+	Vendor string `json:"vendor,omitempty"`
+
+	// required minimum update revision, in hex format, for each given
+	// processor signature (e.g. {"0x000906ea": "0x000000f8"}). All the
+	// updates for the signature must met the requirement, which fails
+	// when no update is claimed for it.
+	MinRevision map[string]string `json:"min_revision,omitempty"`
+}
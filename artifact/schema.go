@@ -0,0 +1,338 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package artifact
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// Schema validation errors
+var (
+	// the field is not defined for the JSON object
+	ErrUnknownField = errors.New("unknown field")
+	// the field is declared as mandatory but it is not set
+	ErrMissingField = errors.New("missing mandatory field")
+	// the field value does not match its declared type or format
+	ErrInvalidFormat = errors.New("invalid format")
+)
+
+// Define a schema validation error for a given field
+type FieldError struct {
+	// JSON path of the offending field (e.g. artifacts[0].claims.hash)
+	Path string
+	// validation error (e.g. ErrUnknownField)
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+func joinPath(path string, name string) string {
+	if path == "" || strings.HasPrefix(name, "[") {
+		return path + name
+	}
+
+	if name == "" {
+		return path
+	}
+
+	return path + "." + name
+}
+
+// Prepend a JSON path to the path of a field error, errors of any other
+// type are wrapped in a field error for the given path.
+func AtPath(path string, err error) error {
+	var fe *FieldError
+
+	if err == nil {
+		return nil
+	}
+
+	if errors.As(err, &fe) {
+		return &FieldError{Path: joinPath(path, fe.Path), Err: fe.Err}
+	}
+
+	return &FieldError{Path: path, Err: err}
+}
+
+// Parse serialized JSON into the value pointed by v, rejecting unknown
+// fields and validating the parsed value (see Validate()).
+//
+// The JSON object fields must match exactly the names set in the `json`
+// struct tags, json.RawMessage values are not inspected as their parsing
+// is deferred.
+//
+// Return error if:
+//   - the JSON is not valid
+//   - a field is not defined, or its value type does not match
+//   - the validation fails
+func Unmarshal(data []byte, v interface{}) (err error) {
+	if !json.Valid(data) {
+		// return the syntax error
+		return json.Unmarshal(data, v)
+	}
+
+	if err = checkFields(data, reflect.TypeOf(v), ""); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		return
+	}
+
+	return Validate(v)
+}
+
+// Return the struct fields, indexed by their JSON name
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+
+		switch {
+		case name == "-":
+			continue
+		case f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct:
+			// embedded struct fields are visited as promoted ones
+			continue
+		case name == "":
+			name = f.Name
+		}
+
+		fields[name] = f
+	}
+
+	return fields
+}
+
+func checkFields(data []byte, t reflect.Type, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == rawMessageType || string(data) == "null" {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return &FieldError{Path: path, Err: fmt.Errorf("%w: object expected", ErrInvalidFormat)}
+		}
+
+		fields := jsonFields(t)
+		names := make([]string, 0, len(obj))
+
+		for name := range obj {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			f, ok := fields[name]
+
+			if !ok {
+				return &FieldError{Path: joinPath(path, name), Err: ErrUnknownField}
+			}
+
+			if err := checkFields(obj[name], f.Type, joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var arr []json.RawMessage
+
+		if err := json.Unmarshal(data, &arr); err != nil {
+			return &FieldError{Path: path, Err: fmt.Errorf("%w: array expected", ErrInvalidFormat)}
+		}
+
+		for i, e := range arr {
+			if err := checkFields(e, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return &FieldError{Path: path, Err: fmt.Errorf("%w: object expected", ErrInvalidFormat)}
+		}
+
+		for name, e := range obj {
+			if err := checkFields(e, t.Elem(), joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := json.Unmarshal(data, reflect.New(t).Interface()); err != nil {
+			return &FieldError{Path: path, Err: fmt.Errorf("%w: %s expected", ErrInvalidFormat, t.Kind())}
+		}
+	}
+
+	return nil
+}
+
+// Validate a parsed value according to the rules, comma separated, set in
+// the `validate` tag of its struct fields:
+//   - required: the field must be set
+//   - hash: the string(s) must be an hex encoded SHA-512 hash
+//   - version: the string(s) must be a Semantic Versioning 2.0.0 version
+//   - timestamp: the string(s) must be an RFC3339 timestamp
+//   - hex: the string(s) must be hex encoded
+//
+// Return error if:
+//   - a mandatory field is not set
+//   - a field format is not valid
+func Validate(v interface{}) error {
+	return validate(reflect.ValueOf(v), "")
+}
+
+func validate(v reflect.Value, path string) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := jsonFields(v.Type())
+		names := make([]string, 0, len(fields))
+
+		for name := range fields {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			f := fields[name]
+			fv := v.FieldByIndex(f.Index)
+			fp := joinPath(path, name)
+
+			if rules := f.Tag.Get("validate"); rules != "" {
+				if err := validateRules(fv, fp, strings.Split(rules, ",")); err != nil {
+					return err
+				}
+			}
+
+			if err := validate(fv, fp); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		// raw JSON values are validated once parsed
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if err := validate(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRules(v reflect.Value, path string, rules []string) error {
+	for _, rule := range rules {
+		if rule == "required" {
+			if v.IsZero() || (v.Kind() == reflect.Slice && v.Len() == 0) {
+				return &FieldError{Path: path, Err: ErrMissingField}
+			}
+
+			continue
+		}
+
+		var values []string
+
+		switch {
+		case v.Kind() == reflect.String:
+			values = []string{v.String()}
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+			values = v.Interface().([]string)
+		default:
+			return &FieldError{Path: path, Err: fmt.Errorf("unsupported %s validation for %s", rule, v.Kind())}
+		}
+
+		for i, s := range values {
+			// unset values are only checked by the required rule
+			if s == "" {
+				continue
+			}
+
+			if err := validateFormat(rule, s); err != nil {
+				if v.Kind() == reflect.Slice {
+					return &FieldError{Path: fmt.Sprintf("%s[%d]", path, i), Err: err}
+				}
+
+				return &FieldError{Path: path, Err: err}
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateFormat(rule string, s string) error {
+	switch rule {
+	case "hash":
+		if h, err := hex.DecodeString(s); err != nil || len(h) != sha512.Size {
+			return fmt.Errorf("%w: SHA-512 hash expected", ErrInvalidFormat)
+		}
+	case "version":
+		if !semver.IsValid(s) {
+			return fmt.Errorf("%w: semantic version expected", ErrInvalidFormat)
+		}
+	case "timestamp":
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return fmt.Errorf("%w: RFC3339 timestamp expected", ErrInvalidFormat)
+		}
+	case "hex":
+		if _, err := hex.DecodeString(s); err != nil {
+			return fmt.Errorf("%w: hex encoding expected", ErrInvalidFormat)
+		}
+	default:
+		return fmt.Errorf("unsupported validation rule %q", rule)
+	}
+
+	return nil
+}
@@ -8,7 +8,6 @@
 package uefi_binary
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/usbarmory/boot-transparency/artifact"
@@ -27,7 +26,7 @@ func init() {
 func (h *UEFIBinary) ParseRequirements(jsonRequirements []byte) (interface{}, error) {
 	var r Requirements
 
-	if err := json.Unmarshal(jsonRequirements, &r); err != nil {
+	if err := artifact.Unmarshal(jsonRequirements, &r); err != nil {
 		return nil, err
 	}
 
@@ -38,65 +37,91 @@ func (h *UEFIBinary) ParseRequirements(jsonRequirements []byte) (interface{}, er
 func (h *UEFIBinary) ParseClaims(jsonClaims []byte) (interface{}, error) {
 	var c Claims
 
-	if err := json.Unmarshal(jsonClaims, &c); err != nil {
+	if err := artifact.Unmarshal(jsonClaims, &c); err != nil {
 		return nil, err
 	}
 
 	return &c, nil
 }
 
+// Extract claims for the UEFIBinary category, the architecture is extracted
+// from the PE/COFF header.
+func (h *UEFIBinary) ExtractClaims(f *artifact.File) (interface{}, error) {
+	arch, err := artifact.PEArchitecture(f.Data)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid UEFI binary: %v", err)
+	}
+
+	c := &Claims{
+		FileName:     f.Name,
+		Hash:         f.Hash,
+		Size:         uint64(len(f.Data)),
+		Architecture: arch,
+	}
+
+	return c, nil
+}
+
 // Check matching between requirements and claims for the UEFIBinary category
-func (h *UEFIBinary) Check(require interface{}, claim interface{}) (err error) {
+func (h *UEFIBinary) Check(require interface{}, claim interface{}) error {
+	res, err := h.Evaluate(require, claim)
+
+	if err != nil {
+		return err
+	}
+
+	return res.Err()
+}
+
+// Evaluate requirements against claims for the UEFIBinary category
+func (h *UEFIBinary) Evaluate(require interface{}, claim interface{}) (res artifact.Results, err error) {
 	if _, ok := require.(*Requirements); !ok {
-		return fmt.Errorf("invalid·policy requirements for UEFIBinary")
+		return nil, fmt.Errorf("invalid·policy requirements for UEFIBinary")
 	}
 
 	if _, ok := claim.(*Claims); !ok {
-		return fmt.Errorf("invalid·claims for UEFIBinary")
+		return nil, fmt.Errorf("invalid·claims for UEFIBinary")
 	}
 
 	r := require.(*Requirements)
 	c := claim.(*Claims)
 
-	// check all the supported policy requirements for UEFIBinary
-	if err = artifact.CheckHash(r.Hash, c.Hash); err != nil {
-		return
+	// evaluate all the supported policy requirements for UEFIBinary
+	if r.Hash != "" {
+		res.Add("hash", artifact.CheckHash(r.Hash, c.Hash))
 	}
 
-	if err = artifact.CheckMinVersion(r.MinVersion, c.Version); err != nil {
-		return
+	if r.MinVersion != "" {
+		res.Add("min_version", artifact.CheckMinVersion(r.MinVersion, c.Version))
 	}
 
-	if err = artifact.CheckMaxVersion(r.MaxVersion, c.Version); err != nil {
-		return
+	if r.MaxVersion != "" {
+		res.Add("max_version", artifact.CheckMaxVersion(r.MaxVersion, c.Version))
 	}
 
-	if r.Architecture != "" && r.Architecture != c.Architecture {
-		return fmt.Errorf("architecture %q does·not·met·requirement", c.Architecture)
+	if r.Architecture != "" {
+		res.Add("architecture", artifact.CheckArchitecture(r.Architecture, c.Architecture))
 	}
 
-	if err = artifact.CheckArrayInclusion(r.License, c.License); err != nil {
-		return fmt.Errorf("license requirement not met: %q", err)
+	if len(r.License) > 0 {
+		res.Add("license", artifact.CheckArrayInclusion(r.License, c.License))
 	}
 
-	if err = artifact.CheckMinTimestamp(r.MinTimestamp, c.Timestamp); err != nil {
-		return
+	if r.MinTimestamp != "" {
+		res.Add("min_timestamp", artifact.CheckMinTimestamp(r.MinTimestamp, c.Timestamp))
 	}
 
-	if err = artifact.CheckStringMatch(r.Metadata, c.Metadata); err != nil {
-		return fmt.Errorf("metadata matching requirement not met")
+	if r.Metadata != "" {
+		res.Add("metadata", artifact.CheckStringMatch(r.Metadata, c.Metadata))
 	}
 
 	for _, requireMetadata := range r.MetadataInclude {
-		if err = artifact.CheckStringInclude(requireMetadata, c.Metadata); err != nil {
-			return fmt.Errorf("metadata inclusion requirement not met: %q", err)
-		}
+		res.Add("metadata_include", artifact.CheckStringInclude(requireMetadata, c.Metadata))
 	}
 
 	for _, requireMetadata := range r.MetadataNotInclude {
-		if err = artifact.CheckStringNotInclude(requireMetadata, c.Metadata); err != nil {
-			return fmt.Errorf("metadata non-inclusion requirement not met: %q", err)
-		}
+		res.Add("metadata_not_include", artifact.CheckStringNotInclude(requireMetadata, c.Metadata))
 	}
 
 	return
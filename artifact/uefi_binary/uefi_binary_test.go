@@ -8,6 +8,8 @@
 package uefi_binary
 
 import (
+	"debug/pe"
+	"encoding/binary"
 	"testing"
 
 	"github.com/usbarmory/boot-transparency/artifact"
@@ -103,3 +105,38 @@ func TestNegativeUEFIBinaryCheck(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// Return a minimal PE/COFF image, without sections
+func peImage(machine uint16) []byte {
+	img := make([]byte, 0x80)
+	copy(img, "MZ")
+	binary.LittleEndian.PutUint32(img[0x3c:], 0x40)
+	copy(img[0x40:], "PE\x00\x00")
+	binary.LittleEndian.PutUint16(img[0x44:], machine)
+
+	return img
+}
+
+func TestUEFIBinaryExtractClaims(t *testing.T) {
+	h := &UEFIBinary{}
+
+	for machine, arch := range map[uint16]string{
+		pe.IMAGE_FILE_MACHINE_AMD64: "x64",
+		pe.IMAGE_FILE_MACHINE_ARM64: "AA64",
+		pe.IMAGE_FILE_MACHINE_I386:  "IA32",
+	} {
+		c, err := h.ExtractClaims(&artifact.File{Name: "EFI/BOOT/BOOTX64.EFI", Data: peImage(machine)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if claims := c.(*Claims); claims.Architecture != arch || claims.Size != 0x80 {
+			t.Fatalf("unexpected claims: %+v", claims)
+		}
+	}
+
+	// error expected here as the binary is not a PE/COFF image
+	if _, err := h.ExtractClaims(&artifact.File{Data: []byte("ELF")}); err == nil {
+		t.Fatal("claims have been extracted from an invalid binary")
+	}
+}
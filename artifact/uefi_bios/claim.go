@@ -10,7 +10,7 @@ package uefi_bios
 // Supported claims for UEFIBIOS artifact
 type Claims struct {
 	// UEFI revision, expressed using Semantic Versioning 2.0.0 (see semver.org)
-	UEFIRevision string `json:"uefi_revision,omitempty"`
+	UEFIRevision string `json:"uefi_revision,omitempty" validate:"version"`
 
 	// firmware vendor
 	FirmwareVendor string `json:"firmware_vendor,omitempty"`
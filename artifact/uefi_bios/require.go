@@ -10,10 +10,10 @@ package uefi_bios
 // Supported policy requirements for UEFIBIOS artifact
 type Requirements struct {
 	// required minimum UEFI revision, expressed using Semantic Versioning 2.0.0 (see semver.org)
-	MinUEFIRevision string `json:"min_uefi_revision,omitempty"`
+	MinUEFIRevision string `json:"min_uefi_revision,omitempty" validate:"version"`
 
 	// maximum allowed UEFI revision, expressed using Semantic Versioning 2.0.0 (see semver.org)
-	MaxUEFIRevision string `json:"max_uefi_revision,omitempty"`
+	MaxUEFIRevision string `json:"max_uefi_revision,omitempty" validate:"version"`
 
 	// allow the boot only on systems where the UEFI bios is from a certain list of trusted vendors
 	FirmwareVendor []string `json:"firmware_vendor,omitempty"`
@@ -8,7 +8,6 @@
 package uefi_bios
 
 import (
-	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -29,7 +28,7 @@ func init() {
 func (h *UEFIBIOS) ParseRequirements(jsonRequirements []byte) (interface{}, error) {
 	var r Requirements
 
-	if err := json.Unmarshal(jsonRequirements, &r); err != nil {
+	if err := artifact.Unmarshal(jsonRequirements, &r); err != nil {
 		return nil, err
 	}
 
@@ -40,7 +39,7 @@ func (h *UEFIBIOS) ParseRequirements(jsonRequirements []byte) (interface{}, erro
 func (h *UEFIBIOS) ParseClaims(jsonClaims []byte) (interface{}, error) {
 	var c Claims
 
-	if err := json.Unmarshal(jsonClaims, &c); err != nil {
+	if err := artifact.Unmarshal(jsonClaims, &c); err != nil {
 		return nil, err
 	}
 
@@ -48,60 +47,89 @@ func (h *UEFIBIOS) ParseClaims(jsonClaims []byte) (interface{}, error) {
 }
 
 // Check matching between requirements and claims for the UEFIBIOS category
-func (h *UEFIBIOS) Check(require interface{}, claim interface{}) (err error) {
+func (h *UEFIBIOS) Check(require interface{}, claim interface{}) error {
+	res, err := h.Evaluate(require, claim)
+
+	if err != nil {
+		return err
+	}
+
+	return res.Err()
+}
+
+// Evaluate requirements against claims for the UEFIBIOS category
+func (h *UEFIBIOS) Evaluate(require interface{}, claim interface{}) (res artifact.Results, err error) {
 	if _, ok := require.(*Requirements); !ok {
-		return fmt.Errorf("invalid·policy requirements for UEFIBIOS")
+		return nil, fmt.Errorf("invalid·policy requirements for UEFIBIOS")
 	}
 
 	if _, ok := claim.(*Claims); !ok {
-		return fmt.Errorf("invalid·claims for UEFIBIOS")
+		return nil, fmt.Errorf("invalid·claims for UEFIBIOS")
 	}
 
 	r := require.(*Requirements)
 	c := claim.(*Claims)
 
-	// check all the supported policy requirements for UEFIBIOS
-	if err = artifact.CheckMinVersion(r.MinUEFIRevision, c.UEFIRevision); err != nil {
-		return
+	// evaluate all the supported policy requirements for UEFIBIOS
+	if r.MinUEFIRevision != "" {
+		res.Add("min_uefi_revision", artifact.CheckMinVersion(r.MinUEFIRevision, c.UEFIRevision))
 	}
 
-	if err = artifact.CheckMaxVersion(r.MaxUEFIRevision, c.UEFIRevision); err != nil {
-		return
+	if r.MaxUEFIRevision != "" {
+		res.Add("max_uefi_revision", artifact.CheckMaxVersion(r.MaxUEFIRevision, c.UEFIRevision))
 	}
 
-	if !artifact.CheckElementInclusion(r.FirmwareVendor, c.FirmwareVendor) {
-		return fmt.Errorf("firmware vendor %q does not met requirements", c.FirmwareVendor)
-	}
+	res.Add("firmware_vendor", checkFirmwareVendor(r.FirmwareVendor, c.FirmwareVendor))
 
 	if r.MinFirmwareRevision != "" {
-		requireFirmwareRevision, err := strconv.ParseUint(strings.Trim(r.MinFirmwareRevision, "0x"), 16, 16)
-		if err != nil {
-			return fmt.Errorf("invalid min firmware revision requirement: %q", r.MinFirmwareRevision)
-		}
-		claimFirmwareRevision, err := strconv.ParseUint(strings.Trim(c.FirmwareRevision, "0x"), 16, 16)
-		if err != nil {
-			return fmt.Errorf("invalid firmware revision claim: %q", c.FirmwareRevision)
-		}
-
-		if claimFirmwareRevision < requireFirmwareRevision {
-			return fmt.Errorf("revision %q does not met min firmware revision requirement", claimFirmwareRevision)
-		}
+		res.Add("min_firmware_revision", checkMinFirmwareRevision(r.MinFirmwareRevision, c.FirmwareRevision))
 	}
 
 	if r.MaxFirmwareRevision != "" {
-		requireFirmwareRevision, err := strconv.ParseUint(r.MaxFirmwareRevision, 16, 16)
-		if err != nil {
-			return fmt.Errorf("invalid max firmware revision requirement: %q", r.MaxFirmwareRevision)
-		}
-		claimFirmwareRevision, err := strconv.ParseUint(c.FirmwareRevision, 16, 16)
-		if err != nil {
-			return fmt.Errorf("invalid firmware revision claim: %q", c.FirmwareRevision)
-		}
-
-		if claimFirmwareRevision > requireFirmwareRevision {
-			return fmt.Errorf("revision %q does not met max firmware revision requirement", claimFirmwareRevision)
-		}
+		res.Add("max_firmware_revision", checkMaxFirmwareRevision(r.MaxFirmwareRevision, c.FirmwareRevision))
 	}
 
 	return
 }
+
+func checkFirmwareVendor(require []string, claim string) error {
+	if !artifact.CheckElementInclusion(require, claim) {
+		return fmt.Errorf("firmware vendor %q does not met requirements", claim)
+	}
+
+	return nil
+}
+
+func checkMinFirmwareRevision(require string, claim string) error {
+	requireFirmwareRevision, err := strconv.ParseUint(strings.Trim(require, "0x"), 16, 16)
+	if err != nil {
+		return fmt.Errorf("invalid min firmware revision requirement: %q", require)
+	}
+	claimFirmwareRevision, err := strconv.ParseUint(strings.Trim(claim, "0x"), 16, 16)
+	if err != nil {
+		return fmt.Errorf("invalid firmware revision claim: %q", claim)
+	}
+
+	if claimFirmwareRevision < requireFirmwareRevision {
+		return fmt.Errorf("revision %q does not met min firmware revision requirement", claimFirmwareRevision)
+	}
+
+	return nil
+}
+
+func checkMaxFirmwareRevision(require string, claim string) error {
+	requireFirmwareRevision, err := strconv.ParseUint(require, 16, 16)
+	if err != nil {
+		return fmt.Errorf("invalid max firmware revision requirement: %q", require)
+	}
+	claimFirmwareRevision, err := strconv.ParseUint(claim, 16, 16)
+	if err != nil {
+		return fmt.Errorf("invalid firmware revision claim: %q", claim)
+	}
+
+	if claimFirmwareRevision > requireFirmwareRevision {
+		return fmt.Errorf("revision %q does not met max firmware revision requirement", claimFirmwareRevision)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package uki
+
+// SHA-512 hashes, in hex format, of the Unified Kernel Image PE sections
+type Sections struct {
+	// .linux section (i.e. kernel image)
+	Linux string `json:"linux,omitempty" validate:"hash"`
+
+	// .initrd section (i.e. initial ramdisk)
+	Initrd string `json:"initrd,omitempty" validate:"hash"`
+
+	// .cmdline section (i.e. kernel command line)
+	Cmdline string `json:"cmdline,omitempty" validate:"hash"`
+
+	// .osrel section (i.e. os-release file)
+	OSRel string `json:"osrel,omitempty" validate:"hash"`
+
+	// .dtb section (i.e. device tree blob)
+	DTB string `json:"dtb,omitempty" validate:"hash"`
+
+	// .uname section (i.e. kernel release)
+	Uname string `json:"uname,omitempty" validate:"hash"`
+
+	// .sbat section (i.e. SBAT revocation metadata)
+	SBAT string `json:"sbat,omitempty" validate:"hash"`
+}
+
+// Supported claims for UKI artifact
+type Claims struct {
+	// filename of the artifact
+	FileName string `json:"file_name,omitempty"`
+
+	// SHA-512 hash of the artifact, mandatory
+	Hash string `json:"hash,omitempty" validate:"required,hash"`
+
+	// size of the artifact, in bytes
+	Size uint64 `json:"size,omitempty"`
+
+	// SHA-512 hashes of the PE sections, unset for sections that are not
+	// present in the image
+	Sections Sections `json:"sections,omitempty"`
+
+	// kernel version, from the .uname section, using Semantic Versioning 2.0.0 (see semver.org)
+	Version string `json:"version,omitempty" validate:"version"`
+
+	// the architecture vocabulary is the one defined by the EFI specification (i.e. IA32, x64, IA64, ARM, AA64, ...)
+	Architecture string `json:"architecture,omitempty"`
+
+	// kernel command line, from the .cmdline section
+	Cmdline string `json:"cmdline,omitempty"`
+
+	// operating system identifier (i.e. ID), from the .osrel section
+	OSID string `json:"os_id,omitempty"`
+
+	// operating system version (i.e. VERSION_ID), from the .osrel section
+	OSVersion string `json:"os_version,omitempty"`
+
+	// license(s) associated to this artifact.
+	// Where applicable, licenses should be expressed as SPDX short-form IDs
+	// (e.g.MIT, GPL-2.0-or-later, BSD-2-Clause)
+	// https://spdx.github.io/spdx-spec/v2.3/SPDX-license-list/
+	License []string `json:"license,omitempty"`
+
+	// timestamp in RFC3339 format (e.g. "1985-04-12T23:20:50.52Z"): "2025-10-12T23:20:50.52Z"
+	// the claimant can decide to use this field to expose any relevant timestamp for the artifact
+	// (e.g. the releasing date, tha building time, ...) that should be verified by the boot policy
+	Timestamp string `json:"timestamp,omitempty" validate:"timestamp"`
+}
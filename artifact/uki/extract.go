@@ -0,0 +1,111 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package uki
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha512"
+	"debug/pe"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// Return the contents of a PE section, trimmed to its virtual size as
+// the section raw data is padded to the file alignment.
+func sectionData(s *pe.Section) ([]byte, error) {
+	data, err := s.Data()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.VirtualSize != 0 && int(s.VirtualSize) < len(data) {
+		data = data[:s.VirtualSize]
+	}
+
+	return data, nil
+}
+
+// Return the value of a given os-release field (see os-release(5))
+func osRelease(data []byte, key string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+
+		if !ok || k != key {
+			continue
+		}
+
+		return strings.Trim(v, `"'`)
+	}
+
+	return ""
+}
+
+// Extract claims for the UKI category, the section hashes, kernel version,
+// command line and operating system are extracted from the PE sections of
+// the systemd-stub Unified Kernel Image.
+func (h *UKI) ExtractClaims(f *artifact.File) (interface{}, error) {
+	arch, err := artifact.PEArchitecture(f.Data)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid UKI: %v", err)
+	}
+
+	img, err := pe.NewFile(bytes.NewReader(f.Data))
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid UKI: %v", err)
+	}
+	defer img.Close()
+
+	c := &Claims{
+		FileName:     f.Name,
+		Hash:         f.Hash,
+		Size:         uint64(len(f.Data)),
+		Architecture: arch,
+	}
+
+	for _, s := range img.Sections {
+		hash := c.Sections.get(strings.TrimPrefix(s.Name, "."))
+
+		if !strings.HasPrefix(s.Name, ".") || hash == nil {
+			continue
+		}
+
+		data, err := sectionData(s)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid UKI %s section: %v", s.Name, err)
+		}
+
+		sum := sha512.Sum512(data)
+		*hash = hex.EncodeToString(sum[:])
+
+		switch s.Name {
+		case ".uname":
+			c.Version = artifact.SemanticVersion(strings.TrimSpace(string(bytes.TrimRight(data, "\x00"))))
+		case ".cmdline":
+			c.Cmdline = strings.TrimSpace(string(bytes.TrimRight(data, "\x00")))
+		case ".osrel":
+			c.OSID = osRelease(data, "ID")
+			c.OSVersion = osRelease(data, "VERSION_ID")
+		}
+	}
+
+	if c.Sections.Linux == "" {
+		return nil, fmt.Errorf("invalid UKI: .linux section not found")
+	}
+
+	return c, nil
+}
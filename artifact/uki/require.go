@@ -0,0 +1,56 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package uki
+
+// Supported requirements for UKI artifact
+type Requirements struct {
+	// required SHA-512 hash of the artifact
+	Hash string `json:"hash,omitempty" validate:"hash"`
+
+	// required SHA-512 hashes of the PE sections, unset sections are not
+	// checked
+	Sections Sections `json:"sections,omitempty"`
+
+	// list of the PE sections (e.g. linux, initrd, cmdline) that are allowed
+	// to be present in the image
+	AllowedSections []string `json:"allowed_sections,omitempty"`
+
+	// required minimum kernel version, expressed using Semantic Versioning 2.0.0 (see semver.org)
+	MinVersion string `json:"min_version,omitempty" validate:"version"`
+
+	// maximum allowed kernel version, expressed using Semantic Versioning 2.0.0 (see semver.org)
+	MaxVersion string `json:"max_version,omitempty" validate:"version"`
+
+	// allowed architecture, the architecture vocabulary is the one defined by the EFI specification (i.e. IA32, x64, IA64, ARM, AA64, ...)
+	Architecture string `json:"architecture,omitempty"`
+
+	// allow only images embedding a given kernel command line (i.e. match check)
+	Cmdline string `json:"cmdline,omitempty"`
+
+	// allow only images embedding a kernel command line which is including
+	// all the string(s) specified here (i.e. AND of inclusion checks)
+	CmdlineInclude []string `json:"cmdline_include,omitempty"`
+
+	// allow only images embedding a kernel command line which is not
+	// including any of the string(s) specified here (i.e. AND of negated
+	// inclusion checks)
+	CmdlineNotInclude []string `json:"cmdline_not_include,omitempty"`
+
+	// list of allowed operating system identifiers (e.g. debian, fedora)
+	OSID []string `json:"os_id,omitempty"`
+
+	// list of allowed licenses.
+	// Where applicable licenses should be expressed as SPDX short-form IDs
+	// (e.g.MIT, GPL-2.0-or-later, BSD-2-Clause)
+	// https://spdx.github.io/spdx-spec/v2.3/SPDX-license-list/
+	License []string `json:"license,omitempty"`
+
+	// allow only artifacts where the claimed timestamp is more recent than the one specified here
+	// in RFC3339 format (e.g. "1985-04-12T23:20:50.52Z")
+	MinTimestamp string `json:"min_timestamp,omitempty" validate:"timestamp"`
+}
@@ -0,0 +1,205 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package uki
+
+import (
+	"fmt"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// Define the UKI handler
+type UKI struct{}
+
+// Register the handler for the UKI category
+func init() {
+	h := UKI{}
+	artifact.Add(&h, artifact.UKI)
+}
+
+// Define a PE section hash
+type section struct {
+	// section name, without leading dot (e.g. linux)
+	name string
+
+	// SHA-512 hash of the section, in hex format
+	hash string
+}
+
+// Return the section hashes, in the systemd-stub measurement order
+func (s *Sections) list() []section {
+	return []section{
+		{"linux", s.Linux},
+		{"osrel", s.OSRel},
+		{"cmdline", s.Cmdline},
+		{"initrd", s.Initrd},
+		{"uname", s.Uname},
+		{"sbat", s.SBAT},
+		{"dtb", s.DTB},
+	}
+}
+
+// Return the hash pointer for a given section name, nil if not supported
+func (s *Sections) get(name string) *string {
+	switch name {
+	case "linux":
+		return &s.Linux
+	case "initrd":
+		return &s.Initrd
+	case "cmdline":
+		return &s.Cmdline
+	case "osrel":
+		return &s.OSRel
+	case "dtb":
+		return &s.DTB
+	case "uname":
+		return &s.Uname
+	case "sbat":
+		return &s.SBAT
+	}
+
+	return nil
+}
+
+// Parse requirements for the UKI category
+func (h *UKI) ParseRequirements(jsonRequirements []byte) (interface{}, error) {
+	var r Requirements
+
+	if err := artifact.Unmarshal(jsonRequirements, &r); err != nil {
+		return nil, err
+	}
+
+	for i, name := range r.AllowedSections {
+		if r.Sections.get(name) == nil {
+			return nil, artifact.AtPath(fmt.Sprintf("allowed_sections[%d]", i), fmt.Errorf("%w: unsupported section %q", artifact.ErrInvalidFormat, name))
+		}
+	}
+
+	return &r, nil
+}
+
+// Parse claims for the UKI category
+func (h *UKI) ParseClaims(jsonClaims []byte) (interface{}, error) {
+	var c Claims
+
+	if err := artifact.Unmarshal(jsonClaims, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Check matching between requirements and claims for the UKI category
+func (h *UKI) Check(require interface{}, claim interface{}) error {
+	res, err := h.Evaluate(require, claim)
+
+	if err != nil {
+		return err
+	}
+
+	return res.Err()
+}
+
+// Evaluate requirements against claims for the UKI category
+func (h *UKI) Evaluate(require interface{}, claim interface{}) (res artifact.Results, err error) {
+	if _, ok := require.(*Requirements); !ok {
+		return nil, fmt.Errorf("invalid·policy requirements for UKI")
+	}
+
+	if _, ok := claim.(*Claims); !ok {
+		return nil, fmt.Errorf("invalid·claims for UKI")
+	}
+
+	r := require.(*Requirements)
+	c := claim.(*Claims)
+
+	// evaluate all the supported policy requirements for UKI
+	if r.Hash != "" {
+		res.Add("hash", artifact.CheckHash(r.Hash, c.Hash))
+	}
+
+	for _, s := range r.Sections.list() {
+		if s.hash != "" {
+			res.Add("sections."+s.name, checkSectionHash(s.name, s.hash, *c.Sections.get(s.name)))
+		}
+	}
+
+	if len(r.AllowedSections) > 0 {
+		res.Add("allowed_sections", checkAllowedSections(r.AllowedSections, &c.Sections))
+	}
+
+	if r.MinVersion != "" {
+		res.Add("min_version", artifact.CheckMinVersion(r.MinVersion, c.Version))
+	}
+
+	if r.MaxVersion != "" {
+		res.Add("max_version", artifact.CheckMaxVersion(r.MaxVersion, c.Version))
+	}
+
+	if r.Architecture != "" {
+		res.Add("architecture", artifact.CheckArchitecture(r.Architecture, c.Architecture))
+	}
+
+	if r.Cmdline != "" {
+		res.Add("cmdline", artifact.CheckStringMatch(r.Cmdline, c.Cmdline))
+	}
+
+	for _, requireCmdline := range r.CmdlineInclude {
+		res.Add("cmdline_include", artifact.CheckStringInclude(requireCmdline, c.Cmdline))
+	}
+
+	for _, requireCmdline := range r.CmdlineNotInclude {
+		res.Add("cmdline_not_include", artifact.CheckStringNotInclude(requireCmdline, c.Cmdline))
+	}
+
+	if len(r.OSID) > 0 {
+		res.Add("os_id", checkOSID(r.OSID, c.OSID))
+	}
+
+	if len(r.License) > 0 {
+		res.Add("license", artifact.CheckArrayInclusion(r.License, c.License))
+	}
+
+	if r.MinTimestamp != "" {
+		res.Add("min_timestamp", artifact.CheckMinTimestamp(r.MinTimestamp, c.Timestamp))
+	}
+
+	return
+}
+
+func checkSectionHash(name string, require string, claim string) error {
+	if claim == "" {
+		return fmt.Errorf(".%s section is not present", name)
+	}
+
+	return artifact.CheckHash(require, claim)
+}
+
+func checkAllowedSections(require []string, claim *Sections) error {
+	var present []string
+
+	for _, s := range claim.list() {
+		if s.hash != "" {
+			present = append(present, s.name)
+		}
+	}
+
+	if err := artifact.CheckArrayInclusion(require, present); err != nil {
+		return fmt.Errorf("section %v", err)
+	}
+
+	return nil
+}
+
+func checkOSID(require []string, claim string) error {
+	if !artifact.CheckElementInclusion(require, claim) {
+		return fmt.Errorf("operating system %q does not met requirements", claim)
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package uki
+
+import (
+	"crypto/sha512"
+	"debug/pe"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+	"github.com/usbarmory/boot-transparency/artifact/artifacttest"
+)
+
+var testClaims = []byte(`{
+    "file_name": "EFI/Linux/debian-6.14.0-29-generic.efi",
+    "hash": "` + artifacttest.Hash + `",
+    "sections": {"linux": "` + artifacttest.Hash + `", "initrd": "` + artifacttest.Hash + `", "cmdline": "` + artifacttest.Hash + `"},
+    "version": "v6.14.0-29-generic",
+    "architecture": "x64",
+    "cmdline": "root=/dev/sda1 ro lockdown=integrity",
+    "os_id": "debian",
+    "os_version": "13"
+}`)
+
+func TestUKIParseRequirements(t *testing.T) {
+	r := []byte(`{"sections": {"linux": "` + artifacttest.Hash + `"}, "allowed_sections": ["linux", "initrd", "cmdline"], "min_version": "v6.14.0", "os_id": ["debian"]}`)
+
+	h, err := artifact.GetHandler(artifact.UKI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.ParseRequirements(r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeUKIParseRequirements(t *testing.T) {
+	artifacttest.NegativeParseRequirements(t, artifact.UKI, []artifacttest.Invalid{
+		{JSON: `{"sections": {"linux": "8ba6bc3d"}}`, Path: "sections.linux", Err: artifact.ErrInvalidFormat},
+		{JSON: `{"sections": {"kernel": "` + artifacttest.Hash + `"}}`, Path: "sections.kernel", Err: artifact.ErrUnknownField},
+		{JSON: `{"allowed_sections": ["linux", ".initrd"]}`, Path: "allowed_sections[1]", Err: artifact.ErrInvalidFormat},
+	})
+}
+
+func TestUKIParseClaims(t *testing.T) {
+	h, err := artifact.GetHandler(artifact.UKI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.ParseClaims(testClaims); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeUKIParseClaims(t *testing.T) {
+	c := []byte(`{"hash": "` + artifacttest.Hash + `", "sections": {"osrel": "8ba6bc3d"}}`)
+
+	h, err := artifact.GetHandler(artifact.UKI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected: the section hash is not valid
+	if _, err := h.ParseClaims(c); !errors.Is(err, artifact.ErrInvalidFormat) {
+		t.Fatal(err)
+	}
+}
+
+func TestUKICheck(t *testing.T) {
+	r := []byte(`{"sections": {"linux": "` + artifacttest.Hash + `", "cmdline": "` + artifacttest.Hash + `"}, "allowed_sections": ["linux", "initrd", "cmdline", "osrel"], "min_version": "v6.14.0-28-generic", "architecture": "x64", "cmdline_include": ["lockdown=integrity"], "cmdline_not_include": ["init="], "os_id": ["debian"]}`)
+
+	h, err := artifact.GetHandler(artifact.UKI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedRequirements, err := h.ParseRequirements(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedClaims, err := h.ParseClaims(testClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = h.Check(parsedRequirements, parsedClaims); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeUKICheck(t *testing.T) {
+	otherHash := artifacttest.Hash[:127] + "0"
+
+	artifacttest.NegativeCheck(t, artifact.UKI, testClaims, []string{
+		// the .linux section hash does not match
+		`{"sections": {"linux": "` + otherHash + `"}}`,
+		// the .dtb section is not present
+		`{"sections": {"dtb": "` + artifacttest.Hash + `"}}`,
+		// the .initrd section is not allowed
+		`{"allowed_sections": ["linux", "cmdline"]}`,
+		// the command line does not match
+		`{"cmdline": "root=/dev/sda1 ro"}`,
+		// the command line includes a forbidden parameter
+		`{"cmdline_not_include": ["lockdown="]}`,
+		// the operating system is not allowed
+		`{"os_id": ["fedora"]}`,
+	})
+}
+
+func TestUKIEvaluate(t *testing.T) {
+	r := []byte(`{"sections": {"linux": "` + artifacttest.Hash + `", "sbat": "` + artifacttest.Hash + `"}, "allowed_sections": ["linux"], "max_version": "v6.14.0-30-generic"}`)
+
+	artifacttest.Evaluate(t, artifact.UKI, r, testClaims, []artifacttest.Outcome{
+		{Requirement: "sections.linux", Passed: true},
+		{Requirement: "sections.sbat", Passed: false},
+		{Requirement: "allowed_sections", Passed: false},
+		{Requirement: "max_version", Passed: true},
+	})
+}
+
+// Return a minimal PE/COFF image, with the given sections
+func ukiImage(machine uint16, sections [][2]string) []byte {
+	const dataOffset = 0x200
+
+	img := make([]byte, dataOffset)
+	copy(img, "MZ")
+	binary.LittleEndian.PutUint32(img[0x3c:], 0x40)
+	copy(img[0x40:], "PE\x00\x00")
+	binary.LittleEndian.PutUint16(img[0x44:], machine)
+	binary.LittleEndian.PutUint16(img[0x46:], uint16(len(sections)))
+
+	for i, s := range sections {
+		hdr := img[0x58+i*40:]
+		copy(hdr, s[0])
+
+		// pad the raw data to a 16 bytes file alignment
+		raw := make([]byte, (len(s[1])+15)&^15)
+		copy(raw, s[1])
+
+		binary.LittleEndian.PutUint32(hdr[8:], uint32(len(s[1])))
+		binary.LittleEndian.PutUint32(hdr[16:], uint32(len(raw)))
+		binary.LittleEndian.PutUint32(hdr[20:], uint32(len(img)))
+
+		img = append(img, raw...)
+	}
+
+	return img
+}
+
+func sum(data string) string {
+	h := sha512.Sum512([]byte(data))
+	return hex.EncodeToString(h[:])
+}
+
+func TestUKIExtractClaims(t *testing.T) {
+	h := &UKI{}
+
+	img := ukiImage(pe.IMAGE_FILE_MACHINE_AMD64, [][2]string{
+		{".osrel", "NAME=\"Debian GNU/Linux\"\nID=debian\nVERSION_ID=\"13\"\n"},
+		{".cmdline", "root=/dev/sda1 ro lockdown=integrity\n\x00"},
+		{".uname", "6.14.0-29-generic"},
+		{".linux", "linux kernel image"},
+		{".initrd", "initial ramdisk"},
+		{".text", "systemd-stub"},
+	})
+
+	c, err := h.ExtractClaims(&artifact.File{Name: "EFI/Linux/debian.efi", Data: img, Hash: artifacttest.Hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := c.(*Claims)
+
+	if claims.Architecture != "x64" || claims.Version != "v6.14.0+29-generic" || claims.Cmdline != "root=/dev/sda1 ro lockdown=integrity" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	if claims.OSID != "debian" || claims.OSVersion != "13" {
+		t.Fatalf("unexpected os-release claims: %+v", claims)
+	}
+
+	// section hashes must not include the file alignment padding
+	if claims.Sections.Linux != sum("linux kernel image") || claims.Sections.Initrd != sum("initial ramdisk") || claims.Sections.DTB != "" {
+		t.Fatalf("unexpected section hashes: %+v", claims.Sections)
+	}
+
+	// error expected here as the image does not include a kernel
+	if _, err = h.ExtractClaims(&artifact.File{Data: ukiImage(pe.IMAGE_FILE_MACHINE_AMD64, [][2]string{{".text", "stub"}})}); err == nil {
+		t.Fatal("claims have been extracted from an image without kernel")
+	}
+
+	// error expected here as the binary is not a PE/COFF image
+	if _, err = h.ExtractClaims(&artifact.File{Data: []byte("ELF")}); err == nil {
+		t.Fatal("claims have been extracted from an invalid binary")
+	}
+}
@@ -12,8 +12,11 @@ type Claims struct {
 	// filename of the artifact
 	FileName string `json:"file_name"`
 
-	// SHA-512 hash of the artifact
-	Hash string `json:"hash"`
+	// SHA-512 hash of the artifact, mandatory
+	Hash string `json:"hash" validate:"required,hash"`
+
+	// size of the artifact, in bytes
+	Size uint64 `json:"size,omitempty"`
 
 	// artifact version
 	Version string `json:"version,omitempty"`
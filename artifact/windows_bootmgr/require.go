@@ -10,11 +10,11 @@ package windows_bootmgr
 // Supported policy requirements for WindowsBootMgr artifact
 type Requirements struct {
 	// required SHA-512 hash of the artifact
-	Hash string `json:"hash,omitempty"`
+	Hash string `json:"hash,omitempty" validate:"hash"`
 
 	// required minimum version
-	MinVersion string `json:"min_version,omitempty"`
+	MinVersion string `json:"min_version,omitempty" validate:"version"`
 
 	// maximum allowed version
-	MaxVersion string `json:"max_version,omitempty"`
+	MaxVersion string `json:"max_version,omitempty" validate:"version"`
 }
@@ -8,7 +8,6 @@
 package windows_bootmgr
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/usbarmory/boot-transparency/artifact"
@@ -27,7 +26,7 @@ func init() {
 func (h *WindowsBootMgr) ParseRequirements(jsonRequirements []byte) (interface{}, error) {
 	var r Requirements
 
-	if err := json.Unmarshal(jsonRequirements, &r); err != nil {
+	if err := artifact.Unmarshal(jsonRequirements, &r); err != nil {
 		return nil, err
 	}
 
@@ -38,38 +37,64 @@ func (h *WindowsBootMgr) ParseRequirements(jsonRequirements []byte) (interface{}
 func (h *WindowsBootMgr) ParseClaims(jsonClaims []byte) (interface{}, error) {
 	var c Claims
 
-	if err := json.Unmarshal(jsonClaims, &c); err != nil {
+	if err := artifact.Unmarshal(jsonClaims, &c); err != nil {
 		return nil, err
 	}
 
 	return &c, nil
 }
 
+// Extract claims for the WindowsBootMgr category
+func (h *WindowsBootMgr) ExtractClaims(f *artifact.File) (interface{}, error) {
+	if _, err := artifact.PEArchitecture(f.Data); err != nil {
+		return nil, fmt.Errorf("invalid Windows boot manager: %v", err)
+	}
+
+	c := &Claims{
+		FileName: f.Name,
+		Hash:     f.Hash,
+		Size:     uint64(len(f.Data)),
+	}
+
+	return c, nil
+}
+
 // Check matching between requirements and claims for the WindowsBootMgr category
-func (h *WindowsBootMgr) Check(require interface{}, claim interface{}) (err error) {
+func (h *WindowsBootMgr) Check(require interface{}, claim interface{}) error {
+	res, err := h.Evaluate(require, claim)
+
+	if err != nil {
+		return err
+	}
+
+	return res.Err()
+}
+
+// Evaluate requirements against claims for the WindowsBootMgr category
+func (h *WindowsBootMgr) Evaluate(require interface{}, claim interface{}) (res artifact.Results, err error) {
 	if _, ok := require.(*Requirements); !ok {
-		return fmt.Errorf("invalid·policy requirements for WindowsBootMgr")
+		return nil, fmt.Errorf("invalid·policy requirements for WindowsBootMgr")
 	}
 
 	if _, ok := claim.(*Claims); !ok {
-		return fmt.Errorf("invalid·claims for WindowsBootMgr")
+		return nil, fmt.Errorf("invalid·claims for WindowsBootMgr")
 	}
 
 	r := require.(*Requirements)
 	c := claim.(*Claims)
 
-	// check all the supported policy requirements for WindowsBootMgr
-	if err = artifact.CheckHash(r.Hash, c.Hash); err != nil {
-		return
+	// evaluate all the supported policy requirements for WindowsBootMgr
+	if r.Hash != "" {
+		res.Add("hash", artifact.CheckHash(r.Hash, c.Hash))
 	}
 
 	// FIXME: windows boot manager does not use semantic versioning
-	if err = artifact.CheckMinVersion(r.MinVersion, c.Version); err != nil {
-		return
+	if r.MinVersion != "" {
+		res.Add("min_version", artifact.CheckMinVersion(r.MinVersion, c.Version))
 	}
 
-	if err = artifact.CheckMaxVersion(r.MaxVersion, c.Version); err != nil {
-		return
+	if r.MaxVersion != "" {
+		res.Add("max_version", artifact.CheckMaxVersion(r.MaxVersion, c.Version))
 	}
 
 	return
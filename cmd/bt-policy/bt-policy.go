@@ -13,6 +13,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"time"
 
 	"github.com/pborman/getopt/v2"
 	"github.com/usbarmory/boot-transparency/policy"
@@ -22,6 +23,10 @@ import (
 type CheckSettings struct {
 	policyFile          string
 	signedStatementFile string
+	time                string
+	device              statement.Device
+	revocationFiles     []string
+	report              bool
 }
 
 type ParseSettings struct {
@@ -31,7 +36,11 @@ type ParseSettings struct {
 func (s *CheckSettings) parse(args []string) {
 	const usage = `
 Check a given signed statement against a boot-transparency policy,
-the result is printed to stdout.
+the result is printed to stdout. The statement validity period, when
+required by the policy, is checked against the current time unless a
+different time is provided. The statement target, when required by the
+policy, is checked against the given device identity. The statement is
+refused if revoked by any of the given signed revocation statements.
 `
 	help := false
 
@@ -40,6 +49,14 @@ the result is printed to stdout.
 
 	set.FlagLong(&s.policyFile, "policy-file", 'p', "Boot-transparency policy file", "policy-file").Mandatory()
 	set.FlagLong(&s.signedStatementFile, "signed-statement", 's', "Signed statement file", "signed-statement-file").Mandatory()
+	set.FlagLong(&s.time, "time", 't', "Evaluation time, in RFC3339 format", "timestamp")
+	set.FlagLong(&s.device.Product, "product", 0, "Device product identifier", "product")
+	set.FlagLong(&s.device.Compatible, "compatible", 0, "Device tree board compatible string, can be repeated", "compatible")
+	set.FlagLong(&s.device.Machine, "machine", 0, "Device machine type (e.g. x64, AA64)", "machine")
+	set.FlagLong(&s.device.DMIVendor, "dmi-vendor", 0, "Device DMI system vendor", "vendor")
+	set.FlagLong(&s.device.DMIModel, "dmi-model", 0, "Device DMI system product name", "model")
+	set.FlagLong(&s.revocationFiles, "revocation", 'R', "Signed revocation statement file, can be repeated", "signed-statement-file")
+	set.FlagLong(&s.report, "report", 'r', "Print the outcome of each policy entry, artifact and requirement")
 	set.FlagLong(&help, "help", 'h', "Show usage message and exit")
 
 	err := set.Getopt(args[1:], nil)
@@ -169,7 +186,42 @@ Usage: bt-policy [--help]
 			log.Fatalf("read policy %q failed: %v", settings.policyFile, err)
 		}
 
-		if err = policy.Check(p, s); err != nil {
+		env := &policy.Environment{
+			Time: func() (time.Time, error) {
+				if settings.time == "" {
+					return time.Now(), nil
+				}
+
+				return time.Parse(time.RFC3339, settings.time)
+			},
+			Device: &settings.device,
+		}
+
+		for _, fileName := range settings.revocationFiles {
+			r, err := readStatement(fileName)
+			if err != nil {
+				log.Fatalf("statement read from %q failed: %v", fileName, err)
+			}
+
+			if r.Kind != statement.KindRevocation {
+				log.Fatalf("statement %q is not a revocation statement", fileName)
+			}
+
+			env.Revocations = append(env.Revocations, r)
+		}
+
+		d, err := policy.EvaluateWith(p, s, env)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if settings.report {
+			if report, err := json.MarshalIndent(d, "", "\t"); err == nil {
+				log.Println(string(report))
+			}
+		}
+
+		if err = d.Err(); err != nil {
 			log.Fatal(err)
 		} else {
 			log.Printf("signed statement is matching the policy")
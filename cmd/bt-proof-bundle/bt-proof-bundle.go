@@ -141,7 +141,7 @@ Usage: bt-proof [--help]
 		if jsonProofBundle, err := readFile(settings.proofBundleFile); err != nil {
 			log.Fatalf("read proof bundle %q failed: %v", settings.proofBundleFile, err)
 		} else {
-			e, err := transparency.GetEngine(uint(format))
+			e, err := transparency.NewEngine(uint(format), transparency.Config{})
 			if err != nil {
 				log.Fatalf("unsupported bundle format: %v", err)
 			}
@@ -154,7 +154,7 @@ Usage: bt-proof [--help]
 			}
 
 			// print result to stdout
-			fmt.Printf(string(parsed))
+			fmt.Print(string(parsed))
 		}
 	case "create":
 		var settings CreateSettings
@@ -209,7 +209,7 @@ Usage: bt-proof [--help]
 
 		// parse the preliminary bundle to ensure it is consistent
 		// with the transparency engine format
-		e, err := transparency.GetEngine(pb.Format)
+		e, err := transparency.NewEngine(pb.Format, transparency.Config{})
 		if err != nil {
 			log.Fatalf("unsupported bundle format: %v", err)
 		}
@@ -222,7 +222,7 @@ Usage: bt-proof [--help]
 		}
 
 		// print result to stdout
-		fmt.Printf(string(parsed))
+		fmt.Print(string(parsed))
 	}
 
 	os.Exit(0)
@@ -8,42 +8,102 @@
 package main
 
 import (
-	"encoding/base64"
+	"crypto"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/pborman/getopt/v2"
-	"sigsum.org/sigsum-go/pkg/crypto"
-	"sigsum.org/sigsum-go/pkg/key"
 
+	_ "github.com/usbarmory/boot-transparency/artifact/boot_entry"
+	_ "github.com/usbarmory/boot-transparency/artifact/cmdline"
+	_ "github.com/usbarmory/boot-transparency/artifact/cve"
+	_ "github.com/usbarmory/boot-transparency/artifact/dtb"
 	_ "github.com/usbarmory/boot-transparency/artifact/initrd"
 	_ "github.com/usbarmory/boot-transparency/artifact/linux_kernel"
+	_ "github.com/usbarmory/boot-transparency/artifact/microcode"
+	_ "github.com/usbarmory/boot-transparency/artifact/uefi_binary"
 	_ "github.com/usbarmory/boot-transparency/artifact/uefi_bios"
+	_ "github.com/usbarmory/boot-transparency/artifact/uki"
+	_ "github.com/usbarmory/boot-transparency/artifact/windows_bootmgr"
+	"github.com/usbarmory/boot-transparency/policy"
+	"github.com/usbarmory/boot-transparency/signature"
 	"github.com/usbarmory/boot-transparency/statement"
 )
 
 type VerifySettings struct {
 	publicKeyFile       string
 	signedStatementFile string
+	legacy              bool
 }
 
 type SignSettings struct {
 	privateKeyFile      string
+	agentKeyFile        string
+	helper              string
 	statementFile       string
 	signedStatementFile string
+	signatureFile       string
+	algorithm           string
+	legacy              bool
+}
+
+type AttachSettings struct {
+	statementFile       string
+	signatureFiles      []string
+	signedStatementFile string
+	legacy              bool
 }
 
 type ParseSettings struct {
 	statementFile string
 }
 
+type MergeSettings struct {
+	signedStatementFiles []string
+	outputFile           string
+	legacy               bool
+}
+
+type ListSettings struct {
+	signedStatementFile string
+	policyFile          string
+}
+
+type RemoveSettings struct {
+	signedStatementFile string
+	publicKeyFile       string
+	outputFile          string
+}
+
+type RevokeSettings struct {
+	revokedStatementFiles []string
+	digests               []string
+	supersededByFile      string
+	reason                string
+	description           string
+	statementFile         string
+}
+
+type CreateSettings struct {
+	artifacts     []string
+	root          string
+	description   string
+	version       string
+	notBefore     string
+	notAfter      string
+	target        statement.Target
+	statementFile string
+}
+
 func (s *VerifySettings) parse(args []string) {
 	const usage = `
-Verify an Ed25519 signature with a given signed statement.
+Verify a signature with a given signed statement.
 The signed statement, and the public key are provided as input files,
 the verification result is printed to stdout.
 `
@@ -53,7 +113,8 @@ the verification result is printed to stdout.
 	set.SetProgram(args[0] + " " + args[1])
 
 	set.FlagLong(&s.signedStatementFile, "signed-statement", 's', "Signed statement file", "signed-statement-file").Mandatory()
-	set.FlagLong(&s.publicKeyFile, "public-key", 'p', "Public key(s) in OpenSSH format to verify a signed bundle of artifacts", "public-key-file").Mandatory()
+	set.FlagLong(&s.publicKeyFile, "public-key", 'p', "Public key in OpenSSH, or PEM, format to verify a signed bundle of artifacts", "public-key-file").Mandatory()
+	set.FlagLong(&s.legacy, "legacy", 'l', "Accept legacy signatures, without signing envelope")
 	set.FlagLong(&help, "help", 'h', "Show usage message and exit")
 
 	err := set.Getopt(args[1:], nil)
@@ -73,17 +134,90 @@ the verification result is printed to stdout.
 
 func (s *SignSettings) parse(args []string) {
 	const usage = `
-Append an Ed25519 signature to a given statement.
-The statement, and the private key are provided as input files,
-the signed statement is saved to an output file.
+Append a signature to a given statement.
+The statement is provided as input file, while the signing key is either
+provided as private key file, held by the SSH agent (SSH_AUTH_SOCK) or held
+by an external signing helper command.
+The signed statement, or the detached signature, is saved to an output file.
+
+The signing helper is invoked with its arguments followed by:
+  public-key        print the OpenSSH, or PEM, public key to stdout
+  sign <algorithm>  sign the message read from stdin, print the hex
+                    encoded signature to stdout
 `
 	help := false
 	set := getopt.New()
 	set.SetProgram(args[0] + " " + args[1])
 
 	set.FlagLong(&s.statementFile, "statement", 'c', "Statement file", "statement-file").Mandatory()
-	set.FlagLong(&s.privateKeyFile, "private-key", 'k', "Private key(s) in OpenSSH format to sign a bundle of artifacts", "private-key-file").Mandatory()
+	set.FlagLong(&s.privateKeyFile, "private-key", 'k', "Private key in OpenSSH, or PEM, format to sign a bundle of artifacts", "private-key-file")
+	set.FlagLong(&s.agentKeyFile, "agent-key", 'A', "Public key, in OpenSSH or PEM format, of the SSH agent key to sign a bundle of artifacts", "public-key-file")
+	set.FlagLong(&s.helper, "helper", 'x', "External signing helper command to sign a bundle of artifacts", "command")
+	set.FlagLong(&s.signedStatementFile, "signed-statement", 's', "Signed statement file", "signed-statement-file")
+	set.FlagLong(&s.signatureFile, "detached", 'd', "Detached signature file, to be attached to the statement later on", "signature-file")
+	set.FlagLong(&s.algorithm, "algorithm", 'a', "Signature algorithm (default: inferred from the signing key)", "algorithm")
+	set.FlagLong(&s.legacy, "legacy", 'l', "Produce a legacy signature, without signing envelope")
+	set.FlagLong(&help, "help", 'h', "Show usage message and exit")
+
+	err := set.Getopt(args[1:], nil)
+
+	// handle help before checking for errors on other arguments
+	if help {
+		fmt.Print(usage[1:] + "\n")
+		set.PrintUsage(os.Stdout)
+		os.Exit(0)
+	}
+
+	if err == nil {
+		err = s.validate()
+	}
+
+	if err != nil {
+		log.Println(err)
+		set.PrintUsage(log.Writer())
+		os.Exit(1)
+	}
+}
+
+func (s *SignSettings) validate() error {
+	keys := 0
+
+	for _, k := range []string{s.privateKeyFile, s.agentKeyFile, s.helper} {
+		if len(k) > 0 {
+			keys++
+		}
+	}
+
+	if keys != 1 {
+		return fmt.Errorf("exactly one of --private-key, --agent-key or --helper is required")
+	}
+
+	if len(s.helper) > 0 && len(strings.Fields(s.helper)) == 0 {
+		return fmt.Errorf("--helper command is empty")
+	}
+
+	if (len(s.signedStatementFile) > 0) == (len(s.signatureFile) > 0) {
+		return fmt.Errorf("exactly one of --signed-statement or --detached is required")
+	}
+
+	return nil
+}
+
+func (s *AttachSettings) parse(args []string) {
+	const usage = `
+Attach detached signatures to a given statement.
+The statement, and the detached signatures are provided as input files,
+each signature is verified before being attached, the signed statement is
+saved to an output file.
+`
+	help := false
+	set := getopt.New()
+	set.SetProgram(args[0] + " " + args[1])
+
+	set.FlagLong(&s.statementFile, "statement", 'c', "Statement file", "statement-file").Mandatory()
+	set.FlagLong(&s.signatureFiles, "signature", 'g', "Detached signature file, can be repeated", "signature-file").Mandatory()
 	set.FlagLong(&s.signedStatementFile, "signed-statement", 's', "Signed statement file", "signed-statement-file").Mandatory()
+	set.FlagLong(&s.legacy, "legacy", 'l', "Accept legacy signatures, without signing envelope")
 	set.FlagLong(&help, "help", 'h', "Show usage message and exit")
 
 	err := set.Getopt(args[1:], nil)
@@ -129,6 +263,240 @@ the result of the parsing is printed to stdout.
 	}
 }
 
+func (s *CreateSettings) parse(args []string) {
+	const usage = `
+Create a boot-transparency statement claiming a given set of artifact files.
+Each artifact is provided as category and file path, relative to the root
+directory, its SHA-512 hash, file name, size and, where possible, version
+and architecture are claimed. An optional validity period, and the targeted
+devices, covered by the statement signatures, can be declared. The statement
+is saved to an output file, ready for signing.
+`
+	help := false
+	s.root = "."
+
+	set := getopt.New()
+	set.SetProgram(args[0] + " " + args[1])
+
+	set.FlagLong(&s.artifacts, "artifact", 'f', "Artifact category and file path (e.g. 1:vmlinuz), can be repeated", "category:path").Mandatory()
+	set.FlagLong(&s.root, "root", 'r', "Root directory of the artifact files (e.g. /boot)", "directory")
+	set.FlagLong(&s.description, "description", 'D', "Human-readable title for the bundle", "description")
+	set.FlagLong(&s.version, "version", 'v', "Bundle version, using Semantic Versioning 2.0.0", "version")
+	set.FlagLong(&s.notBefore, "not-before", 'b', "Start of the statement validity period, in RFC3339 format", "timestamp")
+	set.FlagLong(&s.notAfter, "not-after", 'e', "End of the statement validity period, in RFC3339 format", "timestamp")
+	set.FlagLong(&s.target.Products, "product", 0, "Targeted product identifier, can be repeated", "product")
+	set.FlagLong(&s.target.Compatible, "compatible", 0, "Targeted device tree board compatible string, can be repeated", "compatible")
+	set.FlagLong(&s.target.Machines, "machine", 0, "Targeted machine type (e.g. x64, AA64), can be repeated", "machine")
+	set.FlagLong(&s.target.DMIVendors, "dmi-vendor", 0, "Targeted DMI system vendor, can be repeated", "vendor")
+	set.FlagLong(&s.target.DMIModels, "dmi-model", 0, "Targeted DMI system product name, can be repeated", "model")
+	set.FlagLong(&s.statementFile, "statement", 'c', "Statement file", "statement-file").Mandatory()
+	set.FlagLong(&help, "help", 'h', "Show usage message and exit")
+
+	err := set.Getopt(args[1:], nil)
+
+	// handle help before checking for errors on other arguments
+	if help {
+		fmt.Print(usage[1:] + "\n")
+		set.PrintUsage(os.Stdout)
+		os.Exit(0)
+	}
+
+	if err != nil {
+		set.PrintUsage(log.Writer())
+		os.Exit(1)
+	}
+}
+
+func (s *MergeSettings) parse(args []string) {
+	const usage = `
+Merge the signatures of independently signed copies of a statement.
+The signed statements are provided as input files, their description,
+version and artifacts must match. Each signature is verified, and
+de-duplicated by public key, before being merged. The merged signed
+statement is saved to an output file.
+`
+	help := false
+	set := getopt.New()
+	set.SetProgram(args[0] + " " + args[1])
+
+	set.FlagLong(&s.signedStatementFiles, "signed-statement", 's', "Signed statement file, can be repeated", "signed-statement-file").Mandatory()
+	set.FlagLong(&s.outputFile, "output", 'o', "Merged signed statement file", "signed-statement-file").Mandatory()
+	set.FlagLong(&s.legacy, "legacy", 'l', "Accept legacy signatures, without signing envelope")
+	set.FlagLong(&help, "help", 'h', "Show usage message and exit")
+
+	err := set.Getopt(args[1:], nil)
+
+	// handle help before checking for errors on other arguments
+	if help {
+		fmt.Print(usage[1:] + "\n")
+		set.PrintUsage(os.Stdout)
+		os.Exit(0)
+	}
+
+	if err != nil {
+		set.PrintUsage(log.Writer())
+		os.Exit(1)
+	}
+}
+
+func (s *ListSettings) parse(args []string) {
+	const usage = `
+List the signatures of a given signed statement.
+The signed statement is provided as input file, the fingerprint, algorithm,
+envelope and validity of each signature are printed to stdout. The signer
+names are resolved from the signers of the optional policy file.
+`
+	help := false
+	set := getopt.New()
+	set.SetProgram(args[0] + " " + args[1])
+
+	set.FlagLong(&s.signedStatementFile, "signed-statement", 's', "Signed statement file", "signed-statement-file").Mandatory()
+	set.FlagLong(&s.policyFile, "policy", 'P', "Policy file, to resolve signer names", "policy-file")
+	set.FlagLong(&help, "help", 'h', "Show usage message and exit")
+
+	err := set.Getopt(args[1:], nil)
+
+	// handle help before checking for errors on other arguments
+	if help {
+		fmt.Print(usage[1:] + "\n")
+		set.PrintUsage(os.Stdout)
+		os.Exit(0)
+	}
+
+	if err != nil {
+		set.PrintUsage(log.Writer())
+		os.Exit(1)
+	}
+}
+
+func (s *RemoveSettings) parse(args []string) {
+	const usage = `
+Remove a signature from a given signed statement.
+The signed statement, and the public key of the signature to remove are
+provided as input files, the signed statement is saved to an output file.
+`
+	help := false
+	set := getopt.New()
+	set.SetProgram(args[0] + " " + args[1])
+
+	set.FlagLong(&s.signedStatementFile, "signed-statement", 's', "Signed statement file", "signed-statement-file").Mandatory()
+	set.FlagLong(&s.publicKeyFile, "public-key", 'p', "Public key, in OpenSSH or PEM format, of the signature to remove", "public-key-file").Mandatory()
+	set.FlagLong(&s.outputFile, "output", 'o', "Output signed statement file", "signed-statement-file").Mandatory()
+	set.FlagLong(&help, "help", 'h', "Show usage message and exit")
+
+	err := set.Getopt(args[1:], nil)
+
+	// handle help before checking for errors on other arguments
+	if help {
+		fmt.Print(usage[1:] + "\n")
+		set.PrintUsage(os.Stdout)
+		os.Exit(0)
+	}
+
+	if err != nil {
+		set.PrintUsage(log.Writer())
+		os.Exit(1)
+	}
+}
+
+// Parse the revoke command arguments
+func (s *RevokeSettings) parse(args []string) {
+	const usage = `
+Create a revocation statement, revoking earlier statements which are
+provided as input files or as digests. When a superseding statement is
+provided, the earlier statements are marked as superseded by it. The
+revocation statement is saved to an output file, ready for signing.
+`
+	help := false
+	set := getopt.New()
+	set.SetProgram(args[0] + " " + args[1])
+
+	set.FlagLong(&s.revokedStatementFiles, "revoked-statement", 's', "Revoked statement file, can be repeated", "statement-file")
+	set.FlagLong(&s.digests, "digest", 'H', "Revoked statement digest, can be repeated", "digest")
+	set.FlagLong(&s.supersededByFile, "superseded-by", 'S', "Superseding statement file", "statement-file")
+	set.FlagLong(&s.reason, "reason", 'R', "Human-readable revocation reason (e.g. CVE-2025-38236)", "reason")
+	set.FlagLong(&s.description, "description", 'D', "Human-readable title for the revocation", "description")
+	set.FlagLong(&s.statementFile, "statement", 'c', "Revocation statement file", "statement-file").Mandatory()
+	set.FlagLong(&help, "help", 'h', "Show usage message and exit")
+
+	err := set.Getopt(args[1:], nil)
+
+	// handle help before checking for errors on other arguments
+	if help {
+		fmt.Print(usage[1:] + "\n")
+		set.PrintUsage(os.Stdout)
+		os.Exit(0)
+	}
+
+	if err == nil {
+		err = s.validate()
+	}
+
+	if err != nil {
+		log.Println(err)
+		set.PrintUsage(log.Writer())
+		os.Exit(1)
+	}
+}
+
+func (s *RevokeSettings) validate() error {
+	if len(s.revokedStatementFiles)+len(s.digests) == 0 {
+		return fmt.Errorf("at least one --revoked-statement or --digest is required")
+	}
+
+	return nil
+}
+
+// Return the names of the policy signers, indexed by public key fingerprint
+func readSignerNames(fileName string) (map[string]string, error) {
+	names := make(map[string]string)
+
+	buf, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := policy.Parse(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range *p {
+		for _, signer := range entry.Signatures.Signers {
+			pub, err := signature.ParsePublicKey(signer.PubKey)
+			if err != nil {
+				continue
+			}
+
+			if fp, err := signature.Fingerprint(pub); err == nil && signer.Name != "" {
+				names[fp] = signer.Name
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// Parse artifact files expressed as category:path
+func parseArtifactFiles(artifacts []string) (files []statement.ArtifactFile, err error) {
+	for _, a := range artifacts {
+		c, p, ok := strings.Cut(a, ":")
+
+		if !ok || p == "" {
+			return nil, fmt.Errorf("invalid artifact %q, category:path expected", a)
+		}
+
+		category, err := strconv.ParseUint(c, 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact category %q", c)
+		}
+
+		files = append(files, statement.ArtifactFile{Category: uint(category), Path: p})
+	}
+
+	return
+}
+
 func readStatement(fileName string) (*statement.Statement, error) {
 	var s *statement.Statement
 
@@ -150,7 +518,88 @@ func readStatement(fileName string) (*statement.Statement, error) {
 	return s, nil
 }
 
-func writeSignedStatementFile(outputFile string, outputStatement *statement.Statement, signature *crypto.Signature, publicKey crypto.PublicKey) error {
+func readSignature(fileName string) (*statement.Signature, error) {
+	var sig statement.Signature
+
+	bytes, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(bytes, &sig); err != nil {
+		return nil, err
+	}
+
+	return &sig, nil
+}
+
+func readPublicKey(fileName string) (crypto.PublicKey, error) {
+	pubKey, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return signature.ParsePublicKey(string(pubKey))
+}
+
+// Return the signer selected by the settings, and a function to release it
+func newSigner(settings *SignSettings) (crypto.Signer, func(), error) {
+	switch {
+	case len(settings.agentKeyFile) > 0:
+		pub, err := readPublicKey(settings.agentKeyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		a, conn, err := signature.DialAgent()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		signer, err := signature.NewAgentSigner(a, pub)
+		if err != nil {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+
+		return signer, func() { _ = conn.Close() }, nil
+	case len(settings.helper) > 0:
+		args := strings.Fields(settings.helper)
+
+		signer, err := signature.NewExternalSigner(args[0], args[1:]...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		signer.Algorithm = settings.algorithm
+
+		return signer, func() {}, nil
+	default:
+		privateKey, err := os.ReadFile(settings.privateKeyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		signer, err := signature.ParsePrivateKey(privateKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return signer, func() {}, nil
+	}
+}
+
+// Verify a statement signature with the given public key, legacy signatures
+// are only accepted if explicitly allowed.
+func verifySignature(s *statement.Statement, sig *statement.Signature, pub crypto.PublicKey, legacy bool) error {
+	if sig.Envelope == "" && !legacy {
+		return fmt.Errorf("legacy signature not allowed")
+	}
+
+	return s.VerifySignature(sig, pub)
+}
+
+func writeSignedStatementFile(outputFile string, outputStatement *statement.Statement, sigs ...statement.Signature) error {
 	if len(outputFile) > 0 {
 		var err error
 		var signedS []byte
@@ -162,17 +611,13 @@ func writeSignedStatementFile(outputFile string, outputStatement *statement.Stat
 		}
 		defer closeFile(f)
 
-		s := statement.Signature{}
-		s.Signature = fmt.Sprintf("%x", signature[:])
-
-		// Ed25519 public keys following SSH format
-		prefix := []byte{'\v', 's', 's', 'h', '-', 'e', 'd', '2', '5', '5', '1', '9', 0, 0, 0}
-		plainKey := strings.Join([]string{string(prefix[:]), string(publicKey[:])}, " ")
-		encodedKey := base64.StdEncoding.EncodeToString([]byte(plainKey))
-		s.PubKey = strings.Join([]string{"ssh-ed25519 AAAA", encodedKey}, "")
-
-		// append the new signature, do not overwrite any existing one already present in the statement
-		outputStatement.Signatures = append(outputStatement.Signatures, s)
+		// add the new signatures, an existing signature is only replaced
+		// by a newer one from the same key
+		for _, sig := range sigs {
+			if outputStatement.AddSignature(sig) {
+				log.Printf("replacing existing signature by %s", sig.PubKey)
+			}
+		}
 
 		if signedS, err = json.MarshalIndent(outputStatement, "", "\t"); err != nil {
 			return err
@@ -186,6 +631,15 @@ func writeSignedStatementFile(outputFile string, outputStatement *statement.Stat
 	return nil
 }
 
+func writeSignatureFile(outputFile string, sig statement.Signature) error {
+	buf, err := json.MarshalIndent(sig, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(outputFile, buf, 0644)
+}
+
 func closeFile(f *os.File) {
 	if err := f.Close(); err != nil {
 		log.Fatal(err)
@@ -194,11 +648,17 @@ func closeFile(f *os.File) {
 
 func main() {
 	const usage = `
-Parse, sign, or verify, a statement associated to an artifact bundle.
+Parse, create, sign, or verify, a statement associated to an artifact bundle.
 
 Usage: bt-statement [--help]
    or: bt-statement parse [--help|options]
+   or: bt-statement create [--help|options]
    or: bt-statement sign [--help|options]
+   or: bt-statement attach [--help|options]
+   or: bt-statement merge [--help|options]
+   or: bt-statement list-signatures [--help|options]
+   or: bt-statement remove-signature [--help|options]
+   or: bt-statement revoke [--help|options]
    or: bt-statement verify [--help|options]
 `
 
@@ -223,41 +683,252 @@ Usage: bt-statement [--help]
 				log.Println(string(parsedStatement))
 			}
 		}
+	case "create":
+		var settings CreateSettings
+		settings.parse(os.Args)
+
+		files, err := parseArtifactFiles(settings.artifacts)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		s, err := statement.Create(os.DirFS(settings.root), files)
+		if err != nil {
+			log.Fatalf("statement create failed: %v", err)
+		}
+
+		s.Description = settings.description
+		s.Version = settings.version
+		s.NotBefore = settings.notBefore
+		s.NotAfter = settings.notAfter
+
+		if !settings.target.Empty() {
+			s.Target = &settings.target
+		}
+
+		jsonStatement, err := json.MarshalIndent(s, "", "\t")
+		if err != nil {
+			log.Fatalf("statement create failed: %v", err)
+		}
+
+		// ensure that the statement is valid before writing it
+		if _, err = statement.Parse(jsonStatement); err != nil {
+			log.Fatalf("statement create failed: %v", err)
+		}
+
+		if err = os.WriteFile(settings.statementFile, jsonStatement, 0644); err != nil {
+			log.Fatalf("statement create failed: %v", err)
+		}
+
+		log.Printf("statement written to: %q", settings.statementFile)
 	case "sign":
 		var settings SignSettings
 		settings.parse(os.Args)
 
-		signer, err := key.ReadPrivateKeyFile(settings.privateKeyFile)
+		signer, release, err := newSigner(&settings)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		algorithm := settings.algorithm
+		if algorithm == "" {
+			if algorithm, err = signature.ForKey(signer.Public()); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		pubKey, err := signature.MarshalPublicKey(signer.Public())
 		if err != nil {
 			log.Fatal(err)
 		}
 
+		signed := statement.Signature{
+			PubKey:   pubKey,
+			Envelope: statement.SignatureNamespace,
+		}
+
+		if settings.legacy {
+			signed.Envelope = ""
+		}
+
 		statement, err := readStatement(settings.statementFile)
 		if err != nil {
 			log.Fatalf("statement read from %q failed: %v", settings.statementFile, err)
 		}
 
-		// Sign only the artifacts section of the bundle statement
-		artifacts, err := json.Marshal(statement.Artifacts)
+		// Sign the statement within the signing envelope or, in legacy
+		// mode, only the artifacts section of the bundle statement
+		payload, err := statement.SigningPayload()
+
+		if settings.legacy {
+			payload, err = statement.LegacySigningPayload()
+		}
+
 		if err != nil {
 			log.Fatalf("statement sign failed: %v", err)
 		}
-		signature, err := signer.Sign(artifacts)
+		sig, err := signature.Sign(algorithm, signer, payload)
+		release()
+
 		if err != nil {
 			log.Fatalf("statement sign failed: %v", err)
 		}
 
+		signed.Signature = hex.EncodeToString(sig)
+
+		// the default algorithm is omitted for compatibility with earlier releases
+		if algorithm != signature.Ed25519 {
+			signed.Algorithm = algorithm
+		}
+
+		// Write the detached signature, to be attached later on
+		if len(settings.signatureFile) > 0 {
+			if err = writeSignatureFile(settings.signatureFile, signed); err != nil {
+				log.Fatalf("statement sign failed: %v", err)
+			}
+
+			log.Printf("detached signature written to: %q", settings.signatureFile)
+			break
+		}
+
 		// Append the new signature, and the public key associated to the signer key, to the output file
-		if err = writeSignedStatementFile(settings.signedStatementFile, statement, &signature, signer.Public()); err != nil {
+		if err = writeSignedStatementFile(settings.signedStatementFile, statement, signed); err != nil {
 			log.Fatalf("statement sign failed: %v", err)
 		}
 
 		log.Printf("signed statement written to: %q", settings.signedStatementFile)
-	case "verify":
-		var settings VerifySettings
+	case "attach":
+		var settings AttachSettings
+		settings.parse(os.Args)
+
+		var sigs []statement.Signature
+
+		statement, err := readStatement(settings.statementFile)
+		if err != nil {
+			log.Fatalf("statement read from %q failed: %v", settings.statementFile, err)
+		}
+
+		for _, fileName := range settings.signatureFiles {
+			sig, err := readSignature(fileName)
+			if err != nil {
+				log.Fatalf("signature read from %q failed: %v", fileName, err)
+			}
+
+			pub, err := signature.ParsePublicKey(sig.PubKey)
+			if err != nil {
+				log.Fatalf("signature read from %q failed: %v", fileName, err)
+			}
+
+			// only signatures valid for the statement are attached
+			if err = verifySignature(statement, sig, pub, settings.legacy); err != nil {
+				log.Fatalf("signature read from %q is NOT valid: %v", fileName, err)
+			}
+
+			sigs = append(sigs, *sig)
+		}
+
+		if err = writeSignedStatementFile(settings.signedStatementFile, statement, sigs...); err != nil {
+			log.Fatalf("statement attach failed: %v", err)
+		}
+
+		log.Printf("signed statement written to: %q", settings.signedStatementFile)
+	case "merge":
+		var settings MergeSettings
 		settings.parse(os.Args)
 
-		publicKey, err := key.ReadPublicKeyFile(settings.publicKeyFile)
+		var merged *statement.Statement
+
+		for _, fileName := range settings.signedStatementFiles {
+			s, err := readStatement(fileName)
+			if err != nil {
+				log.Fatalf("read statement %q failed: %v", fileName, err)
+			}
+
+			// only valid signatures are merged
+			for _, sig := range s.Signatures {
+				pub, err := signature.ParsePublicKey(sig.PubKey)
+				if err == nil {
+					err = verifySignature(s, &sig, pub, settings.legacy)
+				}
+
+				if err != nil {
+					log.Fatalf("signature by %s in %q is NOT valid: %v", sig.PubKey, fileName, err)
+				}
+			}
+
+			if merged == nil {
+				base := *s
+				base.Signatures = nil
+				merged = &base
+			}
+
+			n, err := merged.Merge(s)
+			if err != nil {
+				log.Fatalf("statement merge of %q failed: %v", fileName, err)
+			}
+
+			log.Printf("%d signature(s) merged from %q", n, fileName)
+		}
+
+		if err := writeSignedStatementFile(settings.outputFile, merged); err != nil {
+			log.Fatalf("statement merge failed: %v", err)
+		}
+
+		log.Printf("signed statement written to: %q", settings.outputFile)
+	case "list-signatures":
+		var settings ListSettings
+		settings.parse(os.Args)
+
+		var names map[string]string
+
+		statement, err := readStatement(settings.signedStatementFile)
+		if err != nil {
+			log.Fatalf("read statement %q failed: %v", settings.signedStatementFile, err)
+		}
+
+		if len(settings.policyFile) > 0 {
+			if names, err = readSignerNames(settings.policyFile); err != nil {
+				log.Fatalf("read policy %q failed: %v", settings.policyFile, err)
+			}
+		}
+
+		for i, sig := range statement.Signatures {
+			fp := "invalid public key"
+			status := "NOT valid"
+			algorithm := sig.Algorithm
+			envelope := sig.Envelope
+
+			if algorithm == "" {
+				algorithm = signature.Ed25519
+			}
+
+			if envelope == "" {
+				envelope = "legacy"
+			}
+
+			if pub, err := signature.ParsePublicKey(sig.PubKey); err == nil {
+				if fp, err = signature.Fingerprint(pub); err != nil {
+					fp = "unsupported public key"
+				}
+
+				if statement.VerifySignature(&sig, pub) == nil {
+					status = "valid"
+				}
+			}
+
+			fmt.Printf("%d\t%s\t%s\t%s\t%s", i, fp, algorithm, envelope, status)
+
+			if name, ok := names[fp]; ok {
+				fmt.Printf("\t%s", name)
+			}
+
+			fmt.Println()
+		}
+	case "remove-signature":
+		var settings RemoveSettings
+		settings.parse(os.Args)
+
+		buf, err := os.ReadFile(settings.publicKeyFile)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -266,22 +937,100 @@ Usage: bt-statement [--help]
 		if err != nil {
 			log.Fatalf("read statement %q failed: %v", settings.signedStatementFile, err)
 		}
-		artifacts, err := json.Marshal(statement.Artifacts)
+
+		if !statement.RemoveSignature(string(buf)) {
+			log.Fatalf("statement %q is not signed by the public key", settings.signedStatementFile)
+		}
+
+		if err = writeSignedStatementFile(settings.outputFile, statement); err != nil {
+			log.Fatalf("signature removal failed: %v", err)
+		}
+
+		log.Printf("signed statement written to: %q", settings.outputFile)
+	case "revoke":
+		var settings RevokeSettings
+		settings.parse(os.Args)
+
+		r := &statement.Statement{
+			Kind:        statement.KindRevocation,
+			Description: settings.description,
+			Artifacts:   []statement.Artifact{},
+		}
+
+		revocation := statement.Revocation{
+			Status: statement.Revoked,
+			Reason: settings.reason,
+		}
+
+		if settings.supersededByFile != "" {
+			s, err := readStatement(settings.supersededByFile)
+			if err != nil {
+				log.Fatalf("read statement %q failed: %v", settings.supersededByFile, err)
+			}
+
+			if revocation.SupersededBy, err = s.Digest(); err != nil {
+				log.Fatal(err)
+			}
+
+			revocation.Status = statement.Superseded
+		}
+
+		digests := settings.digests
+
+		for _, fileName := range settings.revokedStatementFiles {
+			s, err := readStatement(fileName)
+			if err != nil {
+				log.Fatalf("read statement %q failed: %v", fileName, err)
+			}
+
+			digest, err := s.Digest()
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			log.Printf("revoking %q: %s", fileName, digest)
+			digests = append(digests, digest)
+		}
+
+		for _, digest := range digests {
+			revocation.Digest = digest
+			r.Revokes = append(r.Revokes, revocation)
+		}
+
+		jsonStatement, err := json.MarshalIndent(r, "", "\t")
+		if err != nil {
+			log.Fatalf("revocation statement create failed: %v", err)
+		}
+
+		// ensure that the statement is valid before writing it
+		if _, err = statement.Parse(jsonStatement); err != nil {
+			log.Fatalf("revocation statement create failed: %v", err)
+		}
+
+		if err = os.WriteFile(settings.statementFile, jsonStatement, 0644); err != nil {
+			log.Fatalf("revocation statement create failed: %v", err)
+		}
+
+		log.Printf("revocation statement written to: %q", settings.statementFile)
+	case "verify":
+		var settings VerifySettings
+		settings.parse(os.Args)
+
+		publicKey, err := readPublicKey(settings.publicKeyFile)
 		if err != nil {
-			log.Fatalf("signature verification failed: %v", err)
+			log.Fatal(err)
 		}
 
+		statement, err := readStatement(settings.signedStatementFile)
+		if err != nil {
+			log.Fatalf("read statement %q failed: %v", settings.signedStatementFile, err)
+		}
 		// the signed statement can contain multiple signatures
 		foundValidSignature := false
 		for _, sig := range statement.Signatures {
-			s, err := crypto.SignatureFromHex(sig.Signature)
-			if err != nil {
-				log.Fatalf("signature verification failed: %v", err)
-			}
-
-			if crypto.Verify(&publicKey, artifacts, &s) {
-				log.Printf("signature is valid")
+			if verifySignature(statement, &sig, publicKey, settings.legacy) == nil {
 				foundValidSignature = true
+				log.Printf("signature is valid")
 				break
 			}
 		}
@@ -0,0 +1,172 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pborman/getopt/v2"
+	_ "github.com/usbarmory/boot-transparency/engine/sigsum"
+	_ "github.com/usbarmory/boot-transparency/engine/tessera"
+	"github.com/usbarmory/boot-transparency/statement"
+	"github.com/usbarmory/boot-transparency/transparency"
+	"golang.org/x/crypto/ssh"
+)
+
+type SubmitSettings struct {
+	transparencyEngine  string
+	logURL              string
+	logKeyFile          string
+	witnessPolicyFile   string
+	submitKeyFile       string
+	signedStatementFile string
+}
+
+func (s *SubmitSettings) parse(args []string) {
+	const usage = `
+Submit a signed statement to a transparency log, and wait for its
+inclusion. The resulting boot-transparency proof bundle is written
+to stdout.
+`
+	help := false
+
+	set := getopt.New()
+	set.SetProgram(args[0])
+
+	set.FlagLong(&s.transparencyEngine, "engine", 'e', "Transparency engine (i.e. 1: Sigsum or 2: Tessera)", "transparency-engine").Mandatory()
+	set.FlagLong(&s.logURL, "log-url", 'u', "Log URL", "log-url").Mandatory()
+	set.FlagLong(&s.logKeyFile, "log-key", 'l', "Log public key file", "log-key-file").Mandatory()
+	set.FlagLong(&s.witnessPolicyFile, "witness-policy", 'w', "Witness policy file", "witness-policy-file").Mandatory()
+	set.FlagLong(&s.submitKeyFile, "submit-key", 'k', "Submitter private key file, OpenSSH format (Sigsum only)", "submit-key-file")
+	set.FlagLong(&s.signedStatementFile, "signed-statement", 's', "Signed statement file", "signed-statement-file").Mandatory()
+	set.FlagLong(&help, "help", 'h', "Show usage message and exit")
+
+	err := set.Getopt(args, nil)
+
+	// handle help before checking for errors on other arguments
+	if help {
+		fmt.Print(usage[1:] + "\n")
+		set.PrintUsage(os.Stdout)
+		os.Exit(0)
+	}
+
+	if err != nil {
+		set.PrintUsage(log.Writer())
+		os.Exit(1)
+	}
+}
+
+// Load the submitter private key, and its public key in
+// OpenSSH authorized key format as expected by Sigsum.
+func loadSubmitKey(fileName string) (signer crypto.Signer, pub string, err error) {
+	buf, err := os.ReadFile(fileName)
+
+	if err != nil {
+		return
+	}
+
+	k, err := ssh.ParseRawPrivateKey(buf)
+
+	if err != nil {
+		return
+	}
+
+	switch k := k.(type) {
+	case *ed25519.PrivateKey:
+		signer = *k
+	case ed25519.PrivateKey:
+		signer = k
+	default:
+		return nil, "", fmt.Errorf("unsupported key type %T, expected Ed25519", k)
+	}
+
+	sshPub, err := ssh.NewPublicKey(signer.Public())
+
+	if err != nil {
+		return
+	}
+
+	return signer, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))), nil
+}
+
+func main() {
+	var signer crypto.Signer
+	var submitKey []string
+
+	log.SetFlags(0)
+
+	var settings SubmitSettings
+	settings.parse(os.Args)
+
+	format, err := strconv.ParseUint(settings.transparencyEngine, 10, 64)
+	if err != nil {
+		log.Fatalf("invalid transparency engine: %s", settings.transparencyEngine)
+	}
+
+	s, err := os.ReadFile(settings.signedStatementFile)
+	if err != nil {
+		log.Fatalf("read statement %q failed: %v", settings.signedStatementFile, err)
+	}
+
+	_, err = statement.Parse(s)
+	if err != nil {
+		log.Fatalf("parse statement %q failed: %v", settings.signedStatementFile, err)
+	}
+
+	logKey, err := os.ReadFile(settings.logKeyFile)
+	if err != nil {
+		log.Fatalf("read log key %q failed: %v", settings.logKeyFile, err)
+	}
+
+	wp, err := os.ReadFile(settings.witnessPolicyFile)
+	if err != nil {
+		log.Fatalf("read witness policy %q failed: %v", settings.witnessPolicyFile, err)
+	}
+
+	if settings.submitKeyFile != "" {
+		var pub string
+
+		if signer, pub, err = loadSubmitKey(settings.submitKeyFile); err != nil {
+			log.Fatalf("read submit key %q failed: %v", settings.submitKeyFile, err)
+		}
+
+		submitKey = append(submitKey, pub)
+	}
+
+	e, err := transparency.NewEngine(uint(format), transparency.Config{
+		LogKey:        []string{strings.TrimSpace(string(logKey))},
+		SubmitKey:     submitKey,
+		WitnessPolicy: wp,
+	})
+	if err != nil {
+		log.Fatalf("invalid transparency engine configuration: %v", err)
+	}
+
+	pb, err := e.Submit(context.Background(), settings.logURL, s, signer)
+	if err != nil {
+		log.Fatalf("submission failed: %v", err)
+	}
+
+	jsonProofBundle, err := json.MarshalIndent(pb, "", "\t")
+	if err != nil {
+		log.Fatalf("failed to marshal the proof bundle: %v", err)
+	}
+
+	// print result to stdout
+	fmt.Println(string(jsonProofBundle))
+
+	os.Exit(0)
+}
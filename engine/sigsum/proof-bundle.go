@@ -9,6 +9,8 @@ package sigsum
 
 import (
 	"encoding/json"
+
+	"github.com/usbarmory/boot-transparency/transparency"
 )
 
 // Define Sigsum proof bundle structure
@@ -18,4 +20,6 @@ type ProofBundle struct {
 	Statement json.RawMessage `json:"statement"`
 	Probe     Probe           `json:"probe,omitempty"`
 	Proof     string          `json:"proof,omitempty"`
+
+	Consistency *transparency.ConsistencyProof `json:"consistency,omitempty"`
 }
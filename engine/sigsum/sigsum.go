@@ -17,6 +17,9 @@ import (
 	"net/url"
 	"time"
 
+	merkle "github.com/transparency-dev/merkle/proof"
+	"github.com/transparency-dev/merkle/rfc6962"
+	bt_statement "github.com/usbarmory/boot-transparency/statement"
 	"github.com/usbarmory/boot-transparency/transparency"
 	"sigsum.org/sigsum-go/pkg/client"
 	"sigsum.org/sigsum-go/pkg/crypto"
@@ -36,16 +39,67 @@ type SigsumEngine struct {
 	// the witness policy, the actual format should be aligned
 	// with the one supported one by the chosen transparency engine
 	witnessPolicy *policy.Policy
+	// latest trusted tree head, for each log origin
+	trustState transparency.TrustState
+	// maximum age of the tree head cosignatures
+	maxAge time.Duration
+	// network configuration to reach the logs
+	network transparency.Network
+	// HTTP client for log requests
+	httpClient *http.Client
 }
 
 func init() {
-	e := SigsumEngine{}
-	transparency.Add(&e, transparency.Sigsum)
+	transparency.Register(transparency.Sigsum, func(cfg transparency.Config) (transparency.Engine, error) {
+		return New(cfg)
+	})
+}
+
+// Return a new Sigsum transparency engine instance, for the given
+// configuration.
+func New(cfg transparency.Config) (e *SigsumEngine, err error) {
+	e = &SigsumEngine{
+		trustState: cfg.TrustState,
+		maxAge:     cfg.MaxAge,
+		network:    cfg.Network,
+		httpClient: cfg.Network.Client(),
+	}
+
+	// parse and load log public key(s)
+	for _, k := range cfg.LogKey {
+		if _, err = key.ParsePublicKey(k); err != nil {
+			return nil, fmt.Errorf("invalid log public key: %v", err)
+		}
+
+		e.logPubkey = append(e.logPubkey, k)
+	}
+
+	// parse and load submit public key(s)
+	for _, k := range cfg.SubmitKey {
+		if _, err = key.ParsePublicKey(k); err != nil {
+			return nil, fmt.Errorf("invalid submit public key: %v", err)
+		}
+
+		e.submitPubkey = append(e.submitPubkey, k)
+	}
+
+	if len(cfg.WitnessPolicy) > 0 {
+		if e.witnessPolicy, err = policy.ParseConfig(bytes.NewReader(cfg.WitnessPolicy)); err != nil {
+			return nil, fmt.Errorf("invalid witness policy: %v", err)
+		}
+	}
+
+	return
 }
 
 // The logic implemented for the Sigsum engine is partially replicating
 // the collectProof() from sigsum-go/pkg/submit/submit.go
-func (e *SigsumEngine) GetProof(proofBundle interface{}) ([]byte, error) {
+func (e *SigsumEngine) GetProof(ctx context.Context, proofBundle interface{}) ([]byte, error) {
+	// the engine-agnostic form, if any, is updated along with the
+	// engine proof bundle
+	bundle := proofBundle
+	proofBundle = transparency.NativeBundle(proofBundle)
+
 	if _, ok := proofBundle.(*ProofBundle); !ok {
 		return nil, fmt.Errorf("invalid·proof bundle for Sigsum engine")
 	}
@@ -85,47 +139,21 @@ func (e *SigsumEngine) GetProof(proofBundle interface{}) ([]byte, error) {
 		return nil, err
 	}
 
-	if _, err := url.Parse(pb.Probe.Origin); err != nil {
-		return nil, fmt.Errorf("invalid log origin: %s", err)
-	}
-
-	// HTTP client configuration
-	tr := &http.Transport{
-		MaxIdleConns:       10,
-		IdleConnTimeout:    29 * time.Second,
-		DisableCompression: true,
-	}
-	httpClient := &http.Client{Transport: tr}
-
-	client := client.New(client.Config{
-		UserAgent:  "boot-transparency",
-		URL:        pb.Probe.Origin,
-		HTTPClient: httpClient,
-	})
+	sig, _ := crypto.SignatureFromHex(pb.Probe.LeafSignature)
 
 	// By default in Sigsum, the actual logged message is a double SHA-256 of the statement
 	// equivalent to: $ sha256sum statement.json | cut -d' ' -f1 | base16 -d | sha256sum
-	// JSON marshalling is required to ensure the message has been logged
-	// independently from its formatting (i.e. indent spaces, or tabs,
-	// that would be present in human-readable statement JSON)
-	statement, err := json.Marshal(pb.Statement)
+	// the leaf signature identifies which serialization of the statement
+	// has been logged
+	msg, err := signedMessage(pb.Statement, &sk, &sig)
 
 	if err != nil {
 		return nil, err
 	}
 
-	// need to append a newline (i.e. 0x0a) to be consistent
-	// with the actual logged bytes
-	statement = append(statement, "\n"...)
-
 	// the message chksum is a sha256 of the logged message,
 	// which in turn is a sha256 of the initial statement
-	s := sha256.Sum256(statement)
-	s = sha256.Sum256(s[:])
-
-	msgChksum := crypto.Hash(s)
-
-	sig, _ := crypto.SignatureFromHex(pb.Probe.LeafSignature)
+	msgChksum := crypto.Hash(sha256.Sum256(msg[:]))
 
 	// proof.ShortLeaf is used by GetTreeHead()
 	shortLeaf := proof.ShortLeaf{
@@ -145,84 +173,118 @@ func (e *SigsumEngine) GetProof(proofBundle interface{}) ([]byte, error) {
 		Leaf:       shortLeaf,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(30*time.Second))
-	defer cancel()
+	leafHash := leaf.ToHash()
 
-	if pr.TreeHead, err = client.GetTreeHead(ctx); err != nil {
-		return nil, fmt.Errorf("getting latest tree head: %v", err)
-	}
+	// the log origin, or its mirrors, are queried until a verified
+	// inclusion proof is collected
+	err = e.network.Do(ctx, pb.Probe.Origin, func(ctx context.Context, logURL string) (err error) {
+		client, err := e.newClient(logURL)
 
-	if err = e.witnessPolicy.VerifyCosignedTreeHead(&pr.LogKeyHash, &pr.TreeHead); err != nil {
-		return nil, fmt.Errorf("verifying tree head: %v", err)
-	}
+		if err != nil {
+			return
+		}
 
-	leafHash := leaf.ToHash()
-	req := requests.InclusionProof{Size: pr.TreeHead.Size, LeafHash: leafHash}
+		if pr.TreeHead, err = client.GetTreeHead(ctx); err != nil {
+			return fmt.Errorf("getting latest tree head: %v", err)
+		}
 
-	if pr.Inclusion, err = client.GetInclusionProof(ctx, req); err != nil {
-		return nil, fmt.Errorf("getting inclusion proof: %v", err)
+		if err = e.witnessPolicy.VerifyCosignedTreeHead(&pr.LogKeyHash, &pr.TreeHead); err != nil {
+			return fmt.Errorf("verifying tree head: %v", err)
+		}
+
+		req := requests.InclusionProof{Size: pr.TreeHead.Size, LeafHash: leafHash}
+
+		if pr.Inclusion, err = client.GetInclusionProof(ctx, req); err != nil {
+			return fmt.Errorf("getting inclusion proof: %v", err)
+		}
+
+		if err = pr.Inclusion.Verify(&leafHash, &pr.TreeHead.TreeHead); err != nil {
+			return fmt.Errorf("invalid inclusion proof: %v", err)
+		}
+
+		return
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	if err = pr.Inclusion.Verify(&leafHash, &pr.TreeHead.TreeHead); err != nil {
-		return nil, fmt.Errorf("invalid inclusion proof: %v", err)
+	// the fetched tree head must be proven consistent with the trusted
+	// one, for the bundle to be verified once the log has grown
+	consistency, err := e.trustedConsistency(ctx, pb.Probe.Origin, treeHeadOrigin(&pr.LogKeyHash), pr.TreeHead.Size)
+
+	if err != nil {
+		return nil, err
 	}
 
 	// save the whole inclusion proof in ASCII format in the proof bundle
-	return buildSigsumProofBundle(pr), nil
-}
+	p := buildSigsumProofBundle(pr)
+	pb.Proof = string(p)
+	pb.Consistency = consistency
 
-func (e *SigsumEngine) ParseWitnessPolicy(wp []byte) (interface{}, error) {
-	p, err := policy.ParseConfig(bytes.NewReader(wp))
-
-	if err != nil {
+	if err = transparency.UpdateBundle(bundle); err != nil {
 		return nil, err
 	}
 
-	return p, err
+	return p, nil
 }
 
-func (e *SigsumEngine) SetKey(logKey []string, submitKey []string) (err error) {
-	// re-set any previously stored key
-	e.logPubkey = []string{}
-	e.submitPubkey = []string{}
-
-	// parse and load log public key(s)
-	for _, k := range logKey {
-		_, err = key.ParsePublicKey(k)
+func (e *SigsumEngine) GetConsistencyProof(ctx context.Context, origin string, oldSize uint64, newSize uint64) (*transparency.ConsistencyProof, error) {
+	if oldSize > newSize {
+		return nil, fmt.Errorf("invalid tree sizes, old size %d is greater than new size %d", oldSize, newSize)
+	}
 
-		if err != nil {
-			return
-		}
+	cp := &transparency.ConsistencyProof{
+		OldSize: oldSize,
+		NewSize: newSize,
+		Path:    [][]byte{},
+	}
 
-		e.logPubkey = append(e.logPubkey, k)
+	// the consistency proof is empty when either tree is empty, or when
+	// both trees have the same size, there is no need to query the log
+	if oldSize == 0 || oldSize == newSize {
+		return cp, nil
 	}
 
-	// parse and load submit public key(s)
-	for _, k := range submitKey {
-		_, err = key.ParsePublicKey(k)
+	var p types.ConsistencyProof
+
+	err := e.network.Do(ctx, origin, func(ctx context.Context, logURL string) (err error) {
+		client, err := e.newClient(logURL)
 
 		if err != nil {
 			return
 		}
 
-		e.submitPubkey = append(e.submitPubkey, k)
-	}
+		req := requests.ConsistencyProof{OldSize: oldSize, NewSize: newSize}
 
-	return
-}
+		if p, err = client.GetConsistencyProof(ctx, req); err != nil {
+			return fmt.Errorf("getting consistency proof: %v", err)
+		}
+
+		return
+	})
 
-func (e *SigsumEngine) SetWitnessPolicy(wp interface{}) (err error) {
-	if _, ok := wp.(*policy.Policy); !ok {
-		return fmt.Errorf("invalid policy, type assertion to Sigsum *policy.Policy failed")
+	if err != nil {
+		return nil, err
 	}
 
-	e.witnessPolicy = wp.(*policy.Policy)
+	for _, h := range p.Path {
+		cp.Path = append(cp.Path, h[:])
+	}
 
-	return
+	return cp, nil
 }
 
-func (e *SigsumEngine) ResetWitnessPolicy() {
-	e.witnessPolicy = nil
+func (e *SigsumEngine) VerifyConsistency(oldTreeHead *transparency.TreeHead, newTreeHead *transparency.TreeHead, proof *transparency.ConsistencyProof) error {
+	if oldTreeHead == nil || newTreeHead == nil {
+		return fmt.Errorf("tree heads are not set")
+	}
+
+	if oldTreeHead.Origin != newTreeHead.Origin {
+		return fmt.Errorf("tree heads origin mismatch, %s and %s", oldTreeHead.Origin, newTreeHead.Origin)
+	}
+
+	return verifyConsistency(oldTreeHead, newTreeHead, proof)
 }
 
 func (e *SigsumEngine) VerifyProof(proofBundle interface{}) (err error) {
@@ -230,6 +292,8 @@ func (e *SigsumEngine) VerifyProof(proofBundle interface{}) (err error) {
 	var lk crypto.PublicKey
 	var sk crypto.PublicKey
 
+	proofBundle = transparency.NativeBundle(proofBundle)
+
 	if _, ok := proofBundle.(*ProofBundle); !ok {
 		return fmt.Errorf("invalid proof bundle for Sigsum engine")
 	}
@@ -241,23 +305,14 @@ func (e *SigsumEngine) VerifyProof(proofBundle interface{}) (err error) {
 		return fmt.Errorf("invalid bundle format %d, expected %d (transparency.SigsumBundle)", pb.Format, transparency.Sigsum)
 	}
 
-	// load the statement and compute its checksum, which is the logged message to verify
-	// JSON marshalling is required to ensure the message has been logged
-	// independently from its formatting (i.e. indent spaces, or tabs,
-	// that would be present in human-readable statement JSON)
-	statement, err := json.Marshal(pb.Statement)
+	// load the statement and compute its checksums, which are the candidate
+	// logged messages to verify
+	msgs, err := loggedMessages(pb.Statement)
 
 	if err != nil {
 		return
 	}
 
-	// need to append a newline (i.e. 0x0a) to be consistent
-	// with the actual logged bytes
-	statement = append(statement, "\n"...)
-
-	// the logged message is a sha256 of the original statement
-	msg := crypto.Hash(sha256.Sum256(statement))
-
 	// load the proof
 	asciiProof := []byte(pb.Proof)
 	if err = proof.FromASCII(bytes.NewReader(asciiProof)); err != nil {
@@ -269,9 +324,10 @@ func (e *SigsumEngine) VerifyProof(proofBundle interface{}) (err error) {
 		return fmt.Errorf("submitter public key is not set")
 	}
 
-	// check if at least one trusted log key has been set.
-	// The log key is read directly from the witness policy (when present)
-	if e.witnessPolicy == nil && len(e.logPubkey) == 0 {
+	// check if at least one trusted log key has been set, as the proof
+	// is verified against each trusted log and submitter key pair,
+	// regardless of the witness policy
+	if len(e.logPubkey) == 0 {
 		return fmt.Errorf("log public key is not set")
 	}
 
@@ -293,18 +349,20 @@ func (e *SigsumEngine) VerifyProof(proofBundle interface{}) (err error) {
 				return fmt.Errorf("invalid submit public key: %s", submitKey)
 			}
 
-			// include quorum verification only if the witness policy is set.
-			if e.witnessPolicy != nil {
-				err = proof.Verify(&msg, map[crypto.Hash]crypto.PublicKey{
-					crypto.HashBytes(sk[:]): sk}, e.witnessPolicy)
-			} else { // verification do not include any witness quorum verification
-				err = proof.VerifyNoCosignatures(&msg, map[crypto.Hash]crypto.PublicKey{
-					crypto.HashBytes(sk[:]): sk}, &lk)
-			}
-
-			// return immediately if the proof verification passes
-			if err == nil {
-				return
+			for _, msg := range msgs {
+				// include quorum verification only if the witness policy is set.
+				if e.witnessPolicy != nil {
+					err = proof.Verify(&msg, map[crypto.Hash]crypto.PublicKey{
+						crypto.HashBytes(sk[:]): sk}, e.witnessPolicy)
+				} else { // verification do not include any witness quorum verification
+					err = proof.VerifyNoCosignatures(&msg, map[crypto.Hash]crypto.PublicKey{
+						crypto.HashBytes(sk[:]): sk}, &lk)
+				}
+
+				// return immediately if the proof verification passes
+				if err == nil {
+					return e.checkTreeHead(&proof, pb.Consistency)
+				}
 			}
 		}
 	}
@@ -347,6 +405,116 @@ func (e *SigsumEngine) ParseProof(jsonProofBundle []byte) (interface{}, []byte,
 	return &pb, pbMarshal, nil
 }
 
+// Return the tree head origin, derived from the log key hash, which is
+// authenticated by the tree head signature, rather than from the probing data
+func treeHeadOrigin(logKeyHash *crypto.Hash) string {
+	return fmt.Sprintf("sigsum.org/v1/tree/%x", logKeyHash[:])
+}
+
+// Return a consistency proof between the trusted tree head, for a given log
+// origin, and a more recent tree head. No proof is returned if the trust
+// state is not set, or the trusted tree head is not older than the given one.
+func (e *SigsumEngine) trustedConsistency(ctx context.Context, logURL string, origin string, size uint64) (*transparency.ConsistencyProof, error) {
+	if e.trustState == nil {
+		return nil, nil
+	}
+
+	trusted, err := e.trustState.Load(origin)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot load trusted tree head: %v", err)
+	}
+
+	if trusted == nil || trusted.Size >= size {
+		return nil, nil
+	}
+
+	return e.GetConsistencyProof(ctx, logURL, trusted.Size, size)
+}
+
+// Check the freshness of a verified tree head, and its consistency with
+// the latest trusted tree head for the same log.
+func (e *SigsumEngine) checkTreeHead(p *proof.SigsumProof, cp *transparency.ConsistencyProof) (err error) {
+	if err = e.checkFreshness(&p.LogKeyHash, &p.TreeHead); err != nil {
+		return
+	}
+
+	if e.trustState == nil {
+		return
+	}
+
+	th := &transparency.TreeHead{
+		Origin:   treeHeadOrigin(&p.LogKeyHash),
+		Size:     p.TreeHead.Size,
+		RootHash: p.TreeHead.RootHash[:],
+	}
+
+	// reject rollbacks and ensure that the trusted tree head
+	// is consistent with the one in the proof bundle
+	return transparency.CheckTrustState(e.trustState, th, func(trusted *transparency.TreeHead) error {
+		return verifyConsistency(trusted, th, cp)
+	})
+}
+
+// Verify that the tree head cosignatures, satisfying the witness policy,
+// are not older than the configured maximum age.
+func (e *SigsumEngine) checkFreshness(logKeyHash *crypto.Hash, cth *types.CosignedTreeHead) (err error) {
+	if e.maxAge == 0 {
+		return
+	}
+
+	if e.witnessPolicy == nil {
+		return fmt.Errorf("witness policy not configured, cannot check the tree head age")
+	}
+
+	notBefore := time.Now().Add(-e.maxAge)
+
+	// keep only the cosignatures with a timestamp within the maximum age,
+	// those are the only ones considered to satisfy the witness policy
+	fresh := *cth
+	fresh.Cosignatures = make(map[crypto.Hash]types.Cosignature)
+
+	for keyHash, cs := range cth.Cosignatures {
+		if time.Unix(int64(cs.Timestamp), 0).Before(notBefore) {
+			continue
+		}
+
+		fresh.Cosignatures[keyHash] = cs
+	}
+
+	if err = e.witnessPolicy.VerifyCosignedTreeHead(logKeyHash, &fresh); err != nil {
+		return fmt.Errorf("tree head cosignatures are older than %v: %v", e.maxAge, err)
+	}
+
+	return
+}
+
+// Verify that the older tree head is a prefix of the newer one.
+func verifyConsistency(oldTreeHead *transparency.TreeHead, newTreeHead *transparency.TreeHead, cp *transparency.ConsistencyProof) error {
+	if cp == nil {
+		return fmt.Errorf("consistency proof is not present")
+	}
+
+	if cp.OldSize != oldTreeHead.Size || cp.NewSize != newTreeHead.Size {
+		return fmt.Errorf("consistency proof from size %d to %d does not match tree heads", cp.OldSize, cp.NewSize)
+	}
+
+	return merkle.VerifyConsistency(rfc6962.DefaultHasher, oldTreeHead.Size, newTreeHead.Size, cp.Path, oldTreeHead.RootHash, newTreeHead.RootHash)
+}
+
+// Return a Sigsum client for the log reachable at the given origin.
+func (e *SigsumEngine) newClient(origin string) (*client.Client, error) {
+	if _, err := url.Parse(origin); err != nil {
+		return nil, fmt.Errorf("invalid log origin: %s", err)
+	}
+
+	return client.New(client.Config{
+		UserAgent:  e.network.Agent(),
+		URL:        origin,
+		HTTPClient: e.httpClient,
+	}), nil
+}
+
 // If present, return the key that corresponds to a given key hash.
 // The key is searched among all the trusted keys configured for the transparency engine.
 func getTrustedKeyFromHash(trustedKeys []string, hash string) (crypto.PublicKey, error) {
@@ -386,3 +554,40 @@ func buildSigsumProofBundle(p proof.SigsumProof) []byte {
 
 	return b.Bytes()
 }
+
+// Return the candidate messages logged for a statement, that is the SHA-256
+// of its canonical form (see statement.Canonicalize()) including a trailing
+// newline (i.e. 0x0a), consistently with the actual logged bytes. The SHA-256
+// of its compact form, logged by earlier releases, is returned as second
+// candidate when different.
+func loggedMessages(jsonStatement []byte) (msgs []crypto.Hash, err error) {
+	forms, err := bt_statement.CanonicalForms(jsonStatement)
+
+	if err != nil {
+		return
+	}
+
+	for _, f := range forms {
+		msgs = append(msgs, crypto.Hash(sha256.Sum256(append(f, "\n"...))))
+	}
+
+	return
+}
+
+// Return the message logged for a statement, among the candidate ones,
+// matching the leaf signature.
+func signedMessage(jsonStatement []byte, sk *crypto.PublicKey, sig *crypto.Signature) (msg crypto.Hash, err error) {
+	msgs, err := loggedMessages(jsonStatement)
+
+	if err != nil {
+		return
+	}
+
+	for _, msg = range msgs {
+		if types.VerifyLeafMessage(sk, msg[:], sig) {
+			return
+		}
+	}
+
+	return msg, fmt.Errorf("leaf signature does not match the statement")
+}
@@ -8,9 +8,25 @@
 package sigsum
 
 import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/transparency-dev/merkle/rfc6962"
+	"github.com/transparency-dev/merkle/testonly"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/usbarmory/boot-transparency/engine/sigsum/testlog"
 	"github.com/usbarmory/boot-transparency/transparency"
 )
 
@@ -37,14 +53,25 @@ func TestSigsumEngineSetKey(t *testing.T) {
 	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKwmwKhVrEUaZTlHjhoWA4jwJLOF8TY+/NpHAXAHbAHl"}
 	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMdLcxVjCAQUHbD4jCfFP+f8v1nmyjWkq6rXiexrK8II"}
 
-	e, err := transparency.GetEngine(transparency.Sigsum)
+	_, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:    logKey,
+		SubmitKey: submitKey,
+	})
+
 	if err != nil {
 		t.Fatal(err)
 	}
+}
 
-	err = e.SetKey(logKey, submitKey)
+func TestNegativeSigsumEngineSetKey(t *testing.T) {
+	// invalid log key: truncated key material
+	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKwmwKhVrEUaZTlHjhoWA4jwJLOF8TY+/NpHAXAH"}
 
-	if err != nil {
+	_, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey: logKey,
+	})
+
+	if err == nil {
 		t.Fatal(err)
 	}
 }
@@ -68,32 +95,89 @@ group G any A-group B-group
 quorum G
 `)
 
-	e, err := transparency.GetEngine(transparency.Sigsum)
+	_, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		WitnessPolicy: policy,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
+}
+
+func TestSigsumEngineParseProof(t *testing.T) {
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{})
 
-	p, err := e.ParseWitnessPolicy(policy)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = e.SetWitnessPolicy(p)
-	if err != nil {
+	if _, _, err := e.ParseProof(validProofBundle); err != nil {
 		t.Fatal(err)
 	}
 }
 
-func TestSigsumEngineParseProof(t *testing.T) {
-	e, err := transparency.GetEngine(transparency.Sigsum)
+func TestSigsumParseBundle(t *testing.T) {
+	var proof string
 
+	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
+	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
+
+	b, err := transparency.ParseBundle(validProofBundle)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if _, _, err := e.ParseProof(validProofBundle); err != nil {
+	if b.Format() != transparency.Sigsum {
+		t.Fatalf("unexpected bundle format: %d", b.Format())
+	}
+
+	pb, ok := b.Native().(*ProofBundle)
+	if !ok {
+		t.Fatalf("unexpected native bundle: %T", b.Native())
+	}
+
+	// the statement is returned as logged, in compact JSON format
+	statement, err := json.Marshal(pb.Statement)
+	if err != nil {
 		t.Fatal(err)
 	}
+
+	if !bytes.Equal(b.Statement(), statement) {
+		t.Fatal("statement mismatch")
+	}
+
+	if err = json.Unmarshal(b.Proof(), &proof); err != nil || proof != pb.Proof {
+		t.Fatalf("inclusion proof mismatch: %v", err)
+	}
+
+	e, err := transparency.NewEngine(b.Format(), transparency.Config{
+		LogKey:        logKey,
+		SubmitKey:     submitKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = e.VerifyProof(b); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeParseBundle(t *testing.T) {
+	// error expected here as the bundle is not a valid JSON
+	if _, err := transparency.ParseBundle(validProofBundle[1:]); err == nil {
+		t.Fatal("invalid bundle has been parsed")
+	}
+
+	// error expected here as the bundle format is not registered
+	if _, err := transparency.ParseBundle([]byte(`{"format": 255, "statement": {}}`)); err == nil {
+		t.Fatal("bundle with unknown format has been parsed")
+	}
+
+	// error expected here as the probing data is not valid
+	if _, err := transparency.ParseBundle([]byte(`{"format": 1, "statement": {}, "probe": "invalid"}`)); err == nil {
+		t.Fatal("bundle with invalid probing data has been parsed")
+	}
 }
 
 /*
@@ -108,7 +192,7 @@ func TestSigsumEngineParseNilProof(t *testing.T) {
 		Probe:     probe,
 	}
 
-	e, err := transparency.GetEngine(transparency.Sigsum)
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{})
 
 	if err != nil {
 		t.Fatal(err)
@@ -154,7 +238,7 @@ node_hash=e8bb977d7ae35a4b7e591ded5e3d7fad0afee0b958d6309a52f48fe46c679c36
 		Proof:     proof,
 	}
 
-	e, err := transparency.GetEngine(transparency.Sigsum)
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{})
 
 	if err != nil {
 		t.Fatal(err)
@@ -177,20 +261,39 @@ func TestSigsumEngineNoCosignaturesVerifyProof(t *testing.T) {
 	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMCMTGNMNe1HP2us/dR5dBpyrSPDgPQ9mX5j9iqbLIS+",
 		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
 
-	e, err := transparency.GetEngine(transparency.Sigsum)
+	// the witness policy is not set, to induce the engine to verify the proof
+	// using the no-cosignature verification
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:    logKey,
+		SubmitKey: submitKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
 
+	pb, _, err := e.ParseProof(validProofBundle)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = e.SetKey(logKey, submitKey)
+	err = e.VerifyProof(pb)
 	if err != nil {
 		t.Fatal(err)
 	}
+}
 
-	// reset the witness policy to induce the engine to verify the proof using
-	// the no-cosignature verification
-	e.ResetWitnessPolicy()
+func TestSigsumEngineCosignaturesVerifyProof(t *testing.T) {
+	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
+	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
+
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:        logKey,
+		SubmitKey:     submitKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	pb, _, err := e.ParseProof(validProofBundle)
 	if err != nil {
@@ -203,27 +306,144 @@ func TestSigsumEngineNoCosignaturesVerifyProof(t *testing.T) {
 	}
 }
 
-func TestSigsumEngineCosignaturesVerifyProof(t *testing.T) {
-	e, err := transparency.GetEngine(transparency.Sigsum)
+func TestSigsumEngineCosignaturesVerifyProofInvalidLogKey(t *testing.T) {
+	// invalid log key (i.e. the only allowed key is not matching the log keyhash in the proof)
+	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKwmwKhVrEUaZTlHjhoWA4jwJLOF8TY+/NpHAXAHbAHl"}
+	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
 
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:        logKey,
+		SubmitKey:     submitKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	pb, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = e.VerifyProof(pb)
+	// VerifyProof must return the log keyhash mismatch error
+	if err != nil && err.Error() != "unknown log key hash" {
+		t.Fatal(err)
+	}
+}
+
+func TestSigsumEngineCosignaturesVerifyProofInvalidSubmitKey(t *testing.T) {
 	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
+	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMdLcxVjCAQUHbD4jCfFP+f8v1nmyjWkq6rXiexrK8II"}
+
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:        logKey,
+		SubmitKey:     submitKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = e.VerifyProof(pb)
+	// VerifyProof must return the leaf key hash (i.e. submitter's key) mismatch error
+	if err != nil && err.Error() != "unknown leaf key hash" {
+		t.Fatal(err)
+	}
+}
+
+func TestSigsumEngineCosignaturesVerifyProofNoLogKey(t *testing.T) {
 	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
 
-	err = e.SetKey(logKey, submitKey)
+	// the witness policy is set, but no trusted log key
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		SubmitKey:     submitKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	p, err := e.ParseWitnessPolicy(validWitnessPolicy)
+	pb, _, err := e.ParseProof(validProofBundle)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = e.SetWitnessPolicy(p)
+	// error expected here as the proof cannot be verified against any
+	// trusted log key
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("proof has been verified without any trusted log key")
+	}
+}
+
+func TestSigsumEngineConcurrentInstances(t *testing.T) {
+	var wg sync.WaitGroup
+
+	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
+	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
+
+	// engines configured with different freshness requirements must not
+	// affect each other when used concurrently
+	for i := range 16 {
+		valid := i%2 == 0
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cfg := transparency.Config{
+				LogKey:        logKey,
+				SubmitKey:     submitKey,
+				WitnessPolicy: validWitnessPolicy,
+			}
+
+			if !valid {
+				cfg.MaxAge = time.Second
+			}
+
+			e, err := transparency.NewEngine(transparency.Sigsum, cfg)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			pb, _, err := e.ParseProof(validProofBundle)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			for range 8 {
+				err = e.VerifyProof(pb)
+
+				if valid && err != nil {
+					t.Errorf("valid proof has been rejected: %v", err)
+				}
+
+				if !valid && err == nil {
+					t.Error("stale tree head has been accepted")
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestSigsumEngineGetProof(t *testing.T) {
+	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
+	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
+
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:        logKey,
+		SubmitKey:     submitKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -233,34 +453,99 @@ func TestSigsumEngineCosignaturesVerifyProof(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = e.VerifyProof(pb)
+	pr, err := e.GetProof(context.Background(), pb)
 	if err != nil {
 		t.Fatal(err)
 	}
-}
 
-func TestSigsumEngineCosignaturesVerifyProofInvalidLogKey(t *testing.T) {
-	e, err := transparency.GetEngine(transparency.Sigsum)
+	freshBundle := pb.(*ProofBundle)
+	freshBundle.Proof = string(pr)
 
+	err = e.VerifyProof(freshBundle)
 	if err != nil {
 		t.Fatal(err)
 	}
+}
 
-	// invalid log key (i.e. the only allowed key is not matching the log keyhash in the proof)
-	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKwmwKhVrEUaZTlHjhoWA4jwJLOF8TY+/NpHAXAHbAHl"}
+func TestSigsumEngineTrustState(t *testing.T) {
+	origin := "sigsum.org/v1/tree/4e89cc51651f0d95f3c6127c15e1a42e3ddf7046c5b17b752689c402e773bb4d"
+
+	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
 	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
 
-	err = e.SetKey(logKey, submitKey)
+	ts := &transparency.MemoryTrustState{}
+
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:     logKey,
+		SubmitKey:  submitKey,
+		TrustState: ts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// trust on first use
+	if err = e.VerifyProof(pb); err != nil {
+		t.Fatal(err)
+	}
+
+	th, err := ts.Load(origin)
+	if err != nil || th == nil || th.Size != 12414 {
+		t.Fatalf("unexpected trusted tree head: %v %v", th, err)
+	}
+
+	// same tree head
+	if err = e.VerifyProof(pb); err != nil {
+		t.Fatal(err)
+	}
+
+	// trusted tree head of the same size, but with a different root hash
+	err = ts.Store(&transparency.TreeHead{Origin: origin, Size: 12414, RootHash: make([]byte, 32)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the log presented a split view
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("tree head not matching the trusted one has been accepted")
+	}
+
+	err = ts.Store(&transparency.TreeHead{Origin: origin, Size: 12415, RootHash: make([]byte, 32)})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	p, err := e.ParseWitnessPolicy(validWitnessPolicy)
+	// error expected here as the tree head is older than the trusted one
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("tree head rollback has been accepted")
+	}
+
+	err = ts.Store(&transparency.TreeHead{Origin: origin, Size: 12413, RootHash: make([]byte, 32)})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = e.SetWitnessPolicy(p)
+	// error expected here as the consistency proof is missing
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("tree head without consistency proof has been accepted")
+	}
+}
+
+func TestSigsumEngineMaxAge(t *testing.T) {
+	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
+	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
+
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:        logKey,
+		SubmitKey:     submitKey,
+		WitnessPolicy: validWitnessPolicy,
+		MaxAge:        100 * 365 * 24 * time.Hour,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -270,90 +555,240 @@ func TestSigsumEngineCosignaturesVerifyProofInvalidLogKey(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = e.VerifyProof(pb)
-	// VerifyProof must return the log keyhash mismatch error
-	if err != nil && err.Error() != "unknown log key hash" {
+	if err = e.VerifyProof(pb); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err = transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:        logKey,
+		SubmitKey:     submitKey,
+		WitnessPolicy: validWitnessPolicy,
+		MaxAge:        time.Second,
+	})
+	if err != nil {
 		t.Fatal(err)
 	}
+
+	// error expected here as the test data cosignatures are older
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("stale tree head has been accepted")
+	}
 }
 
-func TestSigsumEngineCosignaturesVerifyProofInvalidSubmitKey(t *testing.T) {
-	e, err := transparency.GetEngine(transparency.Sigsum)
+func TestSigsumEngineVerifyConsistency(t *testing.T) {
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{})
 
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
-	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMdLcxVjCAQUHbD4jCfFP+f8v1nmyjWkq6rXiexrK8II"}
+	origin := "sigsum.org/v1/tree/4e89cc51651f0d95f3c6127c15e1a42e3ddf7046c5b17b752689c402e773bb4d"
+
+	tree := testonly.New(rfc6962.DefaultHasher)
+
+	for i := range 8 {
+		tree.AppendData(fmt.Appendf(nil, "leaf %d", i))
+	}
+
+	newTreeHead := &transparency.TreeHead{Origin: origin, Size: 8, RootHash: tree.HashAt(8)}
+
+	for size := uint64(0); size <= 8; size++ {
+		oldTreeHead := &transparency.TreeHead{Origin: origin, Size: size, RootHash: tree.HashAt(size)}
+
+		path, err := tree.ConsistencyProof(size, 8)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cp := &transparency.ConsistencyProof{OldSize: size, NewSize: 8, Path: path}
+
+		if err = e.VerifyConsistency(oldTreeHead, newTreeHead, cp); err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+	}
+}
+
+func TestSigsumEngineNegativeVerifyConsistency(t *testing.T) {
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{})
 
-	err = e.SetKey(logKey, submitKey)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	p, err := e.ParseWitnessPolicy(validWitnessPolicy)
+	origin := "sigsum.org/v1/tree/4e89cc51651f0d95f3c6127c15e1a42e3ddf7046c5b17b752689c402e773bb4d"
+
+	tree := testonly.New(rfc6962.DefaultHasher)
+
+	for i := range 8 {
+		tree.AppendData(fmt.Appendf(nil, "leaf %d", i))
+	}
+
+	oldTreeHead := &transparency.TreeHead{Origin: origin, Size: 5, RootHash: tree.HashAt(5)}
+	newTreeHead := &transparency.TreeHead{Origin: origin, Size: 8, RootHash: tree.HashAt(8)}
+
+	path, err := tree.ConsistencyProof(5, 8)
+
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = e.SetWitnessPolicy(p)
+	// error expected here as the proof is missing
+	if err = e.VerifyConsistency(oldTreeHead, newTreeHead, nil); err == nil {
+		t.Fatal("missing consistency proof has been accepted")
+	}
+
+	// error expected here as the proof sizes do not match the tree heads
+	if err = e.VerifyConsistency(oldTreeHead, newTreeHead, &transparency.ConsistencyProof{OldSize: 4, NewSize: 8, Path: path}); err == nil {
+		t.Fatal("consistency proof for different sizes has been accepted")
+	}
+
+	// error expected here as the tree heads belong to different logs
+	otherTreeHead := &transparency.TreeHead{Origin: "sigsum.org/v1/tree/00", Size: 8, RootHash: tree.HashAt(8)}
+
+	if err = e.VerifyConsistency(oldTreeHead, otherTreeHead, &transparency.ConsistencyProof{OldSize: 5, NewSize: 8, Path: path}); err == nil {
+		t.Fatal("tree heads from different logs have been accepted")
+	}
+
+	// error expected here as the proof has been tampered
+	path[0] = make([]byte, 32)
+
+	if err = e.VerifyConsistency(oldTreeHead, newTreeHead, &transparency.ConsistencyProof{OldSize: 5, NewSize: 8, Path: path}); err == nil {
+		t.Fatal("invalid consistency proof has been accepted")
+	}
+}
+
+func TestSigsumEngineLeafSigner(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	pb, _, err := e.ParseProof(validProofBundle)
+	s, err := newLeafSigner(priv)
+
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = e.VerifyProof(pb)
-	// VerifyProof must return the leaf key hash (i.e. submitter's key) mismatch error
-	if err != nil && err.Error() != "unknown leaf key hash" {
+	p := s.Public()
+
+	if !bytes.Equal(p[:], pub) {
+		t.Fatal("public key mismatch")
+	}
+
+	msg := []byte("boot-transparency")
+	sig, err := s.Sign(msg)
+
+	if err != nil {
 		t.Fatal(err)
 	}
+
+	if !ed25519.Verify(pub, msg, sig[:]) {
+		t.Fatal("invalid signature")
+	}
 }
 
-func TestSigsumEngineGetProof(t *testing.T) {
-	e, err := transparency.GetEngine(transparency.Sigsum)
+func TestSigsumEngineSubmit(t *testing.T) {
+	logURL, logKey, witnessPolicy := testlog.New(t)
 
+	pub, priv, err := ed25519.GenerateKey(nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
-	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
-
-	err = e.SetKey(logKey, submitKey)
+	sshPub, err := ssh.NewPublicKey(pub)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	p, err := e.ParseWitnessPolicy(validWitnessPolicy)
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:        []string{logKey},
+		SubmitKey:     []string{strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))},
+		WitnessPolicy: witnessPolicy,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = e.SetWitnessPolicy(p)
+	for _, s := range []struct {
+		statement string
+		canonical string
+	}{
+		{`{"description": "first"}`, `{"description":"first"}`},
+		{`{"version": "v1", "description": "<second>"}`, `{"description":"<second>","version":"v1"}`},
+	} {
+		pb, err := e.Submit(context.Background(), logURL, []byte(s.statement), priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b := pb.(*ProofBundle)
+
+		// the statement is logged in its canonical form
+		if string(b.Statement) != s.canonical {
+			t.Fatalf("unexpected logged statement: %s", b.Statement)
+		}
+
+		if b.Probe.Origin != logURL || b.Proof == "" {
+			t.Fatalf("unexpected proof bundle: %+v", b)
+		}
+
+		// the returned proof bundle must be accepted by the verifier
+		if err = e.VerifyProof(pb); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSigsumEngineNegativeSubmit(t *testing.T) {
+	logKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
+	submitKey := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
+
+	_, priv, err := ed25519.GenerateKey(nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	pb, _, err := e.ParseProof(validProofBundle)
+	e, err := transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:    logKey,
+		SubmitKey: submitKey,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	pr, err := e.GetProof(pb)
+	// error expected here as the witness policy is not set
+	if _, err = e.Submit(context.Background(), "https://sigsum.example.org/", []byte(`{}`), priv); err == nil {
+		t.Fatal("submission without witness policy has been accepted")
+	}
+
+	e, err = transparency.NewEngine(transparency.Sigsum, transparency.Config{
+		LogKey:        logKey,
+		SubmitKey:     submitKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	freshBundle := pb.(*ProofBundle)
-	freshBundle.Proof = string(pr)
+	// error expected here as the signer is not set
+	if _, err = e.Submit(context.Background(), "https://sigsum.example.org/", []byte(`{}`), nil); err == nil {
+		t.Fatal("submission without signer has been accepted")
+	}
 
-	err = e.VerifyProof(freshBundle)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	// error expected here as Sigsum only supports Ed25519 submitter keys
+	if _, err = e.Submit(context.Background(), "https://sigsum.example.org/", []byte(`{}`), ecKey); err == nil {
+		t.Fatal("submission with an ECDSA signer has been accepted")
+	}
+
+	// error expected here as the submitter key is not trusted
+	if _, err = e.Submit(context.Background(), "https://sigsum.example.org/", []byte(`{}`), priv); err == nil {
+		t.Fatal("submission with an untrusted signer has been accepted")
+	}
 }
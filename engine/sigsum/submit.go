@@ -0,0 +1,199 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package sigsum
+
+import (
+	"context"
+	stdcrypto "crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	bt_statement "github.com/usbarmory/boot-transparency/statement"
+	"github.com/usbarmory/boot-transparency/transparency"
+	"sigsum.org/sigsum-go/pkg/crypto"
+	"sigsum.org/sigsum-go/pkg/key"
+	"sigsum.org/sigsum-go/pkg/requests"
+	"sigsum.org/sigsum-go/pkg/types"
+)
+
+const (
+	// maximum time to wait for the statement to be included in the log
+	submitTimeout = 5 * time.Minute
+	// interval between log requests while waiting for the inclusion
+	submitPollInterval = 2 * time.Second
+)
+
+// Define a Sigsum signer backed by a standard library signer,
+// Sigsum only supports Ed25519 submitter keys.
+type leafSigner struct {
+	signer stdcrypto.Signer
+	pub    crypto.PublicKey
+}
+
+func newLeafSigner(signer stdcrypto.Signer) (*leafSigner, error) {
+	if signer == nil {
+		return nil, fmt.Errorf("signer is not set")
+	}
+
+	pub, ok := signer.Public().(ed25519.PublicKey)
+
+	if !ok || len(pub) != crypto.PublicKeySize {
+		return nil, fmt.Errorf("unsupported signer, Sigsum requires an Ed25519 key")
+	}
+
+	return &leafSigner{
+		signer: signer,
+		pub:    crypto.PublicKey(pub),
+	}, nil
+}
+
+func (s *leafSigner) Sign(msg []byte) (sig crypto.Signature, err error) {
+	// Ed25519 signs the message itself, rather than its digest
+	b, err := s.signer.Sign(rand.Reader, msg, stdcrypto.Hash(0))
+
+	if err != nil {
+		return
+	}
+
+	if len(b) != crypto.SignatureSize {
+		return sig, fmt.Errorf("invalid signature size %d", len(b))
+	}
+
+	copy(sig[:], b)
+
+	return
+}
+
+func (s *leafSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// The logic implemented for the Sigsum engine is partially replicating
+// the submit flow from sigsum-go/pkg/submit/submit.go
+func (e *SigsumEngine) Submit(ctx context.Context, logURL string, statement []byte, signer stdcrypto.Signer) (interface{}, error) {
+	var persisted bool
+
+	// the inclusion proof cannot be collected without a witness policy,
+	// therefore the leaf must not be submitted in the first place
+	if e.witnessPolicy == nil {
+		return nil, fmt.Errorf("witness policy not configured")
+	}
+
+	if len(e.logPubkey) == 0 {
+		return nil, fmt.Errorf("trusted log public key is not set")
+	}
+
+	s, err := newLeafSigner(signer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pub := s.Public()
+	keyHash := crypto.HashBytes(pub[:])
+
+	// the submitter key must be trusted to collect the inclusion proof
+	if _, err = getTrustedKeyFromHash(e.submitPubkey, hex.EncodeToString(keyHash[:])); err != nil {
+		return nil, fmt.Errorf("submit public key is not trusted: %v", err)
+	}
+
+	// the statement is logged in its canonical form to ensure the message
+	// is independent from its formatting (i.e. indent spaces, or tabs,
+	// that would be present in human-readable statement JSON)
+	canonical, err := bt_statement.Canonicalize(statement)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement: %v", err)
+	}
+
+	// the logged message is a sha256 of the statement, including
+	// the trailing newline, consistently with VerifyProof()
+	msg := crypto.Hash(sha256.Sum256(append(canonical, "\n"...)))
+
+	sig, err := types.SignLeafMessage(s, msg[:])
+
+	if err != nil {
+		return nil, fmt.Errorf("signing leaf: %v", err)
+	}
+
+	client, err := e.newClient(logURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, submitTimeout)
+	defer cancel()
+
+	req := requests.Leaf{
+		Message:   msg,
+		Signature: sig,
+		PublicKey: pub,
+	}
+
+	// the log accepts the leaf first, then it reports it as persisted
+	// once it has been sequenced
+	for !persisted {
+		if persisted, err = client.AddLeaf(ctx, req, nil); err != nil {
+			return nil, fmt.Errorf("adding leaf: %v", err)
+		}
+
+		if !persisted {
+			if err = sleep(ctx, submitPollInterval); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	pb := &ProofBundle{
+		Format:    transparency.Sigsum,
+		Statement: canonical,
+		Probe: Probe{
+			Origin:              logURL,
+			LeafSignature:       hex.EncodeToString(sig[:]),
+			SubmitPublicKeyHash: hex.EncodeToString(keyHash[:]),
+		},
+	}
+
+	// wait for a cosigned tree head including the leaf, the log key
+	// is not known in advance so all trusted ones are attempted
+	for {
+		for _, k := range e.logPubkey {
+			var lk crypto.PublicKey
+
+			if lk, err = key.ParsePublicKey(k); err != nil {
+				return nil, fmt.Errorf("invalid log public key: %s", k)
+			}
+
+			logKeyHash := crypto.HashBytes(lk[:])
+			pb.Probe.LogPublicKeyHash = hex.EncodeToString(logKeyHash[:])
+
+			if _, err = e.GetProof(ctx, pb); err == nil {
+				return pb, nil
+			}
+		}
+
+		if sleep(ctx, submitPollInterval) != nil {
+			return nil, fmt.Errorf("waiting for inclusion proof: %v", err)
+		}
+	}
+}
+
+// Wait for the given interval, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
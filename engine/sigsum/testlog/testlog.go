@@ -0,0 +1,241 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package testlog
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/transparency-dev/merkle/rfc6962"
+	"github.com/transparency-dev/merkle/testonly"
+	"golang.org/x/crypto/ssh"
+	"sigsum.org/sigsum-go/pkg/crypto"
+	"sigsum.org/sigsum-go/pkg/requests"
+	"sigsum.org/sigsum-go/pkg/types"
+)
+
+// Define an in-memory Sigsum log, each leaf is sequenced as soon as it
+// is added.
+type log struct {
+	sync.Mutex
+
+	signer    crypto.Signer
+	cosigners []crypto.Signer
+	tree      *testonly.Tree
+	leaves    map[crypto.Hash]bool
+}
+
+// Return a new Sigsum log, for testing, served over HTTP until the end of
+// the test. Each served tree head is cosigned by two witnesses, both
+// required by the returned witness policy. The log includes an initial
+// leaf, so that inclusion proofs are never empty.
+func New(t testing.TB) (logURL string, logKey string, witnessPolicy []byte) {
+	pub, signer, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(ed25519.PublicKey(pub[:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := &log{
+		signer: signer,
+		tree:   testonly.New(rfc6962.DefaultHasher),
+		leaves: make(map[crypto.Hash]bool),
+	}
+
+	l.tree.AppendData([]byte("initial leaf"))
+
+	witnessPolicy = fmt.Appendf(witnessPolicy, "log %x\n", pub[:])
+
+	for _, name := range []string{"w1", "w2"} {
+		wpub, cosigner, err := crypto.NewKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		l.cosigners = append(l.cosigners, cosigner)
+		witnessPolicy = fmt.Appendf(witnessPolicy, "witness %s %x\n", name, wpub[:])
+	}
+
+	witnessPolicy = append(witnessPolicy, "group g1 all w1 w2\nquorum g1\n"...)
+
+	srv := httptest.NewServer(l)
+	t.Cleanup(srv.Close)
+
+	return srv.URL + "/", strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))), witnessPolicy
+}
+
+// Serve the Sigsum log API endpoints, regardless of the separator between
+// the log URL and the endpoint.
+func (l *log) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	l.Lock()
+	defer l.Unlock()
+
+	endpoint := strings.Split(strings.TrimLeft(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.Method == http.MethodPost && endpoint[0] == "add-leaf":
+		err = l.addLeaf(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(endpoint[0], "get-tree-head"):
+		err = l.getTreeHead(w)
+	case r.Method == http.MethodGet && endpoint[0] == "get-inclusion-proof" && len(endpoint) == 3:
+		err = l.getInclusionProof(w, endpoint[1], endpoint[2])
+	case r.Method == http.MethodGet && endpoint[0] == "get-consistency-proof" && len(endpoint) == 3:
+		err = l.getConsistencyProof(w, endpoint[1], endpoint[2])
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+func (l *log) addLeaf(w http.ResponseWriter, r *http.Request) error {
+	var req requests.Leaf
+
+	if err := req.FromASCII(r.Body); err != nil {
+		return err
+	}
+
+	leaf := types.Leaf{
+		Checksum:  crypto.HashBytes(req.Message[:]),
+		Signature: req.Signature,
+		KeyHash:   crypto.HashBytes(req.PublicKey[:]),
+	}
+
+	leafHash := leaf.ToHash()
+
+	// duplicate leaves are accepted, but logged only once
+	if !l.leaves[leafHash] {
+		l.leaves[leafHash] = true
+		l.tree.Append(leafHash[:])
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	return nil
+}
+
+func (l *log) getTreeHead(w http.ResponseWriter) error {
+	th := types.TreeHead{
+		Size:     l.tree.Size(),
+		RootHash: crypto.Hash(l.tree.Hash()),
+	}
+
+	sth, err := th.Sign(l.signer)
+
+	if err != nil {
+		return err
+	}
+
+	logPub := l.signer.Public()
+	logKeyHash := crypto.HashBytes(logPub[:])
+
+	cth := types.CosignedTreeHead{
+		SignedTreeHead: sth,
+		Cosignatures:   make(map[crypto.Hash]types.Cosignature),
+	}
+
+	for _, cosigner := range l.cosigners {
+		cs, err := th.Cosign(cosigner, &logKeyHash, uint64(time.Now().Unix()))
+
+		if err != nil {
+			return err
+		}
+
+		pub := cosigner.Public()
+		cth.Cosignatures[crypto.HashBytes(pub[:])] = cs
+	}
+
+	return cth.ToASCII(w)
+}
+
+func (l *log) getInclusionProof(w http.ResponseWriter, sizeParam string, leafHashParam string) error {
+	size, err := strconv.ParseUint(sizeParam, 10, 64)
+
+	if err != nil || size > l.tree.Size() {
+		return fmt.Errorf("invalid tree size %q", sizeParam)
+	}
+
+	h, err := hex.DecodeString(leafHashParam)
+
+	if err != nil || len(h) != len(crypto.Hash{}) {
+		return fmt.Errorf("invalid leaf hash %q", leafHashParam)
+	}
+
+	for i := uint64(0); i < size; i++ {
+		if !bytes.Equal(l.tree.LeafHash(i), h) {
+			continue
+		}
+
+		path, err := l.tree.InclusionProof(i, size)
+
+		if err != nil {
+			return err
+		}
+
+		p := types.InclusionProof{
+			LeafIndex: i,
+			Path:      hashes(path),
+		}
+
+		return p.ToASCII(w)
+	}
+
+	return fmt.Errorf("leaf not found")
+}
+
+func (l *log) getConsistencyProof(w http.ResponseWriter, oldSizeParam string, newSizeParam string) error {
+	oldSize, err := strconv.ParseUint(oldSizeParam, 10, 64)
+
+	if err != nil {
+		return fmt.Errorf("invalid tree size %q", oldSizeParam)
+	}
+
+	newSize, err := strconv.ParseUint(newSizeParam, 10, 64)
+
+	if err != nil || oldSize > newSize || newSize > l.tree.Size() {
+		return fmt.Errorf("invalid tree size %q", newSizeParam)
+	}
+
+	path, err := l.tree.ConsistencyProof(oldSize, newSize)
+
+	if err != nil {
+		return err
+	}
+
+	p := types.ConsistencyProof{
+		Path: hashes(path),
+	}
+
+	return p.ToASCII(w)
+}
+
+func hashes(path [][]byte) (h []crypto.Hash) {
+	for _, p := range path {
+		h = append(h, crypto.Hash(p))
+	}
+
+	return
+}
@@ -14,10 +14,11 @@ type Probe struct {
 	Origin string `json:"origin"`
 	// Leaf index
 	LeafIdx uint64 `json:"leafIdx"`
-	// Tree size
-	TreeSize uint64 `json:"treeSize"`
-	// Root hash
-	Root []byte `json:"root"`
+	// Signed checkpoint note, including the witness cosignatures,
+	// as served by the log. The tree size and the root hash used to
+	// verify the inclusion proof are read from the checkpoint only
+	// after its log signature has been verified.
+	Checkpoint string `json:"checkpoint,omitempty"`
 	// the LeafHash is not present as it is computed hashing
 	// the ProofBundle.Statement.
 	// LeafHash []byte   `json:"leafHash"`
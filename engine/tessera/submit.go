@@ -0,0 +1,144 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package tessera
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	bt_statement "github.com/usbarmory/boot-transparency/statement"
+	"github.com/usbarmory/boot-transparency/transparency"
+)
+
+const (
+	// maximum time to wait for the statement to be included in the log
+	submitTimeout = 5 * time.Minute
+	// interval between log requests while waiting for the inclusion
+	submitPollInterval = 2 * time.Second
+	// maximum size of the add endpoint response
+	maxAddResponseSize = 1024
+)
+
+// The statement is submitted to the Tessera personality add endpoint
+// (i.e. HTTP POST to <logURL>/add) which is expected to return the
+// assigned leaf index, as ASCII decimal.
+// The signer is not used, as Tessera does not authenticate submissions,
+// therefore it can be nil.
+func (e *TesseraEngine) Submit(ctx context.Context, logURL string, statement []byte, signer crypto.Signer) (interface{}, error) {
+	var err error
+	var idx uint64
+
+	if e.witnessPolicy == nil {
+		return nil, fmt.Errorf("witness policy not configured")
+	}
+
+	if len(e.logPubkey) == 0 {
+		return nil, fmt.Errorf("log public key is not set")
+	}
+
+	// the statement is logged in its canonical form to ensure the message
+	// is independent from its formatting (i.e. indent spaces, or tabs,
+	// that would be present in human-readable statement JSON)
+	canonical, err := bt_statement.Canonicalize(statement)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, submitTimeout)
+	defer cancel()
+
+	if idx, err = addEntry(ctx, e.httpClient, logURL, canonical); err != nil {
+		return nil, err
+	}
+
+	pb := &ProofBundle{
+		Format:    transparency.Tessera,
+		Statement: canonical,
+		Probe: Probe{
+			Origin:  logURL,
+			LeafIdx: idx,
+		},
+	}
+
+	// wait for a checkpoint including the leaf, the log key
+	// is not known in advance so all trusted ones are attempted
+	for {
+		for _, k := range e.logPubkey {
+			pb.Probe.LogPublicKey = k
+
+			if _, err = e.GetProof(ctx, pb); err == nil {
+				return pb, nil
+			}
+		}
+
+		if sleep(ctx, submitPollInterval) != nil {
+			return nil, fmt.Errorf("waiting for inclusion proof: %v", err)
+		}
+	}
+}
+
+// Add an entry to the log, via the personality add endpoint,
+// and return its leaf index.
+func addEntry(ctx context.Context, httpClient *http.Client, logURL string, entry []byte) (idx uint64, err error) {
+	u, err := url.Parse(logURL)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid log URL: %s", err)
+	}
+
+	u = u.JoinPath("add")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(entry))
+
+	if err != nil {
+		return
+	}
+
+	res, err := httpClient.Do(req)
+
+	if err != nil {
+		return 0, fmt.Errorf("adding entry: %v", err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxAddResponseSize))
+
+	if err != nil {
+		return 0, fmt.Errorf("adding entry: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("adding entry: log returned %s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+
+	if idx, err = strconv.ParseUint(strings.TrimSpace(string(body)), 10, 64); err != nil {
+		return 0, fmt.Errorf("adding entry: invalid leaf index: %v", err)
+	}
+
+	return
+}
+
+// Wait for the given interval, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
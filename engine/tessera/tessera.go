@@ -8,17 +8,23 @@
 package tessera
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
+	"github.com/transparency-dev/formats/log"
+	f_note "github.com/transparency-dev/formats/note"
 	"github.com/transparency-dev/merkle/proof"
 	"github.com/transparency-dev/merkle/rfc6962"
 	"github.com/transparency-dev/tessera"
 	"github.com/transparency-dev/tessera/client"
+	bt_statement "github.com/usbarmory/boot-transparency/statement"
 	"github.com/usbarmory/boot-transparency/transparency"
 	"golang.org/x/mod/sumdb/note"
 )
@@ -30,17 +36,59 @@ type TesseraEngine struct {
 	// the witness policy, the actual format should be aligned with
 	// the one supported one by the chosen transparency engine
 	witnessPolicy *tessera.WitnessGroup
+	// latest trusted checkpoint, for each log origin
+	trustState transparency.TrustState
+	// maximum age of the checkpoint cosignatures
+	maxAge time.Duration
+	// network configuration to reach the logs
+	network transparency.Network
+	// HTTP client for log requests
+	httpClient *http.Client
 }
 
 func init() {
-	e := TesseraEngine{}
-	transparency.Add(&e, transparency.Tessera)
+	transparency.Register(transparency.Tessera, func(cfg transparency.Config) (transparency.Engine, error) {
+		return New(cfg)
+	})
 }
 
-func (e *TesseraEngine) GetProof(proofBundle interface{}) ([]byte, error) {
-	var logReadBaseURL *url.URL
-	var logReadCP client.CheckpointFetcherFunc
-	var logReadTile client.TileFetcherFunc
+// Return a new Tessera transparency engine instance, for the given
+// configuration. Tessera does not use the submit keys.
+func New(cfg transparency.Config) (e *TesseraEngine, err error) {
+	e = &TesseraEngine{
+		trustState: cfg.TrustState,
+		maxAge:     cfg.MaxAge,
+		network:    cfg.Network,
+		httpClient: cfg.Network.Client(),
+	}
+
+	// parse and load log public key(s) that needs to be compliant with note format
+	for _, k := range cfg.LogKey {
+		if _, err = note.NewVerifier(k); err != nil {
+			return nil, fmt.Errorf("invalid log public key: %v", err)
+		}
+
+		e.logPubkey = append(e.logPubkey, k)
+	}
+
+	if len(cfg.WitnessPolicy) > 0 {
+		p, err := tessera.NewWitnessGroupFromPolicy(cfg.WitnessPolicy)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid witness policy: %v", err)
+		}
+
+		e.witnessPolicy = &p
+	}
+
+	return
+}
+
+func (e *TesseraEngine) GetProof(ctx context.Context, proofBundle interface{}) ([]byte, error) {
+	// the engine-agnostic form, if any, is updated along with the
+	// engine proof bundle
+	bundle := proofBundle
+	proofBundle = transparency.NativeBundle(proofBundle)
 
 	if _, ok := proofBundle.(*ProofBundle); !ok {
 		return nil, fmt.Errorf("invalid·proof bundle for Tessera engine")
@@ -75,133 +123,174 @@ func (e *TesseraEngine) GetProof(proofBundle interface{}) ([]byte, error) {
 		return nil, fmt.Errorf("failed to load log public key: %v", err)
 	}
 
-	logReadBaseURL, err = url.Parse(pb.Probe.Origin)
+	leafHashes, err := statementLeafHashes(pb.Statement)
+
 	if err != nil {
-		return nil, fmt.Errorf("invalid log origin: %s", err)
+		return nil, err
 	}
 
-	switch logReadBaseURL.Scheme {
-	case "http", "https":
-		hf, err := client.NewHTTPFetcher(logReadBaseURL, nil)
+	var ip [][]byte
+	var rawcp []byte
+	var cp *log.Checkpoint
+
+	// the log origin, or its mirrors, are queried until a verified
+	// inclusion proof is collected
+	err = e.network.Do(ctx, pb.Probe.Origin, func(ctx context.Context, logURL string) (err error) {
+		logReadCP, logReadTile, err := e.newFetchers(logURL)
+
 		if err != nil {
-			return nil, fmt.Errorf("failed to create an http fetcher: %v", err)
+			return
 		}
-		logReadCP = hf.ReadCheckpoint
-		logReadTile = hf.ReadTile
-	case "file":
-		ff := client.FileFetcher{Root: logReadBaseURL.Path}
-		logReadCP = ff.ReadCheckpoint
-		logReadTile = ff.ReadTile
-	default:
-		return nil, fmt.Errorf("unsupported url scheme: %s", logReadBaseURL.Scheme)
-	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(30*time.Second))
-	defer cancel()
+		// get the latest checkpoint, its log signature is verified while parsing it
+		// and the expected checkpoint origin is the one bound to the log key
+		if cp, rawcp, _, err = client.FetchCheckpoint(ctx, logReadCP, logVerifier, logVerifier.Name()); err != nil {
+			return fmt.Errorf("fetching checkpoint: %v", err)
+		}
 
-	// get the latest checkpoint
-	// previous checkpoint (third argument) is passed as nil, thus the tracker function will
-	// "only" fetch the latest checkpoint and will not return any consistency proof.
-	//lst, err := client.NewLogStateTracker(ctx, logReadTile, nil, logVerifier, probe.Origin, client.UnilateralConsensus(logReadCP))
-	//if err != nil {
-	//	return fmt.Errorf("tessera client: %v", err)
-	//}
+		// verify that checkpoint co-signatures are satisfying the witness policy
+		if !e.witnessPolicy.Satisfied(rawcp) {
+			return fmt.Errorf("invalid checkpoint: witness policy not satisfied")
+		}
 
-	// verify that checkpoint co-signatures are satisfying the witness policy
-	cp, rawcp, _, err := client.FetchCheckpoint(ctx, logReadCP, logVerifier, pb.Probe.Origin)
-	if err != nil {
-		return nil, fmt.Errorf("fecthing checkpoint: %v", err)
-	}
+		// creates the proof builder that will be used to assemble proofs
+		// according with the passed (i.e. latest) checkpoint
+		pBuilder, err := client.NewProofBuilder(ctx, cp.Size, logReadTile)
 
-	if !e.witnessPolicy.Satisfied(rawcp) {
-		return nil, fmt.Errorf("invalid checkpoint: %v", err)
-	}
+		if err != nil {
+			return fmt.Errorf("tessera proof builder: %v", err)
+		}
+
+		// get the inclusion proof given the latest checkpoint
+		if ip, err = pBuilder.InclusionProof(ctx, pb.Probe.LeafIdx); err != nil {
+			return fmt.Errorf("getting inclusion proof: %v", err)
+		}
+
+		// verify the inclusion proof is valid
+		if err = verifyInclusion(pb.Probe.LeafIdx, cp.Size, leafHashes, ip, cp.Hash); err != nil {
+			return fmt.Errorf("invalid inclusion proof: %v", err)
+		}
 
-	// creates the proof builder that will be used to assemble proofs
-	// according with the passed (i.e. latest) checkpoint
-	pBuilder, err := client.NewProofBuilder(ctx, cp.Size, logReadTile)
+		return
+	})
 
 	if err != nil {
-		return nil, fmt.Errorf("tessera proof builder: %v", err)
+		return nil, err
 	}
 
-	// get the inclusion proof given the latest checkpoint
-	ip, err := pBuilder.InclusionProof(ctx, pb.Probe.LeafIdx)
+	// Tessera stores inclusion proof(s) as array of byte arrays ([][]byte)
+	// but p.Proof is json.RawMessage which is defined as []byte
+	builtProof, err := json.Marshal(ip)
 
 	if err != nil {
-		return nil, fmt.Errorf("getting inclusion proof: %v", err)
+		return nil, err
 	}
 
-	// JSON marshalling is required to ensure the message has been logged
-	// independently from its formatting (i.e. indent spaces, or tabs,
-	// that would be present in human-readable statement JSON)
-	statement, err := json.Marshal(pb.Statement)
+	// the fetched checkpoint must be proven consistent with the trusted
+	// one, for the bundle to be verified once the log has grown
+	consistency, err := e.trustedConsistency(ctx, pb.Probe.Origin, cp.Origin, cp.Size)
 
 	if err != nil {
 		return nil, err
 	}
 
-	leafHash := rfc6962.DefaultHasher.HashLeaf(fmt.Append(nil, statement))
+	// the inclusion proof is relative to the fetched checkpoint, which
+	// replaces the one in the probing data to keep the bundle consistent
+	pb.Probe.Checkpoint = string(rawcp)
+	pb.Consistency = consistency
+	pb.Proof = make([]string, len(ip))
 
-	// verify the inclusion proof is valid
-	if err = proof.VerifyInclusion(rfc6962.DefaultHasher, pb.Probe.LeafIdx, cp.Size, leafHash, ip, cp.Hash); err != nil {
-		return nil, fmt.Errorf("invalid inclusion proof: %v", err)
+	for i, h := range ip {
+		pb.Proof[i] = base64.StdEncoding.EncodeToString(h)
 	}
 
-	// Tessera stores inclusion proof(s) as array of byte arrays ([][]byte)
-	// but p.Proof is json.RawMessage which is defined as []byte
-	builtProof, err := json.Marshal(ip)
-
-	if err != nil {
+	if err = transparency.UpdateBundle(bundle); err != nil {
 		return nil, err
 	}
 
 	return builtProof, nil
 }
 
-func (e *TesseraEngine) ParseWitnessPolicy(wp []byte) (interface{}, error) {
-	p, err := tessera.NewWitnessGroupFromPolicy(wp)
+// Return a consistency proof between the trusted checkpoint, for a given log
+// origin, and a more recent checkpoint. No proof is returned if the trust
+// state is not set, or the trusted checkpoint is not older than the given one.
+func (e *TesseraEngine) trustedConsistency(ctx context.Context, logURL string, origin string, size uint64) (*transparency.ConsistencyProof, error) {
+	if e.trustState == nil {
+		return nil, nil
+	}
+
+	trusted, err := e.trustState.Load(origin)
 
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("cannot load trusted tree head: %v", err)
+	}
+
+	if trusted == nil || trusted.Size >= size {
+		return nil, nil
 	}
 
-	return &p, err
+	return e.GetConsistencyProof(ctx, logURL, trusted.Size, size)
 }
 
-func (e *TesseraEngine) SetKey(logKey []string, submitKey []string) (err error) {
-	// re-set any previously stored key
-	e.logPubkey = []string{}
+func (e *TesseraEngine) GetConsistencyProof(ctx context.Context, origin string, oldSize uint64, newSize uint64) (*transparency.ConsistencyProof, error) {
+	if oldSize > newSize {
+		return nil, fmt.Errorf("invalid tree sizes, old size %d is greater than new size %d", oldSize, newSize)
+	}
 
-	// parse and load log public key(s) that needs to be compliant with note format
-	for _, k := range logKey {
-		_, err = note.NewVerifier(k)
+	var p [][]byte
+
+	err := e.network.Do(ctx, origin, func(ctx context.Context, logURL string) (err error) {
+		_, logReadTile, err := e.newFetchers(logURL)
 
 		if err != nil {
 			return
 		}
 
-		e.logPubkey = append(e.logPubkey, k)
-	}
+		pBuilder, err := client.NewProofBuilder(ctx, newSize, logReadTile)
 
-	return
-}
+		if err != nil {
+			return fmt.Errorf("tessera proof builder: %v", err)
+		}
 
-func (e *TesseraEngine) SetWitnessPolicy(wp interface{}) (err error) {
-	if _, ok := wp.(*tessera.WitnessGroup); !ok {
-		return fmt.Errorf("invalid policy, type assertion to Tessera *tessera.WitnessGroup failed")
+		if p, err = pBuilder.ConsistencyProof(ctx, oldSize, newSize); err != nil {
+			return fmt.Errorf("getting consistency proof: %v", err)
+		}
+
+		return
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	e.witnessPolicy = wp.(*tessera.WitnessGroup)
+	cp := &transparency.ConsistencyProof{
+		OldSize: oldSize,
+		NewSize: newSize,
+		Path:    p,
+	}
 
-	return
+	if cp.Path == nil {
+		cp.Path = [][]byte{}
+	}
+
+	return cp, nil
 }
 
-func (e *TesseraEngine) ResetWitnessPolicy() {
-	e.witnessPolicy = nil
+func (e *TesseraEngine) VerifyConsistency(oldTreeHead *transparency.TreeHead, newTreeHead *transparency.TreeHead, proof *transparency.ConsistencyProof) error {
+	if oldTreeHead == nil || newTreeHead == nil {
+		return fmt.Errorf("tree heads are not set")
+	}
+
+	if oldTreeHead.Origin != newTreeHead.Origin {
+		return fmt.Errorf("tree heads origin mismatch, %s and %s", oldTreeHead.Origin, newTreeHead.Origin)
+	}
+
+	return verifyConsistency(oldTreeHead, newTreeHead, proof)
 }
 
 func (e *TesseraEngine) VerifyProof(proofBundle interface{}) (err error) {
+	proofBundle = transparency.NativeBundle(proofBundle)
+
 	if _, ok := proofBundle.(*ProofBundle); !ok {
 		return fmt.Errorf("invalid proof bundle for Tessera engine")
 	}
@@ -213,46 +302,62 @@ func (e *TesseraEngine) VerifyProof(proofBundle interface{}) (err error) {
 		return fmt.Errorf("invalid bundle format %d, expected %d (transparency.Tessera)", pb.Format, transparency.Tessera)
 	}
 
-	// load the statement and compute its checksum, which is the leaf hash
-	// JSON marshal is required to ensure the message has been logged
-	// independently from its formatting (i.e. indent spaces, or tabs,
-	// that would be present in human-readable statement JSON)
-	statement, err := json.Marshal(pb.Statement)
+	// load the statement and compute its checksums, which are the
+	// candidate leaf hashes
+	leafHashes, err := statementLeafHashes(pb.Statement)
 
 	if err != nil {
 		return
 	}
 
-	leafHash := rfc6962.DefaultHasher.HashLeaf(statement)
-
 	// check if at least one trusted log key has been set
 	if len(e.logPubkey) == 0 {
 		return fmt.Errorf("log public key is not set")
 	}
 
+	// the root hash and the tree size are taken from the signed checkpoint
+	// thus the checkpoint must be present and verified before trusting them
+	if pb.Probe.Checkpoint == "" {
+		return fmt.Errorf("checkpoint is not present in the proof bundle")
+	}
+
+	cp, err := e.verifyCheckpoint([]byte(pb.Probe.Checkpoint))
+
+	if err != nil {
+		return
+	}
+
 	// convert the inclusion proof, from []string to [][]byte
 	// as expected by Tessera
-	ip := inclusionProofFromJSON(pb.Proof)
+	ip, err := inclusionProofFromJSON(pb.Proof)
 
-	// traverse all log keys and attempt to verify the proof
-	for _, logKey := range e.logPubkey {
-		// FIXME this is not a valid check confirming that the log key
-		// actually used to sign the inclusion proof is a trusted one.
-		// witness group must be satisfied when verifying co-signatures
-		//  on the tree head
-		if logKey != pb.Probe.LogPublicKey {
-			err = fmt.Errorf("unknown log public key")
-			continue // try the next trusted log key
-		}
+	if err != nil {
+		return
+	}
 
-		err = proof.VerifyInclusion(rfc6962.DefaultHasher, pb.Probe.LeafIdx, pb.Probe.TreeSize, leafHash, ip, pb.Probe.Root)
+	if err = verifyInclusion(pb.Probe.LeafIdx, cp.Size, leafHashes, ip, cp.Hash); err != nil {
+		return
+	}
 
-		if err != nil {
-			continue // try proof verification with the next log key, if any
-		}
+	if err = e.checkFreshness([]byte(pb.Probe.Checkpoint)); err != nil {
+		return
 	}
 
-	return
+	if e.trustState == nil {
+		return
+	}
+
+	th := &transparency.TreeHead{
+		Origin:   cp.Origin,
+		Size:     cp.Size,
+		RootHash: cp.Hash,
+	}
+
+	// reject rollbacks and ensure that the trusted checkpoint
+	// is consistent with the one in the proof bundle
+	return transparency.CheckTrustState(e.trustState, th, func(trusted *transparency.TreeHead) error {
+		return verifyConsistency(trusted, th, pb.Consistency)
+	})
 }
 
 func (e *TesseraEngine) ParseProof(jsonProofBundle []byte) (interface{}, []byte, error) {
@@ -328,15 +433,176 @@ func getTrustedKey(trusted []string, probe string) (string, error) {
 	return "", fmt.Errorf("public key is not matching any of the trusted keys")
 }
 
+// Verify the log signature, and the witness cosignatures, of a given checkpoint.
+// The checkpoint is parsed only if it is signed by one of the trusted log keys.
+func (e *TesseraEngine) verifyCheckpoint(rawcp []byte) (*log.Checkpoint, error) {
+	var cp *log.Checkpoint
+	var err error
+
+	// the checkpoint root is trusted only once its cosignatures satisfy
+	// the witness policy, as done when fetching proofs online
+	if e.witnessPolicy == nil {
+		return nil, fmt.Errorf("witness policy not configured")
+	}
+
+	// traverse all trusted log keys and attempt to open the checkpoint note,
+	// the expected checkpoint origin is the one bound to the log key
+	for _, logKey := range e.logPubkey {
+		v, verr := note.NewVerifier(logKey)
+
+		// return immediately when encountering an invalid public key
+		if verr != nil {
+			return nil, fmt.Errorf("invalid log public key: %s", logKey)
+		}
+
+		if cp, _, _, err = log.ParseCheckpoint(rawcp, v.Name(), v); err == nil {
+			break
+		}
+	}
+
+	if cp == nil {
+		return nil, fmt.Errorf("checkpoint is not signed by a trusted log key: %v", err)
+	}
+
+	if !e.witnessPolicy.Satisfied(rawcp) {
+		return nil, fmt.Errorf("checkpoint cosignatures do not satisfy the witness policy")
+	}
+
+	return cp, nil
+}
+
+// Verify that the checkpoint cosignatures, satisfying the witness policy,
+// are not older than the configured maximum age.
+func (e *TesseraEngine) checkFreshness(rawcp []byte) error {
+	var fresh bytes.Buffer
+
+	if e.maxAge == 0 {
+		return nil
+	}
+
+	if e.witnessPolicy == nil {
+		return fmt.Errorf("witness policy not configured, cannot check the checkpoint age")
+	}
+
+	// split the checkpoint text from the signature lines
+	i := bytes.LastIndex(rawcp, []byte("\n\n"))
+
+	if i < 0 {
+		return fmt.Errorf("invalid checkpoint note")
+	}
+
+	fresh.Write(rawcp[:i+2])
+	notBefore := time.Now().Add(-e.maxAge)
+
+	// keep only the cosignatures with a timestamp within the maximum age,
+	// those are the only ones considered to satisfy the witness policy
+	for _, line := range strings.SplitAfter(string(rawcp[i+2:]), "\n") {
+		fields := strings.Fields(line)
+
+		if len(fields) != 3 {
+			continue
+		}
+
+		t, err := f_note.CoSigV1Timestamp(note.Signature{Base64: fields[2]})
+
+		if err != nil || t.Before(notBefore) {
+			continue
+		}
+
+		fresh.WriteString(line)
+	}
+
+	if !e.witnessPolicy.Satisfied(fresh.Bytes()) {
+		return fmt.Errorf("checkpoint cosignatures are older than %v", e.maxAge)
+	}
+
+	return nil
+}
+
+// Verify that the older tree head is a prefix of the newer one.
+func verifyConsistency(oldTreeHead *transparency.TreeHead, newTreeHead *transparency.TreeHead, cp *transparency.ConsistencyProof) error {
+	if cp == nil {
+		return fmt.Errorf("consistency proof is not present")
+	}
+
+	if cp.OldSize != oldTreeHead.Size || cp.NewSize != newTreeHead.Size {
+		return fmt.Errorf("consistency proof from size %d to %d does not match tree heads", cp.OldSize, cp.NewSize)
+	}
+
+	return proof.VerifyConsistency(rfc6962.DefaultHasher, oldTreeHead.Size, newTreeHead.Size, cp.Path, oldTreeHead.RootHash, newTreeHead.RootHash)
+}
+
+// Return the checkpoint and tile fetchers for the log reachable at the
+// given origin, either over HTTP(S) or on the local file system.
+func (e *TesseraEngine) newFetchers(origin string) (logReadCP client.CheckpointFetcherFunc, logReadTile client.TileFetcherFunc, err error) {
+	logReadBaseURL, err := url.Parse(origin)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid log origin: %s", err)
+	}
+
+	switch logReadBaseURL.Scheme {
+	case "http", "https":
+		hf, err := client.NewHTTPFetcher(logReadBaseURL, e.httpClient)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create an http fetcher: %v", err)
+		}
+		logReadCP = hf.ReadCheckpoint
+		logReadTile = hf.ReadTile
+	case "file":
+		ff := client.FileFetcher{Root: logReadBaseURL.Path}
+		logReadCP = ff.ReadCheckpoint
+		logReadTile = ff.ReadTile
+	default:
+		return nil, nil, fmt.Errorf("unsupported url scheme: %s", logReadBaseURL.Scheme)
+	}
+
+	return
+}
+
 // convert the inclusion proof from what is provided in the JSON
-// proof bundle (i.e. []string) to what Tessera functions expects
-// to verify the inclusion proof (i.e. [][]byte)
-func inclusionProofFromJSON(pbProof []string) [][]byte {
+// proof bundle (i.e. []string of base64 entries) to what Tessera
+// functions expects to verify the inclusion proof (i.e. [][]byte)
+func inclusionProofFromJSON(pbProof []string) ([][]byte, error) {
 	tesseraProof := make([][]byte, len(pbProof))
 
 	for i, v := range pbProof {
-		tesseraProof[i] = []byte(v)
+		d, err := base64.StdEncoding.DecodeString(v)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse Tessera inclusion proof: %s", err)
+		}
+
+		tesseraProof[i] = d
+	}
+
+	return tesseraProof, nil
+}
+
+// Return the candidate leaf hashes for a statement, that is the hash of its
+// canonical form (see statement.Canonicalize()) and, when different, the
+// hash of its compact form, logged by earlier releases.
+func statementLeafHashes(jsonStatement []byte) (leafHashes [][]byte, err error) {
+	forms, err := bt_statement.CanonicalForms(jsonStatement)
+
+	if err != nil {
+		return
+	}
+
+	for _, f := range forms {
+		leafHashes = append(leafHashes, rfc6962.DefaultHasher.HashLeaf(f))
+	}
+
+	return
+}
+
+// Verify the inclusion proof for any of the candidate leaf hashes.
+func verifyInclusion(index uint64, size uint64, leafHashes [][]byte, ip [][]byte, root []byte) (err error) {
+	for _, leafHash := range leafHashes {
+		if err = proof.VerifyInclusion(rfc6962.DefaultHasher, index, size, leafHash, ip, root); err == nil {
+			return
+		}
 	}
 
-	return tesseraProof
+	return
 }
@@ -8,15 +8,29 @@
 package tessera
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/transparency-dev/formats/log"
+	"github.com/transparency-dev/tessera"
+	"github.com/transparency-dev/tessera/testonly"
+	"github.com/usbarmory/boot-transparency/engine/tessera/testlog"
 	"github.com/usbarmory/boot-transparency/transparency"
 )
 
 var validProofBundle []byte
 var validWitnessPolicy []byte
+var olderProofBundle []byte
+var validConsistencyProof []byte
 
 func TestLoadTestData(t *testing.T) {
 	var err error
@@ -32,6 +46,18 @@ func TestLoadTestData(t *testing.T) {
 	if err != nil {
 		t.Errorf("failed to load test witness policy: %s", err)
 	}
+
+	olderProofBundle, err = os.ReadFile("../../testdata/tessera/proof_bundle_size5.json")
+
+	if err != nil {
+		t.Errorf("failed to load test proof bundle: %s", err)
+	}
+
+	validConsistencyProof, err = os.ReadFile("../../testdata/tessera/consistency_proof.json")
+
+	if err != nil {
+		t.Errorf("failed to load test consistency proof: %s", err)
+	}
 }
 
 func TestTesseraEngineSetKey(t *testing.T) {
@@ -39,12 +65,10 @@ func TestTesseraEngineSetKey(t *testing.T) {
 	// Tessera does not use the submit key in the verification process
 	submitKey := []string{}
 
-	e, err := transparency.GetEngine(transparency.Tessera)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	err = e.SetKey(logKey, submitKey)
+	_, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:    logKey,
+		SubmitKey: submitKey,
+	})
 
 	if err != nil {
 		t.Fatal(err)
@@ -57,49 +81,376 @@ func TestNegativeTesseraEngineSetKey(t *testing.T) {
 	// Tessera does not use the submit key in the verification process
 	submitKey := []string{}
 
-	e, err := transparency.GetEngine(transparency.Tessera)
+	_, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:    logKey,
+		SubmitKey: submitKey,
+	})
+
+	if err == nil {
+		t.Fatal("invalid log key has been accepted")
+	}
+}
+
+func TestTesseraEngineParseWitnessPolicy(t *testing.T) {
+	_, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		WitnessPolicy: validWitnessPolicy,
+	})
+
 	if err != nil {
 		t.Fatal(err)
 	}
+}
 
-	err = e.SetKey(logKey, submitKey)
+func TestNegativeTesseraEngineParseWitnessPolicy(t *testing.T) {
+	_, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		WitnessPolicy: []byte("quorum unknown-group\n"),
+	})
 
 	if err == nil {
-		t.Fatal(err)
+		t.Fatal("invalid witness policy has been accepted")
 	}
 }
 
-func TestTesseraEngineParseWitnessPolicy(t *testing.T) {
-	e, err := transparency.GetEngine(transparency.Tessera)
+func TestTesseraParseBundle(t *testing.T) {
+	var probe Probe
+	var proof []string
+
+	logKey := []string{"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+
+	b, err := transparency.ParseBundle(validProofBundle)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	p, err := e.ParseWitnessPolicy(validWitnessPolicy)
+	if b.Format() != transparency.Tessera {
+		t.Fatalf("unexpected bundle format: %d", b.Format())
+	}
+
+	pb, ok := b.Native().(*ProofBundle)
+	if !ok {
+		t.Fatalf("unexpected native bundle: %T", b.Native())
+	}
 
+	// the statement is returned as logged, in compact JSON format
+	statement, err := json.Marshal(pb.Statement)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = e.SetWitnessPolicy(p)
+	if !bytes.Equal(b.Statement(), statement) {
+		t.Fatal("statement mismatch")
+	}
 
+	if err = json.Unmarshal(b.Probe(), &probe); err != nil || probe != pb.Probe {
+		t.Fatalf("probing data mismatch: %v", err)
+	}
+
+	if err = json.Unmarshal(b.Proof(), &proof); err != nil || len(proof) != len(pb.Proof) {
+		t.Fatalf("inclusion proof mismatch: %v", err)
+	}
+
+	e, err := transparency.NewEngine(b.Format(), transparency.Config{
+		LogKey:        logKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	if err = e.VerifyProof(b); err != nil {
+		t.Fatal(err)
+	}
 }
 
-func TestTesseraEngineNegativeNoCosignaturesVerifyProof(t *testing.T) {
+func TestTesseraEngineMultipleKeysVerifyProof(t *testing.T) {
 	// test support for multiple keys configured in the transparency engine:
-	// in this example only the last keys are the correct ones for verifying
-	// the test statement proof
+	// in this example only the last key is the correct one for verifying
+	// the test checkpoint signature
+	logKey := []string{"PeterNeumann+c74f20a3+ARpc2QcUPDhMQegwxbzhKqiBfsVkmqq/LDE4izWy10TW",
+		"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        logKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = e.VerifyProof(pb); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTesseraEngineNegativeNoWitnessPolicyVerifyProof(t *testing.T) {
+	logKey := []string{"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey: logKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the cosignatures cannot be verified, offline
+	// verification is refused as online proof fetching is
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("proof has been accepted without witness policy")
+	}
+
+	if _, err = e.GetProof(context.Background(), pb); err == nil {
+		t.Fatal("proof has been fetched without witness policy")
+	}
+}
+
+func TestTesseraEngineNegativeNoCosignaturesVerifyProof(t *testing.T) {
 	logKey := []string{"PeterNeumann+c74f20a3+ARpc2QcUPDhMQegwxbzhKqiBfsVkmqq/LDE4izWy10TW"}
 
-	e, err := transparency.GetEngine(transparency.Tessera)
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        logKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the checkpoint is not signed by the trusted log key
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("checkpoint signed by an untrusted log key has been accepted")
+	}
+}
+
+func TestTesseraEngineCosignaturesVerifyProof(t *testing.T) {
+	logKey := []string{"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        logKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = e.VerifyProof(pb); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTesseraEngineCosignaturesVerifyProofQuorumNotMet(t *testing.T) {
+	logKey := []string{"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        logKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// strip the last witness cosignature from the checkpoint, the witness
+	// policy requires both witnesses to cosign the checkpoint
+	b := pb.(*ProofBundle)
+	lines := strings.SplitAfter(b.Probe.Checkpoint, "\n")
+	b.Probe.Checkpoint = strings.Join(lines[:len(lines)-2], "")
+
+	// error expected here as the witness quorum is not reached
+	if err = e.VerifyProof(b); err == nil {
+		t.Fatal("checkpoint not satisfying the witness policy has been accepted")
+	}
+}
+
+func TestTesseraEngineConcurrentInstances(t *testing.T) {
+	var wg sync.WaitGroup
+
+	validLogKey := []string{"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+	invalidLogKey := []string{"PeterNeumann+c74f20a3+ARpc2QcUPDhMQegwxbzhKqiBfsVkmqq/LDE4izWy10TW"}
+
+	// engines configured with different log keys must not affect each
+	// other when used concurrently
+	for i := range 16 {
+		valid := i%2 == 0
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			logKey := invalidLogKey
+
+			if valid {
+				logKey = validLogKey
+			}
+
+			e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+				LogKey:        logKey,
+				WitnessPolicy: validWitnessPolicy,
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			pb, _, err := e.ParseProof(validProofBundle)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			for range 8 {
+				err = e.VerifyProof(pb)
+
+				if valid && err != nil {
+					t.Errorf("valid proof has been rejected: %v", err)
+				}
+
+				if !valid && err == nil {
+					t.Error("checkpoint signed by an untrusted log key has been accepted")
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestTesseraEngineVerifyProofForgedRoot(t *testing.T) {
+	logKey := []string{"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        logKey,
+		WitnessPolicy: validWitnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// replace the checkpoint root hash with the one matching a different
+	// statement, keeping the original log signature
+	b := pb.(*ProofBundle)
+	lines := strings.Split(b.Probe.Checkpoint, "\n")
+	lines[2] = "XcnaeacGWamtVZy3Ad7ZoqudgjqtL0lgz+Nw7/RgQyg="
+	b.Probe.Checkpoint = strings.Join(lines, "\n")
+
+	// error expected here as the checkpoint signature is not valid
+	if err = e.VerifyProof(b); err == nil {
+		t.Fatal("forged checkpoint has been accepted")
+	}
+}
+
+func TestTesseraEngineTrustState(t *testing.T) {
+	var cp transparency.ConsistencyProof
+
+	logKey := []string{"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+
+	ts := &transparency.MemoryTrustState{}
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        logKey,
+		WitnessPolicy: validWitnessPolicy,
+		TrustState:    ts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	older, _, err := e.ParseProof(olderProofBundle)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = e.SetKey(logKey, []string{})
+	newer, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// trust on first use
+	if err = e.VerifyProof(older); err != nil {
+		t.Fatal(err)
+	}
+
+	th, err := ts.Load("boot-transparency.example.org/test-log")
+	if err != nil || th == nil || th.Size != 5 {
+		t.Fatalf("unexpected trusted tree head: %v %v", th, err)
+	}
+
+	// error expected here as the consistency proof is missing
+	if err = e.VerifyProof(newer); err == nil {
+		t.Fatal("tree head without consistency proof has been accepted")
+	}
+
+	if err = json.Unmarshal(validConsistencyProof, &cp); err != nil {
+		t.Fatal(err)
+	}
+
+	newer.(*ProofBundle).Consistency = &cp
+
+	if err = e.VerifyProof(newer); err != nil {
+		t.Fatal(err)
+	}
+
+	th, err = ts.Load("boot-transparency.example.org/test-log")
+	if err != nil || th == nil || th.Size != 8 {
+		t.Fatalf("unexpected trusted tree head: %v %v", th, err)
+	}
+
+	// error expected here as the tree head is older than the trusted one
+	if err = e.VerifyProof(older); err == nil {
+		t.Fatal("tree head rollback has been accepted")
+	}
+}
+
+func TestTesseraEngineTrustStateInvalidConsistencyProof(t *testing.T) {
+	var cp transparency.ConsistencyProof
+
+	logKey := []string{"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+
+	ts := &transparency.MemoryTrustState{}
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        logKey,
+		WitnessPolicy: validWitnessPolicy,
+		TrustState:    ts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// trusted tree head of the same size, but with a different root hash
+	err = ts.Store(&transparency.TreeHead{
+		Origin:   "boot-transparency.example.org/test-log",
+		Size:     8,
+		RootHash: make([]byte, 32),
+	})
+
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -109,10 +460,398 @@ func TestTesseraEngineNegativeNoCosignaturesVerifyProof(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = e.VerifyProof(pb)
+	// error expected here as the log presented a split view
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("tree head not matching the trusted one has been accepted")
+	}
+
+	// trusted tree head of a smaller size, but not a prefix of the log
+	err = ts.Store(&transparency.TreeHead{
+		Origin:   "boot-transparency.example.org/test-log",
+		Size:     5,
+		RootHash: make([]byte, 32),
+	})
 
-	// error expected here as the log public key will not pass log signature verification
-	if err != nil && !strings.Contains(err.Error(), "does not match expected root") {
+	if err != nil {
 		t.Fatal(err)
 	}
+
+	if err = json.Unmarshal(validConsistencyProof, &cp); err != nil {
+		t.Fatal(err)
+	}
+
+	pb.(*ProofBundle).Consistency = &cp
+
+	// error expected here as the consistency proof does not match
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("inconsistent tree head has been accepted")
+	}
+}
+
+func TestTesseraEngineMaxAge(t *testing.T) {
+	logKey := []string{"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey: logKey,
+		MaxAge: 100 * 365 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the age check requires a witness policy
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("age check without witness policy has been accepted")
+	}
+
+	e, err = transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        logKey,
+		WitnessPolicy: validWitnessPolicy,
+		MaxAge:        100 * 365 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = e.VerifyProof(pb); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err = transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        logKey,
+		WitnessPolicy: validWitnessPolicy,
+		MaxAge:        time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the test data cosignatures are older
+	if err = e.VerifyProof(pb); err == nil {
+		t.Fatal("stale checkpoint has been accepted")
+	}
+}
+
+func TestTesseraEngineVerifyConsistency(t *testing.T) {
+	var cp transparency.ConsistencyProof
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	older, _, err := e.ParseProof(olderProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newer, _, err := e.ParseProof(validProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldTreeHead := treeHeadFromCheckpoint(t, older.(*ProofBundle).Probe.Checkpoint)
+	newTreeHead := treeHeadFromCheckpoint(t, newer.(*ProofBundle).Probe.Checkpoint)
+
+	if err = json.Unmarshal(validConsistencyProof, &cp); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = e.VerifyConsistency(oldTreeHead, newTreeHead, &cp); err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as tree heads are swapped
+	if err = e.VerifyConsistency(newTreeHead, oldTreeHead, &cp); err == nil {
+		t.Fatal("swapped tree heads have been accepted")
+	}
+
+	// error expected here as the tree heads belong to different logs
+	otherTreeHead := *newTreeHead
+	otherTreeHead.Origin = "example.org/other-log"
+
+	if err = e.VerifyConsistency(oldTreeHead, &otherTreeHead, &cp); err == nil {
+		t.Fatal("tree heads from different logs have been accepted")
+	}
+
+	// error expected here as the proof has been tampered
+	cp.Path[0][0] ^= 0xff
+
+	if err = e.VerifyConsistency(oldTreeHead, newTreeHead, &cp); err == nil {
+		t.Fatal("invalid consistency proof has been accepted")
+	}
+}
+
+func TestTesseraEngineGetConsistencyProof(t *testing.T) {
+	var treeHeads []*transparency.TreeHead
+
+	ctx := context.Background()
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, shutdown := testonly.NewTestLog(t, tessera.NewAppendOptions().
+		WithCheckpointInterval(100*time.Millisecond).
+		WithBatching(1, 10*time.Millisecond))
+	defer func() { _ = shutdown(ctx) }()
+
+	awaiter := tessera.NewPublicationAwaiter(ctx, l.LogReader.ReadCheckpoint, 10*time.Millisecond)
+
+	// grow the log, collecting a checkpoint at each size
+	for i := range 8 {
+		_, rawcp, err := awaiter.Await(ctx, l.Appender.Add(ctx, tessera.NewEntry(fmt.Appendf(nil, "leaf %d", i))))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cp, _, _, err := log.ParseCheckpoint(rawcp, l.SigVerifier.Name(), l.SigVerifier)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		treeHeads = append(treeHeads, &transparency.TreeHead{Origin: cp.Origin, Size: cp.Size, RootHash: cp.Hash})
+	}
+
+	newTreeHead := treeHeads[len(treeHeads)-1]
+
+	for _, oldTreeHead := range treeHeads {
+		cp, err := e.GetConsistencyProof(ctx, "file://"+l.Root, oldTreeHead.Size, newTreeHead.Size)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = e.VerifyConsistency(oldTreeHead, newTreeHead, cp); err != nil {
+			t.Fatalf("size %d: %v", oldTreeHead.Size, err)
+		}
+	}
+
+	// error expected here as the requested tree is larger than the log
+	if _, err = e.GetConsistencyProof(ctx, "file://"+l.Root, 1, newTreeHead.Size+1); err == nil {
+		t.Fatal("consistency proof for a non-existing tree has been returned")
+	}
+
+	// error expected here as the tree sizes are swapped
+	if _, err = e.GetConsistencyProof(ctx, "file://"+l.Root, newTreeHead.Size, 1); err == nil {
+		t.Fatal("consistency proof for swapped tree sizes has been returned")
+	}
+}
+
+func treeHeadFromCheckpoint(t *testing.T, rawcp string) *transparency.TreeHead {
+	var cp log.Checkpoint
+
+	// the checkpoint signature is not relevant here
+	text, _, _ := strings.Cut(rawcp, "\n\n")
+
+	if _, err := cp.Unmarshal([]byte(text + "\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	return &transparency.TreeHead{Origin: cp.Origin, Size: cp.Size, RootHash: cp.Hash}
+}
+
+func TestTesseraEngineSubmit(t *testing.T) {
+	logURL, logKey, witnessPolicy := testlog.New(t)
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        []string{logKey},
+		WitnessPolicy: witnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, s := range []struct {
+		statement string
+		canonical string
+	}{
+		{`{"description": "first"}`, `{"description":"first"}`},
+		{`{"version": "v1", "description": "<second>"}`, `{"description":"<second>","version":"v1"}`},
+	} {
+		pb, err := e.Submit(context.Background(), logURL, []byte(s.statement), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b := pb.(*ProofBundle)
+
+		// the statement is logged in its canonical form
+		if string(b.Statement) != s.canonical {
+			t.Fatalf("unexpected logged statement: %s", b.Statement)
+		}
+
+		if b.Probe.LeafIdx != uint64(i) || b.Probe.LogPublicKey != logKey || b.Probe.Origin != logURL {
+			t.Fatalf("unexpected probing data: %+v", b.Probe)
+		}
+
+		// the returned proof bundle must be accepted by the verifier
+		if err = e.VerifyProof(pb); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTesseraEngineNegativeSubmit(t *testing.T) {
+	logURL, logKey, witnessPolicy := testlog.New(t)
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey: []string{logKey},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the witness policy is not set
+	if _, err = e.Submit(context.Background(), logURL, []byte(`{}`), nil); err == nil {
+		t.Fatal("submission without witness policy has been accepted")
+	}
+
+	e, err = transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        []string{logKey},
+		WitnessPolicy: witnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the statement is not a valid JSON
+	if _, err = e.Submit(context.Background(), logURL, []byte(`{`), nil); err == nil {
+		t.Fatal("invalid statement has been accepted")
+	}
+
+	// error expected here as the add endpoint does not exist
+	if _, err = e.Submit(context.Background(), logURL+"missing/", []byte(`{}`), nil); err == nil {
+		t.Fatal("submission to an invalid log has been accepted")
+	}
+}
+
+func TestTesseraEngineGetProofMirror(t *testing.T) {
+	var requests atomic.Int32
+
+	logURL, logKey, witnessPolicy := testlog.New(t)
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        []string{logKey},
+		WitnessPolicy: witnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, err := e.Submit(context.Background(), logURL, []byte(`{"description": "mirrored"}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unreachable log origin, the test log acts as its mirror
+	origin := "http://127.0.0.1:0/"
+	pb.(*ProofBundle).Probe.Origin = origin
+
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+
+	e, err = transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        []string{logKey},
+		WitnessPolicy: witnessPolicy,
+		Network: transparency.Network{
+			HTTPClient: httpClient,
+			Mirrors:    map[string][]string{origin: {logURL}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = e.GetProof(context.Background(), pb); err != nil {
+		t.Fatal(err)
+	}
+
+	if requests.Load() == 0 {
+		t.Fatal("configured HTTP client has not been used")
+	}
+
+	// the log origin in the probing data is not replaced by the mirror
+	if pb.(*ProofBundle).Probe.Origin != origin {
+		t.Fatalf("unexpected log origin: %s", pb.(*ProofBundle).Probe.Origin)
+	}
+
+	if err = e.VerifyProof(pb); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// error expected here as the context is done
+	if _, err = e.GetProof(ctx, pb); err == nil {
+		t.Fatal("proof has been fetched with a cancelled context")
+	}
+}
+
+func TestTesseraBundleUpdate(t *testing.T) {
+	logURL, logKey, witnessPolicy := testlog.New(t)
+
+	e, err := transparency.NewEngine(transparency.Tessera, transparency.Config{
+		LogKey:        []string{logKey},
+		WitnessPolicy: witnessPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, err := e.Submit(context.Background(), logURL, []byte(`{"description": "first"}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonProofBundle, err := json.Marshal(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := transparency.ParseBundle(jsonProofBundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	probe := b.Probe()
+
+	// grow the log, so that a fresh inclusion proof is relative to a
+	// different checkpoint
+	if _, err = e.Submit(context.Background(), logURL, []byte(`{"description": "second"}`), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = e.GetProof(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+
+	// the engine-agnostic form reflects the updated proof bundle
+	if bytes.Equal(b.Probe(), probe) {
+		t.Fatal("probing data not updated")
+	}
+
+	if !bytes.Contains(b.Probe(), []byte(`"checkpoint"`)) {
+		t.Fatalf("unexpected probing data: %s", b.Probe())
+	}
+
+	if err = e.VerifyProof(b); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
 }
@@ -0,0 +1,130 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package testlog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	f_note "github.com/transparency-dev/formats/note"
+	"github.com/transparency-dev/tessera"
+	"github.com/transparency-dev/tessera/storage/posix"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// Return a new Tessera log, for testing, served over HTTP until the end of
+// the test. Statements are added with HTTP POST to <logURL>/add, while each
+// served checkpoint is cosigned by two witnesses, both required by the
+// returned witness policy.
+func New(t testing.TB) (logURL string, logKey string, witnessPolicy []byte) {
+	var cosigners []note.Signer
+
+	ctx := context.Background()
+
+	skey, logKey, err := note.GenerateKey(nil, "example.org/test-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := note.NewSigner(skey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"w1", "w2"} {
+		skey, vkey, err := note.GenerateKey(nil, name+".example.org")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cosigner, err := f_note.NewSignerForCosignatureV1(skey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cosigners = append(cosigners, cosigner)
+		witnessPolicy = fmt.Appendf(witnessPolicy, "witness %s %s https://%s.example.org/\n", name, vkey, name)
+	}
+
+	witnessPolicy = append(witnessPolicy, "group g1 all w1 w2\nquorum g1\n"...)
+
+	root := t.TempDir()
+
+	driver, err := posix.New(ctx, posix.Config{Path: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := note.NewVerifier(logKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appender, shutdown, _, err := tessera.NewAppender(ctx, driver, tessera.NewAppendOptions().
+		WithCheckpointSigner(signer).
+		WithCheckpointInterval(100*time.Millisecond).
+		WithBatching(1, 10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /add", func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		idx, err := appender.Add(r.Context(), tessera.NewEntry(b))()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, "%d", idx.Index)
+	})
+	// witnesses cosign each checkpoint as it is served
+	mux.HandleFunc("GET /checkpoint", func(w http.ResponseWriter, r *http.Request) {
+		b, err := os.ReadFile(filepath.Join(root, "checkpoint"))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		n, err := note.Open(b, note.VerifierList(verifier))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if b, err = note.Sign(n, cosigners...); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = w.Write(b)
+	})
+	mux.Handle("GET /", http.FileServer(http.Dir(root)))
+
+	srv := httptest.NewServer(mux)
+
+	t.Cleanup(func() {
+		srv.Close()
+		_ = shutdown(ctx)
+	})
+
+	return srv.URL + "/", logKey, witnessPolicy
+}
@@ -8,15 +8,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
 	"log"
 	"os"
 
-	"github.com/usbarmory/boot-transparency/engine/sigsum"
-	"github.com/usbarmory/boot-transparency/policy"
-	"github.com/usbarmory/boot-transparency/statement"
 	"github.com/usbarmory/boot-transparency/transparency"
+	"github.com/usbarmory/boot-transparency/verifier"
 )
 
 const (
@@ -54,58 +53,26 @@ func bootTransparencyOfflineCheck(fsys fs.FS, bootPolicyPath string, witnessPoli
 		return fmt.Errorf("cannot read proof bundle, %v", err)
 	}
 
-	// select Sigsum as transparency engine
-	te, err := transparency.GetEngine(transparency.Sigsum)
+	// configure the verifier with the Sigsum public keys,
+	// the witness policy and the boot policy
+	v, err := verifier.New(verifier.Config{
+		Engines: map[uint]transparency.Config{
+			transparency.Sigsum: {
+				LogKey:        []string{string(logKey)},
+				SubmitKey:     []string{string(submitKey)},
+				WitnessPolicy: witnessPolicy,
+			},
+		},
+		BootPolicy: bootPolicy,
+	})
 	if err != nil {
-		return fmt.Errorf("unable to configure the transparency engine: %v", err)
+		return fmt.Errorf("unable to configure the verifier: %v", err)
 	}
 
-	// set public keys
-	err = te.SetKey([]string{string(logKey)}, []string{string(submitKey)})
-	if err != nil {
-		return err
-	}
-
-	// parse witness policy
-	wp, err := te.ParseWitnessPolicy(witnessPolicy)
-	if err != nil {
-		return err
-	}
-
-	// set witness policy
-	err = te.SetWitnessPolicy(wp)
-	if err != nil {
-		return err
-	}
-
-	// parse the proof bundle, which is expected to contain
-	// the logged statement and its inclusion proof
-	pb, _, err := te.ParseProof(proofBundle)
-
-	// inclusion proof verification
-	// considers the co-signing quorum as defined in the witness policy
-	err = te.VerifyProof(pb)
-	if err != nil {
-		return err
-	}
-
-	// parse the boot policy
-	p, err := policy.Parse(bootPolicy)
-	if err != nil {
-		return err
-	}
-
-	// convert to the proof bundle type expected by the selected engine
-	b := pb.(*sigsum.ProofBundle)
-
-	// parse the statement included in the proof bundle
-	s, err := statement.Parse(b.Statement)
-	if err != nil {
-		return err
-	}
-
-	// check if the logged claims are matching the policy requirements
-	if err = policy.Check(p, s); err != nil {
+	// verify the inclusion proof included in the proof bundle,
+	// considering the co-signing quorum as defined in the witness policy,
+	// then check if the logged claims are matching the policy requirements
+	if _, err = v.Verify(proofBundle); err != nil {
 		// the boot bundle is NOT authorized for boot
 		return err
 	}
@@ -140,73 +107,32 @@ func bootTransparencyOnlineCheck(fsys fs.FS, bootPolicyPath string, witnessPolic
 		return fmt.Errorf("cannot read proof bundle, %v", err)
 	}
 
-	// select Sigsum as transparency engine
-	te, err := transparency.GetEngine(transparency.Sigsum)
+	// configure the verifier with the Sigsum public keys,
+	// the witness policy and the boot policy
+	v, err := verifier.New(verifier.Config{
+		Engines: map[uint]transparency.Config{
+			transparency.Sigsum: {
+				LogKey:        []string{string(logKey)},
+				SubmitKey:     []string{string(submitKey)},
+				WitnessPolicy: witnessPolicy,
+			},
+		},
+		BootPolicy: bootPolicy,
+	})
 	if err != nil {
-		return fmt.Errorf("unable to configure the transparency engine: %v", err)
+		return fmt.Errorf("unable to configure the verifier: %v", err)
 	}
 
-	// set public keys
-	err = te.SetKey([]string{string(logKey)}, []string{string(submitKey)})
+	// probe the log to obtain a fresh inclusion proof, then perform
+	// the same checks as for the off-line verification
+	r, err := v.VerifyOnline(context.Background(), proofBundle)
 	if err != nil {
-		return err
-	}
-
-	// parse witness policy
-	wp, err := te.ParseWitnessPolicy(witnessPolicy)
-	if err != nil {
-		return err
-	}
-
-	// set witness policy
-	err = te.SetWitnessPolicy(wp)
-	if err != nil {
-		return err
-	}
-
-	// parse the proof bundle, which is expected to contain
-	// the logged statement and probe data to request the inclusion proof
-	pb, _, err := te.ParseProof(proofBundle)
-
-	// probe the log to obtain a fresh inclusion proof
-	pr, err := te.GetProof(pb)
-	if err != nil {
-		return err
-	}
-
-	freshBundle := pb.(*sigsum.ProofBundle)
-	freshBundle.Proof = string(pr)
-	log.Printf("successfully downloaded a fresh inclusion proof:\n%s", freshBundle.Proof)
-
-	// inclusion proof verification
-	// using the fresh inclusion proof obtained from the log
-	// considers the co-signing quorum as defined in the witness policy
-	err = te.VerifyProof(freshBundle)
-	if err != nil {
-		return err
-	}
-
-	// parse the boot policy
-	p, err := policy.Parse(bootPolicy)
-	if err != nil {
-		return err
-	}
-
-	// convert to the proof bundle type expected by the selected engine
-	b := pb.(*sigsum.ProofBundle)
-
-	// parse the statement included in the proof bundle
-	s, err := statement.Parse(b.Statement)
-	if err != nil {
-		return err
-	}
-
-	// check if the logged claims are matching the policy requirements
-	if err = policy.Check(p, s); err != nil {
 		// the boot bundle is NOT authorized for boot
 		return err
 	}
 
+	log.Printf("successfully downloaded a fresh inclusion proof:\n%s", r.Bundle.Proof())
+
 	// all boot-transparency checks passed
 	return
 }
@@ -8,10 +8,15 @@
 package policy
 
 import (
+	_ "github.com/usbarmory/boot-transparency/artifact/boot_entry"
+	_ "github.com/usbarmory/boot-transparency/artifact/cmdline"
+	_ "github.com/usbarmory/boot-transparency/artifact/cve"
 	_ "github.com/usbarmory/boot-transparency/artifact/dtb"
 	_ "github.com/usbarmory/boot-transparency/artifact/initrd"
 	_ "github.com/usbarmory/boot-transparency/artifact/linux_kernel"
-	_ "github.com/usbarmory/boot-transparency/artifact/uefi_bios"
+	_ "github.com/usbarmory/boot-transparency/artifact/microcode"
 	_ "github.com/usbarmory/boot-transparency/artifact/uefi_binary"
+	_ "github.com/usbarmory/boot-transparency/artifact/uefi_bios"
+	_ "github.com/usbarmory/boot-transparency/artifact/uki"
 	_ "github.com/usbarmory/boot-transparency/artifact/windows_bootmgr"
 )
@@ -0,0 +1,518 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+	"github.com/usbarmory/boot-transparency/statement"
+)
+
+var (
+	// ErrQuorumNotMet is returned when the statement signatures do not
+	// satisfy the signing quorum of a policy entry.
+	ErrQuorumNotMet = errors.New("signing quorum not met")
+
+	// ErrMissingCategory is returned when the statement does not include
+	// an artifact category required by a policy entry.
+	ErrMissingCategory = errors.New("the boot bundle does not include a required artifact category")
+
+	// ErrRequirementNotMet is returned when the claims of an artifact do
+	// not meet the requirements of a policy entry.
+	ErrRequirementNotMet = errors.New("artifact requirement not met")
+
+	// ErrValidityNotMet is returned when the statement validity period
+	// does not meet the requirements of a policy entry.
+	ErrValidityNotMet = errors.New("statement validity requirement not met")
+
+	// ErrTargetNotMet is returned when the statement target does not
+	// meet the requirements of a policy entry.
+	ErrTargetNotMet = errors.New("statement target requirement not met")
+
+	// ErrEmptyPolicy is returned when the policy does not include any entry.
+	ErrEmptyPolicy = errors.New("empty policy")
+)
+
+// Define the error returned when the claims of an artifact do not meet
+// a policy requirement, it matches ErrRequirementNotMet with errors.Is().
+type RequirementError struct {
+	// index of the policy entry
+	Entry int
+
+	// artifact category
+	Category uint
+
+	// name of the failed requirement (i.e. JSON key)
+	Requirement string
+
+	// failure reason, as returned by the artifact handler
+	Reason error
+}
+
+func (e *RequirementError) Error() string {
+	return fmt.Sprintf("%v for category %d, %s: %v", ErrRequirementNotMet, e.Category, e.Requirement, e.Reason)
+}
+
+func (e *RequirementError) Unwrap() []error {
+	return []error{ErrRequirementNotMet, e.Reason}
+}
+
+// Define the outcome of a single artifact requirement.
+type RequirementResult struct {
+	// requirement name (i.e. JSON key)
+	Name string `json:"name"`
+
+	// true if the requirement is met
+	Passed bool `json:"passed"`
+
+	// failure reason
+	Reason string `json:"reason,omitempty"`
+}
+
+// Define the outcome of the artifact requirements of a policy entry
+// against a claimed artifact.
+type ArtifactResult struct {
+	// artifact category
+	Category uint `json:"category"`
+
+	// index of the artifact in the statement, -1 if the statement does
+	// not include any artifact for the category
+	Artifact int `json:"artifact"`
+
+	// true if all requirements are met
+	Passed bool `json:"passed"`
+
+	// outcome of each requirement
+	Requirements []RequirementResult `json:"requirements,omitempty"`
+
+	// failure reason
+	Reason string `json:"reason,omitempty"`
+}
+
+// Define the outcome of the signing quorum of a policy entry.
+type QuorumResult struct {
+	// required number of valid signatures
+	Quorum uint64 `json:"quorum"`
+
+	// number of trusted signers with a valid signature
+	Valid uint64 `json:"valid"`
+
+	// true if the quorum is met
+	Passed bool `json:"passed"`
+
+	// failure reason
+	Reason string `json:"reason,omitempty"`
+}
+
+// Define the outcome of the validity requirements of a policy entry.
+type ValidityResult struct {
+	// evaluation time, in RFC3339 format, empty if not available
+	Time string `json:"time,omitempty"`
+
+	// true if the requirements are met
+	Passed bool `json:"passed"`
+
+	// failure reason
+	Reason string `json:"reason,omitempty"`
+}
+
+// Define the outcome of the target requirements of a policy entry.
+type TargetResult struct {
+	// true if the requirements are met
+	Passed bool `json:"passed"`
+
+	// failure reason
+	Reason string `json:"reason,omitempty"`
+}
+
+// Define the outcome of the revocation check of a policy entry.
+type RevocationResult struct {
+	// index, within the environment revocation statements, of the
+	// statement revoking the evaluated one, -1 if not revoked
+	Statement int `json:"statement"`
+
+	// revocation status (e.g. statement.Revoked), empty if not revoked
+	Status string `json:"status,omitempty"`
+
+	// true if the statement is not revoked
+	Passed bool `json:"passed"`
+
+	// failure reason
+	Reason string `json:"reason,omitempty"`
+}
+
+// Define the outcome of a policy entry.
+type EntryResult struct {
+	// index of the policy entry
+	Entry int `json:"entry"`
+
+	// true if the policy entry authorizes the statement
+	Passed bool `json:"passed"`
+
+	// signing quorum outcome, nil if the entry does not require any
+	Quorum *QuorumResult `json:"quorum,omitempty"`
+
+	// validity outcome, nil if the entry does not require any
+	Validity *ValidityResult `json:"validity,omitempty"`
+
+	// target outcome, nil if the entry does not require any
+	Target *TargetResult `json:"target,omitempty"`
+
+	// revocation outcome, nil if no revocation statement is available
+	Revocation *RevocationResult `json:"revocation,omitempty"`
+
+	// outcome of each artifact requirement
+	Artifacts []ArtifactResult `json:"artifacts"`
+
+	// first error encountered while evaluating the entry
+	err error
+}
+
+// Return the first error encountered while evaluating the entry,
+// nil if the entry authorizes the statement.
+func (r *EntryResult) Err() error {
+	return r.err
+}
+
+// return the number of met requirements, used to identify the entry
+// closest to matching
+func (r *EntryResult) score() (n int) {
+	if r.Quorum != nil && r.Quorum.Passed {
+		n++
+	}
+
+	if r.Validity != nil && r.Validity.Passed {
+		n++
+	}
+
+	if r.Target != nil && r.Target.Passed {
+		n++
+	}
+
+	if r.Revocation != nil && r.Revocation.Passed {
+		n++
+	}
+
+	for _, a := range r.Artifacts {
+		if a.Passed {
+			n++
+		}
+	}
+
+	return
+}
+
+// Define the report of a policy evaluation.
+type Decision struct {
+	// true if at least one policy entry authorizes the statement
+	Allowed bool `json:"allowed"`
+
+	// index of the first policy entry authorizing the statement or, when
+	// not allowed, of the entry closest to matching; -1 if the policy
+	// is empty
+	Entry int `json:"entry"`
+
+	// outcome of each policy entry
+	Entries []EntryResult `json:"entries"`
+}
+
+// Return nil if the statement is authorized, otherwise the error of
+// the policy entry closest to matching.
+func (d *Decision) Err() error {
+	if d.Allowed {
+		return nil
+	}
+
+	if d.Entry < 0 || d.Entry >= len(d.Entries) {
+		return ErrEmptyPolicy
+	}
+
+	return d.Entries[d.Entry].err
+}
+
+// Evaluate the claims present in a given statement against all the policy
+// entries, reporting the outcome of each entry, artifact and requirement.
+// All entries are evaluated, even after one authorizing the statement.
+//
+// The outcome of each requirement is the one reported by the artifact
+// category handler (see artifact.Handler.Evaluate()).
+//
+// Return error if:
+//   - the handler of a policy artifact category is not registered
+//   - the requirement parsing fails
+//   - the claim parsing fails
+func Evaluate(p *[]PolicyEntry, s *statement.Statement) (d *Decision, err error) {
+	return EvaluateWith(p, s, nil)
+}
+
+// Evaluate the claims present in a given statement against all the policy
+// entries, within a given evaluation environment (see Evaluate()). The
+// environment is required to meet the policy requirements depending on the
+// boot environment (e.g. the statement validity or target), when nil those
+// are not met.
+//
+// Bundles revoked by the environment revocation statements, satisfying the
+// revocation signing quorum of a policy entry, are not authorized by the
+// entry. Entries without any signing quorum to trust revocations do not
+// authorize any bundle when the environment includes revocations.
+//
+// Return error if:
+//   - any of the Evaluate() conditions is met
+//   - the statement is not a boot bundle (see statement.KindBundle)
+func EvaluateWith(p *[]PolicyEntry, s *statement.Statement, env *Environment) (d *Decision, err error) {
+	if s.Kind != statement.KindBundle {
+		return nil, fmt.Errorf("%s statements cannot authorize boot bundles", s.Kind)
+	}
+
+	d = &Decision{
+		Entry: -1,
+	}
+
+	for i, entry := range *p {
+		var r *EntryResult
+
+		if r, err = evaluateEntry(i, &entry, s, env); err != nil {
+			return nil, err
+		}
+
+		d.Entries = append(d.Entries, *r)
+
+		switch {
+		case d.Allowed:
+		case r.Passed:
+			d.Allowed = true
+			d.Entry = i
+		case d.Entry < 0 || r.score() > d.Entries[d.Entry].score():
+			d.Entry = i
+		}
+	}
+
+	return
+}
+
+// Return the signing requirement for the statements authorized by a policy
+// entry within an evaluation environment.
+func signers(entry *PolicyEntry, env *Environment) *SigningRequirement {
+	req := entry.Signatures
+
+	// legacy signatures only cover the statement artifacts, and not its
+	// validity period or target, therefore they are never accepted when
+	// the entry has validity or target requirements
+	if entry.Validity.Current || entry.Validity.Expiry ||
+		entry.Target.Match || entry.Target.Declared {
+		req.Legacy = false
+	}
+
+	// revocations match the statement digest, which legacy signatures do
+	// not cover, therefore a revoked statement could be logged again with
+	// an edited description and its legacy signatures would still verify
+	if env != nil && len(env.Revocations) > 0 && revokers(entry) != nil {
+		req.Legacy = false
+	}
+
+	return &req
+}
+
+func evaluateEntry(i int, entry *PolicyEntry, s *statement.Statement, env *Environment) (r *EntryResult, err error) {
+	r = &EntryResult{
+		Entry:     i,
+		Artifacts: []ArtifactResult{},
+	}
+
+	// if this policy entry requires a signing quorum to authorize the bundle,
+	// check the number of valid signatures in the logged statement
+	if entry.Signatures.Quorum > 0 {
+		q := &QuorumResult{
+			Quorum: entry.Signatures.Quorum,
+		}
+
+		if q.Valid, err = checkSigningQuorum(signers(entry, env), s); err == nil {
+			q.Passed = true
+		} else {
+			q.Reason = err.Error()
+			r.err = err
+		}
+
+		r.Quorum = q
+	}
+
+	// if this policy entry requires the statement to be within its
+	// validity period, check it against the environment time
+	if entry.Validity.Current || entry.Validity.Expiry {
+		v := &ValidityResult{}
+
+		if err := checkValidity(&entry.Validity, s, env, v); err == nil {
+			v.Passed = true
+		} else {
+			v.Reason = err.Error()
+
+			if r.err == nil {
+				r.err = err
+			}
+		}
+
+		r.Validity = v
+	}
+
+	// if this policy entry requires the statement to target the booted
+	// device, check it against the environment device identity
+	if entry.Target.Match || entry.Target.Declared {
+		t := &TargetResult{}
+
+		if err := checkTarget(&entry.Target, s, env); err == nil {
+			t.Passed = true
+		} else {
+			t.Reason = err.Error()
+
+			if r.err == nil {
+				r.err = err
+			}
+		}
+
+		r.Target = t
+	}
+
+	// check whether the statement is revoked by a revocation statement
+	// trusted by this policy entry
+	if env != nil && len(env.Revocations) > 0 {
+		v := &RevocationResult{
+			Statement: -1,
+		}
+
+		if err := checkRevocation(entry, s, env, v); err == nil {
+			v.Passed = true
+		} else {
+			v.Reason = err.Error()
+
+			if r.err == nil {
+				r.err = err
+			}
+		}
+
+		r.Revocation = v
+	}
+
+	// check all the per-category requirements against the claimed
+	// properties for the artifacts present in the bundle
+	for _, policyArtifact := range entry.Artifacts {
+		var results []artifactResult
+
+		if results, err = evaluateArtifact(i, &policyArtifact, s); err != nil {
+			return nil, err
+		}
+
+		for _, a := range results {
+			if !a.Passed && r.err == nil {
+				r.err = a.err
+			}
+
+			r.Artifacts = append(r.Artifacts, a.ArtifactResult)
+		}
+	}
+
+	r.Passed = r.err == nil
+
+	return
+}
+
+type artifactResult struct {
+	ArtifactResult
+	err error
+}
+
+func evaluateArtifact(i int, policyArtifact *ArtifactRequirements, s *statement.Statement) (results []artifactResult, err error) {
+	h, err := artifact.GetHandler(policyArtifact.Category)
+
+	// the policy requirements for this artifact cannot be checked, the
+	// handler for this category, that is included in the policy, is not
+	// registered
+	if err != nil {
+		return
+	}
+
+	req, err := h.ParseRequirements([]byte(policyArtifact.Requirements))
+
+	if err != nil {
+		return
+	}
+
+	for j, statementArtifact := range s.Artifacts {
+		if policyArtifact.Category != statementArtifact.Category {
+			continue
+		}
+
+		a := artifactResult{
+			ArtifactResult: ArtifactResult{
+				Category: policyArtifact.Category,
+				Artifact: j,
+			},
+		}
+
+		c, err := h.ParseClaims([]byte(statementArtifact.Claims))
+
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := h.Evaluate(req, c)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rr := range res {
+			r := RequirementResult{
+				Name:   rr.Requirement,
+				Passed: rr.Err == nil,
+			}
+
+			if rr.Err != nil {
+				r.Reason = rr.Err.Error()
+
+				// the artifact verdict is the one of its first failed
+				// requirement
+				if a.err == nil {
+					a.err = &RequirementError{
+						Entry:       i,
+						Category:    policyArtifact.Category,
+						Requirement: rr.Requirement,
+						Reason:      rr.Err,
+					}
+
+					a.Reason = a.err.Error()
+				}
+			}
+
+			a.Requirements = append(a.Requirements, r)
+		}
+
+		a.Passed = a.err == nil
+
+		results = append(results, a)
+	}
+
+	// cannot authorize bundles that are not containing at least one artifact
+	// that is compatible (i.e. same category) with the one required by this
+	// policy entry
+	if len(results) == 0 {
+		err := fmt.Errorf("%w (category %d)", ErrMissingCategory, policyArtifact.Category)
+
+		results = append(results, artifactResult{
+			ArtifactResult: ArtifactResult{
+				Category: policyArtifact.Category,
+				Artifact: -1,
+				Reason:   err.Error(),
+			},
+			err: err,
+		})
+	}
+
+	return
+}
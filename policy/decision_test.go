@@ -0,0 +1,399 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/usbarmory/boot-transparency/statement"
+)
+
+var decisionStatement = []byte(`{
+    "description": "Linux bundle",
+    "version": "v1",
+    "artifacts": [
+        {
+            "category": 1,
+            "claims": {
+                "file_name": "vmlinuz-6.14.0-29-generic",
+                "hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59",
+                "version": "v6.14.0-29-generic",
+                "architecture": "x64",
+                "tainted": false,
+                "license": ["GPL-2.0"]
+            }
+        }
+    ]
+}`)
+
+func evaluate(t *testing.T, jsonPolicy []byte) *Decision {
+	p, err := Parse(jsonPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse(decisionStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Evaluate(p, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return d
+}
+
+func TestEvaluate(t *testing.T) {
+	d := evaluate(t, []byte(`[
+{
+    "artifacts": [
+        {
+            "category": 1,
+            "requirements": {
+                "min_version": "v7.0.0"
+            }
+        }
+    ]
+},
+{
+    "artifacts": [
+        {
+            "category": 1,
+            "requirements": {
+                "min_version": "v6.14.0-29",
+                "architecture": "x64"
+            }
+        }
+    ]
+}]`))
+
+	if !d.Allowed || d.Entry != 1 || d.Err() != nil {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+
+	if len(d.Entries) != 2 || d.Entries[0].Passed || !d.Entries[1].Passed {
+		t.Fatalf("unexpected entry results: %+v", d.Entries)
+	}
+
+	a := d.Entries[1].Artifacts
+
+	if len(a) != 1 || a[0].Artifact != 0 || !a[0].Passed {
+		t.Fatalf("unexpected artifact results: %+v", a)
+	}
+
+	// min_version, architecture and the implicit tainted requirement
+	if len(a[0].Requirements) != 3 {
+		t.Fatalf("unexpected requirement results: %+v", a[0].Requirements)
+	}
+}
+
+func TestNegativeEvaluate(t *testing.T) {
+	d := evaluate(t, []byte(`[
+{
+    "artifacts": [
+        {
+            "category": 1,
+            "requirements": {
+                "min_version": "v6.14.0-29",
+                "architecture": "arm64"
+            }
+        }
+    ]
+},
+{
+    "artifacts": [
+        {
+            "category": 1,
+            "requirements": {}
+        },
+        {
+            "category": 2,
+            "requirements": {}
+        }
+    ]
+},
+{
+    "artifacts": [
+        {
+            "category": 1,
+            "requirements": {}
+        }
+    ],
+    "signatures": {
+        "signers": [
+            {
+                "name": "signatory I",
+                "pub_key": "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIP5rbNcIOcwqBHzLOhJEfdKFHa+pIs10idfTm8c+HDnK"
+            }
+        ],
+        "quorum": 1
+    }
+}]`))
+
+	if d.Allowed || d.Err() == nil {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+
+	var rerr *RequirementError
+
+	// the architecture requirement is not met
+	if err := d.Entries[0].Err(); !errors.Is(err, ErrRequirementNotMet) || !errors.As(err, &rerr) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rerr.Entry != 0 || rerr.Category != 1 || rerr.Requirement != "architecture" {
+		t.Fatalf("unexpected requirement error: %+v", rerr)
+	}
+
+	// the statement does not include any initrd
+	if err := d.Entries[1].Err(); !errors.Is(err, ErrMissingCategory) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a := d.Entries[1].Artifacts; len(a) != 2 || !a[0].Passed || a[1].Artifact != -1 {
+		t.Fatalf("unexpected artifact results: %+v", a)
+	}
+
+	// the statement is not signed
+	if err := d.Entries[2].Err(); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if q := d.Entries[2].Quorum; q == nil || q.Passed || q.Valid != 0 {
+		t.Fatalf("unexpected quorum result: %+v", q)
+	}
+
+	// the second entry is the first one closest to matching
+	if d.Entry != 1 || !errors.Is(d.Err(), ErrMissingCategory) {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+
+	// error expected here as the policy is empty
+	if err := evaluate(t, []byte(`[]`)).Err(); !errors.Is(err, ErrEmptyPolicy) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEvaluateValidity(t *testing.T) {
+	p, err := Parse([]byte(`[
+{
+    "artifacts": [
+        {
+            "category": 1,
+            "requirements": {}
+        }
+    ],
+    "validity": {
+        "current": true,
+        "expiry": true
+    }
+}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse(decisionStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.NotBefore = "2025-10-01T00:00:00Z"
+	s.NotAfter = "2026-10-01T00:00:00Z"
+
+	at := func(ts string) *Environment {
+		return &Environment{
+			Time: func() (time.Time, error) {
+				return time.Parse(time.RFC3339, ts)
+			},
+		}
+	}
+
+	d, err := EvaluateWith(p, s, at("2026-01-01T00:00:00Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !d.Allowed || d.Err() != nil {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+
+	if v := d.Entries[0].Validity; v == nil || !v.Passed || v.Time != "2026-01-01T00:00:00Z" {
+		t.Fatalf("unexpected validity result: %+v", v)
+	}
+
+	if err = CheckWith(p, s, at("2026-01-01T00:00:00Z")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeEvaluateValidity(t *testing.T) {
+	p, err := Parse([]byte(`[
+{
+    "artifacts": [
+        {
+            "category": 1,
+            "requirements": {}
+        }
+    ],
+    "validity": {
+        "current": true,
+        "expiry": true
+    }
+}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse(decisionStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := func() (time.Time, error) {
+		return time.Parse(time.RFC3339, "2027-01-01T00:00:00Z")
+	}
+
+	// error expected here as the statement does not declare its expiry
+	if err = CheckWith(p, s, &Environment{Time: now}); !errors.Is(err, ErrValidityNotMet) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s.NotAfter = "2026-10-01T00:00:00Z"
+
+	// error expected here as the statement is expired
+	if err = CheckWith(p, s, &Environment{Time: now}); !errors.Is(err, ErrValidityNotMet) || !errors.Is(err, statement.ErrExpired) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// error expected here as the time source is not available
+	if err = Check(p, s); !errors.Is(err, ErrNoTimeSource) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failing := func() (time.Time, error) {
+		return time.Time{}, errors.New("untrusted clock")
+	}
+
+	// error expected here as the time source fails
+	d, err := EvaluateWith(p, s, &Environment{Time: failing})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := d.Entries[0].Validity; d.Allowed || v == nil || v.Passed || !errors.Is(d.Err(), ErrNoTimeSource) {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+}
+
+func TestEvaluateTarget(t *testing.T) {
+	p, err := Parse([]byte(`[
+{
+    "artifacts": [
+        {
+            "category": 1,
+            "requirements": {}
+        }
+    ],
+    "target": {
+        "match": true,
+        "declared": true
+    }
+}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse(decisionStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Target = &statement.Target{
+		DMIVendors: []string{"LENOVO"},
+		Machines:   []string{"x64"},
+	}
+
+	env := &Environment{
+		Device: &statement.Device{
+			Machine:   "x64",
+			DMIVendor: "LENOVO",
+			DMIModel:  "21KCCTO1WW",
+		},
+	}
+
+	d, err := EvaluateWith(p, s, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !d.Allowed || d.Err() != nil {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+
+	if r := d.Entries[0].Target; r == nil || !r.Passed {
+		t.Fatalf("unexpected target result: %+v", r)
+	}
+}
+
+func TestNegativeEvaluateTarget(t *testing.T) {
+	p, err := Parse([]byte(`[
+{
+    "artifacts": [
+        {
+            "category": 1,
+            "requirements": {}
+        }
+    ],
+    "target": {
+        "match": true,
+        "declared": true
+    }
+}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse(decisionStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := &Environment{
+		Device: &statement.Device{
+			Machine: "AA64",
+		},
+	}
+
+	// error expected here as the statement does not declare its target
+	if err = CheckWith(p, s, env); !errors.Is(err, ErrTargetNotMet) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s.Target = &statement.Target{
+		Machines: []string{"x64"},
+	}
+
+	// error expected here as the device is not targeted
+	if err = CheckWith(p, s, env); !errors.Is(err, ErrTargetNotMet) || !errors.Is(err, statement.ErrTargetMismatch) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// error expected here as the device identity is not available
+	d, err := Evaluate(p, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r := d.Entries[0].Target; d.Allowed || r == nil || r.Passed || !errors.Is(d.Err(), ErrNoDevice) {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+}
@@ -0,0 +1,97 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/usbarmory/boot-transparency/statement"
+)
+
+var (
+	// ErrNoTimeSource is returned when the statement validity cannot be
+	// checked as the evaluation environment does not provide any time
+	// source.
+	ErrNoTimeSource = errors.New("time source not available")
+
+	// ErrNoDevice is returned when the statement target cannot be
+	// checked as the evaluation environment does not provide the device
+	// identity.
+	ErrNoDevice = errors.New("device identity not available")
+)
+
+// Define the boot environment, supplied by the caller, against which the
+// policy requirements depending on it are evaluated.
+type Environment struct {
+	// time source, used to check the statement validity period.
+	// Devices without a trusted real-time clock might return a time
+	// derived from authenticated data (e.g. the timestamp of the log
+	// tree head cosignatures).
+	Time func() (time.Time, error)
+
+	// identity of the booted device, used to check the statement target
+	Device *statement.Device
+
+	// revocation statements (see statement.KindRevocation), whose
+	// transparency proofs have been verified by the caller
+	Revocations []*statement.Statement
+}
+
+// check the statement validity period against the environment time source,
+// the evaluation time is reported in the given result
+func checkValidity(p *ValidityRequirement, s *statement.Statement, env *Environment, v *ValidityResult) error {
+	if p.Expiry && s.NotAfter == "" {
+		return fmt.Errorf("%w: the statement does not declare its expiry", ErrValidityNotMet)
+	}
+
+	if !p.Current {
+		return nil
+	}
+
+	if env == nil || env.Time == nil {
+		return fmt.Errorf("%w: %w", ErrValidityNotMet, ErrNoTimeSource)
+	}
+
+	now, err := env.Time()
+
+	if err != nil {
+		return fmt.Errorf("%w: %w: %v", ErrValidityNotMet, ErrNoTimeSource, err)
+	}
+
+	v.Time = now.UTC().Format(time.RFC3339)
+
+	if err = s.ValidAt(now); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidityNotMet, err)
+	}
+
+	return nil
+}
+
+// check the statement target against the environment device identity
+func checkTarget(p *TargetRequirement, s *statement.Statement, env *Environment) error {
+	if p.Declared && s.Target.Empty() {
+		return fmt.Errorf("%w: the statement does not declare its target", ErrTargetNotMet)
+	}
+
+	// statements without target selectors are targeting any device
+	if !p.Match || s.Target.Empty() {
+		return nil
+	}
+
+	if env == nil || env.Device == nil {
+		return fmt.Errorf("%w: %w", ErrTargetNotMet, ErrNoDevice)
+	}
+
+	if err := s.Target.Matches(env.Device); err != nil {
+		return fmt.Errorf("%w: %w", ErrTargetNotMet, err)
+	}
+
+	return nil
+}
@@ -8,13 +8,13 @@
 package policy
 
 import (
+	"crypto"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 
-	"sigsum.org/sigsum-go/pkg/crypto"
-	"sigsum.org/sigsum-go/pkg/key"
-
 	"github.com/usbarmory/boot-transparency/artifact"
+	"github.com/usbarmory/boot-transparency/signature"
 	"github.com/usbarmory/boot-transparency/statement"
 )
 
@@ -23,8 +23,12 @@ type Signer struct {
 	// human-readable signer name
 	Name string `json:"name,omitempty"`
 
-	// signer's public key
-	PubKey string `json:"pub_key"`
+	// signer's public key, in OpenSSH or PEM format
+	PubKey string `json:"pub_key" validate:"required"`
+
+	// signature algorithm (e.g. signature.ECDSAP256SHA256), when set only
+	// signatures using this algorithm are accepted for the signer
+	Algorithm string `json:"algorithm,omitempty"`
 }
 
 // Define a signing quorum that must be satisfied to authorize the bundle
@@ -34,6 +38,34 @@ type SigningRequirement struct {
 
 	// requires at least n signatures out of the total number of trusted signers
 	Quorum uint64 `json:"quorum"`
+
+	// accept legacy signatures, computed over the statement artifacts
+	// without any signing envelope (see statement.SignatureNamespace),
+	// never accepted by policy entries with validity or target
+	// requirements, nor when evaluating revocation statements trusted by
+	// the entry
+	Legacy bool `json:"legacy,omitempty"`
+}
+
+// Define the statement validity requirements
+type ValidityRequirement struct {
+	// require the statement to be valid (see statement.ValidAt()) at the
+	// time returned by the time source of the evaluation environment
+	Current bool `json:"current,omitempty"`
+
+	// require the statement to declare the end of its validity period
+	// (i.e. not_after)
+	Expiry bool `json:"expiry,omitempty"`
+}
+
+// Define the statement target requirements
+type TargetRequirement struct {
+	// require the statement to target (see statement.Target.Matches())
+	// the device of the evaluation environment
+	Match bool `json:"match,omitempty"`
+
+	// require the statement to declare its targeted devices
+	Declared bool `json:"declared,omitempty"`
 }
 
 // Define the required set of properties to authorize an artifact from a given category.
@@ -56,33 +88,57 @@ type PolicyEntry struct {
 
 	// require at least a quorum of n signatures for the bundle
 	Signatures SigningRequirement `json:"signatures,omitempty"`
+
+	// require the statement to be within its validity period
+	Validity ValidityRequirement `json:"validity,omitempty"`
+
+	// require the statement to target the booted device
+	Target TargetRequirement `json:"target,omitempty"`
+
+	// trusted signers of revocation statements (see
+	// statement.KindRevocation), the entry signing requirement is used
+	// when no quorum is set
+	Revokers SigningRequirement `json:"revokers,omitempty"`
 }
 
 // Parse the boot policy requirements from the serialized JSON
 //
-// Return error if:
+// The parsing is strict, unknown fields are rejected and the requirements
+// are validated according to their artifact category (see
+// artifact.Unmarshal()).
+//
+// Return error, as *artifact.FieldError pinpointing the JSON path of the
+// offending field when applicable, if:
 //   - the parsing fails
+//   - an artifact category is not registered
+//   - the requirements parsing fails
 func Parse(jsonPolicy []byte) (policy *[]PolicyEntry, err error) {
 	var h artifact.Handler
 
-	if err = json.Unmarshal(jsonPolicy, &policy); err != nil {
-		return
+	if err = artifact.Unmarshal(jsonPolicy, &policy); err != nil {
+		return nil, err
+	}
+
+	if policy == nil {
+		return nil, ErrEmptyPolicy
 	}
 
 	// the policy is an array of entries (i.e. per-bundle requirements).
 	// Each entry needs deeper parsing to ensure consistency between the specified
 	// artifact requirements and the ones supported by the given artifact category
-	for _, entry := range *policy {
-		for _, a := range entry.Artifacts {
+	for i, entry := range *policy {
+		for j, a := range entry.Artifacts {
+			path := fmt.Sprintf("[%d].artifacts[%d]", i, j)
+
 			// check if an artifact handler is registered for the given artifact category
 			h, err = artifact.GetHandler(a.Category)
 			if err != nil {
-				return
+				return nil, artifact.AtPath(path+".category", err)
 			}
 
 			// invoke the correspondent requirement parser for the given artifact category
 			if _, err = h.ParseRequirements(a.Requirements); err != nil {
-				return
+				return nil, artifact.AtPath(path+".requirements", err)
 			}
 		}
 	}
@@ -102,119 +158,87 @@ func Parse(jsonPolicy []byte) (policy *[]PolicyEntry, err error) {
 //
 // Return error if:
 //   - the bundle does not met the policy requirements
+//   - the policy is empty
 //   - the claim parsing fails
 //   - the requirement parsing fails
 func Check(p *[]PolicyEntry, s *statement.Statement) (err error) {
-	var h artifact.Handler
-
-	// traverse the policy
-	for _, entry := range *p {
-		// reset any error got while checking the previous policy entry
-		err = nil
-
-		// if this policy entry requires a signing quorum to authorize the bundle,
-		// check the number of valid signatures in the logged statement
-		if entry.Signatures.Quorum > 0 {
-			err = checkSigningQuorum(&entry.Signatures, s)
-			if err != nil {
-				continue // quorum not satisfied try with the next policy entry
-			}
-		}
-
-		// check all the per-category requirements against the claimed
-		// properties for the artifacts present in the bundle
-		for _, policyArtifact := range entry.Artifacts {
-			// this means that the latest checked artifact did not met the requirements
-			if err != nil {
-				break // try with the next policy entry
-			}
-
-			h, err = artifact.GetHandler(policyArtifact.Category)
-
-			// return immediately if the policy requirements for this artifact
-			// cannot be checked. The handler for this category, that is included
-			// in the policy, is not registered
-			if err != nil {
-				return
-			}
-
-			matchCategory := false
-			for _, statementArtifact := range s.Artifacts {
-				if policyArtifact.Category == statementArtifact.Category {
-					matchCategory = true
-					r, parseError := h.ParseRequirements([]byte(policyArtifact.Requirements))
-
-					if parseError != nil {
-						return parseError
-					}
-
-					c, parseError := h.ParseClaims([]byte(statementArtifact.Claims))
-
-					if parseError != nil {
-						return parseError
-					}
-
-					// stop checking this bundle at the first artifact that
-					// does not met the requirements
-					err = h.Check(r, c)
-					if err != nil {
-						break
-					}
-				}
-			}
+	return CheckWith(p, s, nil)
+}
 
-			// do not continue checking this bundle
-			// cannot authorize bundles that are not containing at least one artifact
-			// that is compatible (i.e. same category) with the one required by this policy entry
-			if !matchCategory {
-				err = fmt.Errorf("the boot bundle does not include a required artifact category")
-				break // try with the next policy entry
-			}
-		}
+// Check if the claims present in a given statement are satisfying the
+// policy requirements, within a given evaluation environment (see Check()).
+//
+// Return error if:
+//   - any of the Check() conditions is met
+func CheckWith(p *[]PolicyEntry, s *statement.Statement, env *Environment) (err error) {
+	d, err := EvaluateWith(p, s, env)
 
-		// return on the first policy entry that authorize the bundle
-		if err == nil {
-			return
-		}
+	if err != nil {
+		return
 	}
 
-	// return latest error encountered while traversing the policy array
-	// that contains the per-bundle rule sets
-	return
+	// return the error of the policy entry closest to matching when
+	// none of the entries authorizes the bundle
+	return d.Err()
 }
 
 // check validity of the signatures present in the statement against
 // the required quorum
 // return error if an insufficient number of valid signatures is found
-func checkSigningQuorum(p *SigningRequirement, s *statement.Statement) (err error) {
-	var validSignatures uint64
-
-	artifacts, err := json.Marshal(s.Artifacts)
-
-	if err != nil {
-		return
-	}
-
+func checkSigningQuorum(p *SigningRequirement, s *statement.Statement) (validSignatures uint64, err error) {
 	// total valid signatures
 	validSignatures = 0
 
 	// loop through all the trusted signers set in the policy
 	for _, signer := range p.Signers {
+		var pub crypto.PublicKey
+
+		if pub, err = signature.ParsePublicKey(signer.PubKey); err != nil {
+			return
+		}
+
 		gotValidSignature := false
 		for _, sig := range s.Signatures {
-			var k crypto.PublicKey
-			var s crypto.Signature
+			// legacy signatures are not bound to the approval of a boot
+			// bundle and can only be accepted if explicitly allowed
+			if sig.Envelope == "" && !p.Legacy {
+				continue
+			}
 
-			if k, err = key.ParsePublicKey(signer.PubKey); err != nil {
-				return
+			algorithm := sig.Algorithm
+
+			if algorithm == "" {
+				algorithm = signature.Ed25519
 			}
 
-			if s, err = crypto.SignatureFromHex(sig.Signature); err != nil {
+			// the signature algorithm must match the signer one, if set
+			if signer.Algorithm != "" && signer.Algorithm != algorithm {
+				continue
+			}
+
+			payloads, payloadErr := s.SignedPayloads(&sig)
+
+			// signatures with unsupported envelopes are not valid
+			if payloadErr != nil {
+				continue
+			}
+
+			var rawSig []byte
+
+			if rawSig, err = hex.DecodeString(sig.Signature); err != nil {
 				return
 			}
 
-			if crypto.Verify(&k, artifacts, &s) {
-				gotValidSignature = true
+			for _, payload := range payloads {
+				// signatures with unsupported algorithms, or algorithms not
+				// matching the signer key, are not valid
+				if signature.Verify(algorithm, pub, payload, rawSig) == nil {
+					gotValidSignature = true
+					break
+				}
+			}
+
+			if gotValidSignature {
 				break
 			}
 		}
@@ -227,7 +251,7 @@ func checkSigningQuorum(p *SigningRequirement, s *statement.Statement) (err erro
 	}
 
 	if validSignatures < p.Quorum {
-		return fmt.Errorf("insufficient number of valid signatures (%d), policy quorum of %d not reached", validSignatures, p.Quorum)
+		return validSignatures, fmt.Errorf("%w: insufficient number of valid signatures (%d), policy quorum of %d not reached", ErrQuorumNotMet, validSignatures, p.Quorum)
 	}
 
 	return
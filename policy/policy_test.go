@@ -8,8 +8,24 @@
 package policy
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"golang.org/x/crypto/ssh"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+	"github.com/usbarmory/boot-transparency/signature"
 	"github.com/usbarmory/boot-transparency/statement"
 )
 
@@ -51,6 +67,59 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestNegativeParse(t *testing.T) {
+	for _, v := range []struct {
+		policy string
+		path   string
+		err    error
+	}{
+		{
+			`[{"artifacts": [{"category": 1, "requirements": {"min_verison": "v6.14.0"}}]}]`,
+			"[0].artifacts[0].requirements.min_verison",
+			artifact.ErrUnknownField,
+		},
+		{
+			`[{"artifacts": [{"category": 1, "requirements": {"min_version": "6.14"}}]}]`,
+			"[0].artifacts[0].requirements.min_version",
+			artifact.ErrInvalidFormat,
+		},
+		{
+			`[{"artifacts": [], "signatures": {"signers": [{"name": "A"}], "quorum": 1}}]`,
+			"[0].signatures.signers[0].pub_key",
+			artifact.ErrMissingField,
+		},
+		{
+			`[{"artifacts": [], "signatures": {"quorum": "1"}}]`,
+			"[0].signatures.quorum",
+			artifact.ErrInvalidFormat,
+		},
+		{
+			`[{"artifacts": [], "signature": {}}]`,
+			"[0].signature",
+			artifact.ErrUnknownField,
+		},
+		{
+			`[{"artifacts": [], "validity": {"curent": true}}]`,
+			"[0].validity.curent",
+			artifact.ErrUnknownField,
+		},
+		{
+			`[{"artifacts": [], "target": {"match": "yes"}}]`,
+			"[0].target.match",
+			artifact.ErrInvalidFormat,
+		},
+	} {
+		var fe *artifact.FieldError
+
+		// error expected here as the policy is not valid
+		_, err := Parse([]byte(v.policy))
+
+		if !errors.As(err, &fe) || fe.Path != v.path || !errors.Is(err, v.err) {
+			t.Fatalf("unexpected error for %s: %v", v.policy, err)
+		}
+	}
+}
+
 func TestCheck(t *testing.T) {
 	p := []byte(`[
 {
@@ -241,3 +310,388 @@ func TestNegativeCheck(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestCheckSigningQuorum(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+
+	jsonPolicy := `[{
+    "artifacts": [{"category": 1, "requirements": {}}],
+    "signatures": {"signers": [{"pub_key": "%s"}], "quorum": 1, "legacy": %v}
+}]`
+
+	p, err := Parse([]byte(fmt.Sprintf(jsonPolicy, pubKey, false)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacyPolicy, err := Parse([]byte(fmt.Sprintf(jsonPolicy, pubKey, true)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse([]byte(`{
+    "description": "Linux bundle",
+    "version": "v1",
+    "artifacts": [
+        {
+            "category": 1,
+            "claims": {
+                "hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59",
+                "version": "v6.14.0-29-generic",
+                "architecture": "x64"
+            }
+        }
+    ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(msg []byte, envelope string) {
+		s.Signatures = []statement.Signature{
+			{
+				PubKey:    pubKey,
+				Signature: hex.EncodeToString(ed25519.Sign(priv, msg)),
+				Envelope:  envelope,
+			},
+		}
+	}
+
+	payload, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign(payload, statement.SignatureNamespace)
+
+	for _, p := range []*[]PolicyEntry{p, legacyPolicy} {
+		if err = Check(p, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// legacy signatures, over the canonical or (by earlier releases)
+	// compact form of the artifacts, are accepted only in legacy mode
+	legacyPayload, err := s.LegacySigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compactPayload, err := json.Marshal(s.Artifacts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, msg := range [][]byte{legacyPayload, compactPayload} {
+		sign(msg, "")
+
+		if err = Check(legacyPolicy, s); err != nil {
+			t.Fatal(err)
+		}
+
+		// error expected here as legacy signatures are not allowed
+		if err = Check(p, s); !errors.Is(err, ErrQuorumNotMet) {
+			t.Fatalf("legacy signature has been accepted: %v", err)
+		}
+	}
+}
+
+func TestNegativeCheckSigningQuorum(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+
+	p, err := Parse([]byte(fmt.Sprintf(`[{
+    "artifacts": [{"category": 1, "requirements": {}}],
+    "signatures": {"signers": [{"pub_key": "%s"}], "quorum": 1, "legacy": true}
+}]`, pubKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse([]byte(`{
+    "description": "Linux bundle",
+    "version": "v1",
+    "artifacts": [{"category": 1, "claims": {"hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59", "version": "v6.14.0-29-generic"}}]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := statement.Signature{
+		PubKey:    pubKey,
+		Signature: hex.EncodeToString(ed25519.Sign(priv, payload)),
+		Envelope:  statement.SignatureNamespace,
+	}
+
+	s.Signatures = []statement.Signature{sig}
+
+	// error expected here as the signed description has been modified
+	s.Description = "Modified bundle"
+
+	if err = Check(p, s); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("statement with invalid signature has been authorized: %v", err)
+	}
+
+	s.Description = "Linux bundle"
+
+	// error expected here as the signed artifacts have been modified
+	s.Artifacts[0].Claims = json.RawMessage(`{"hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59", "version": "v6.14.0-30-generic"}`)
+
+	if err = Check(p, s); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("statement with invalid signature has been authorized: %v", err)
+	}
+
+	s.Artifacts[0].Claims = json.RawMessage(`{"hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59", "version": "v6.14.0-29-generic"}`)
+
+	// error expected here as the envelope is not supported
+	s.Signatures[0].Envelope = "boot-transparency-statement-v0"
+
+	if err = Check(p, s); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("signature with unsupported envelope has been authorized: %v", err)
+	}
+
+	// error expected here as the enveloped signature is not valid as
+	// legacy signature
+	s.Signatures[0].Envelope = ""
+
+	if err = Check(p, s); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("signature with invalid envelope has been authorized: %v", err)
+	}
+}
+
+func TestNegativeCheckSigningQuorumValidity(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+
+	p, err := Parse([]byte(fmt.Sprintf(`[{
+    "artifacts": [{"category": 1, "requirements": {}}],
+    "signatures": {"signers": [{"pub_key": "%s"}], "quorum": 1, "legacy": true},
+    "validity": {"current": true, "expiry": true}
+}]`, pubKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse([]byte(`{
+    "description": "Linux bundle",
+    "version": "v1",
+    "not_after": "2026-10-01T00:00:00Z",
+    "artifacts": [{"category": 1, "claims": {"hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59", "version": "v6.14.0-29-generic"}}]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := &Environment{
+		Time: func() (time.Time, error) {
+			return time.Parse(time.RFC3339, "2027-01-01T00:00:00Z")
+		},
+	}
+
+	payload, err := s.LegacySigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Signatures = []statement.Signature{
+		{
+			PubKey:    pubKey,
+			Signature: hex.EncodeToString(ed25519.Sign(priv, payload)),
+		},
+	}
+
+	// the legacy signature does not cover the validity period, which is
+	// therefore extended without invalidating it
+	s.NotAfter = "2099-01-01T00:00:00Z"
+
+	// error expected here as legacy signatures are not accepted by policy
+	// entries with validity requirements
+	if err = CheckWith(p, s, env); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("statement with tampered validity period has been authorized: %v", err)
+	}
+
+	(*p)[0].Validity = ValidityRequirement{}
+
+	if err = CheckWith(p, s, env); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeCheckSigningQuorumTarget(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+
+	p, err := Parse([]byte(fmt.Sprintf(`[{
+    "artifacts": [{"category": 1, "requirements": {}}],
+    "signatures": {"signers": [{"pub_key": "%s"}], "quorum": 1, "legacy": true},
+    "target": {"match": true, "declared": true}
+}]`, pubKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse([]byte(`{
+    "description": "Linux bundle",
+    "version": "v1",
+    "target": {"machines": ["AA64"]},
+    "artifacts": [{"category": 1, "claims": {"hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59", "version": "v6.14.0-29-generic"}}]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := &Environment{
+		Device: &statement.Device{
+			Machine: "x64",
+		},
+	}
+
+	payload, err := s.LegacySigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Signatures = []statement.Signature{
+		{
+			PubKey:    pubKey,
+			Signature: hex.EncodeToString(ed25519.Sign(priv, payload)),
+		},
+	}
+
+	// the legacy signature does not cover the target, which is therefore
+	// retargeted without invalidating it
+	s.Target.Machines = []string{"x64"}
+
+	// error expected here as legacy signatures are not accepted by policy
+	// entries with target requirements
+	if err = CheckWith(p, s, env); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("statement with tampered target has been authorized: %v", err)
+	}
+
+	(*p)[0].Target = TargetRequirement{}
+
+	if err = CheckWith(p, s, env); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckSigningQuorumAlgorithms(t *testing.T) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	jsonPolicy := `[{
+    "artifacts": [{"category": 1, "requirements": {}}],
+    "signatures": {"signers": [{"pub_key": %q, "algorithm": "%s"}], "quorum": 1}
+}]`
+
+	s, err := statement.Parse([]byte(`{
+    "description": "Linux bundle",
+    "version": "v1",
+    "artifacts": [{"category": 1, "claims": {"hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59", "version": "v6.14.0-29-generic"}}]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := signature.Sign(signature.ECDSAP256SHA256, k, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Signatures = []statement.Signature{
+		{
+			PubKey:    pemKey,
+			Signature: hex.EncodeToString(sig),
+			Envelope:  statement.SignatureNamespace,
+			Algorithm: signature.ECDSAP256SHA256,
+		},
+	}
+
+	for _, algorithm := range []string{"", signature.ECDSAP256SHA256} {
+		p, err := Parse([]byte(fmt.Sprintf(jsonPolicy, pemKey, algorithm)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = Check(p, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// error expected here as the signer is restricted to another algorithm
+	p, err := Parse([]byte(fmt.Sprintf(jsonPolicy, pemKey, signature.ECDSAP384SHA384)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = Check(p, s); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("signature with disallowed algorithm has been authorized: %v", err)
+	}
+
+	// error expected here as the signature algorithm does not match the key
+	s.Signatures[0].Algorithm = signature.Ed25519
+
+	if p, err = Parse([]byte(fmt.Sprintf(jsonPolicy, pemKey, ""))); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = Check(p, s); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("signature with mismatching algorithm has been authorized: %v", err)
+	}
+}
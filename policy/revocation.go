@@ -0,0 +1,95 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/usbarmory/boot-transparency/statement"
+)
+
+// ErrRevoked is returned when the statement has been revoked, or
+// superseded, by a trusted revocation statement.
+var ErrRevoked = errors.New("the statement has been revoked")
+
+// ErrUntrustedRevocations is returned when revocation statements are
+// supplied for a policy entry which does not set any signing quorum to
+// trust them, therefore revocations cannot be enforced.
+var ErrUntrustedRevocations = errors.New("revocations cannot be trusted without a signing quorum")
+
+// Return the signing requirement for the revocation statements of a policy
+// entry, nil if revocations cannot be trusted as no signing quorum is set.
+func revokers(entry *PolicyEntry) *SigningRequirement {
+	req := entry.Revokers
+
+	if req.Quorum == 0 {
+		req = entry.Signatures
+	}
+
+	if req.Quorum == 0 {
+		return nil
+	}
+
+	// legacy signatures only cover the statement artifacts, which are not
+	// present in revocation statements, therefore they are never accepted
+	req.Legacy = false
+
+	return &req
+}
+
+// check whether the statement is revoked by any revocation statement of
+// the environment satisfying the revocation signing quorum, the check
+// fails when the entry does not set any quorum to trust revocations
+func checkRevocation(entry *PolicyEntry, s *statement.Statement, env *Environment, v *RevocationResult) error {
+	req := revokers(entry)
+
+	// revocations must not be silently ignored, as the caller expects
+	// them to be enforced
+	if req == nil {
+		return ErrUntrustedRevocations
+	}
+
+	digest, err := s.Digest()
+
+	if err != nil {
+		return err
+	}
+
+	for i, rs := range env.Revocations {
+		r := rs.Revocation(digest)
+
+		if r == nil {
+			continue
+		}
+
+		if _, err := checkSigningQuorum(req, rs); err != nil {
+			continue
+		}
+
+		v.Statement = i
+		v.Status = r.Status
+
+		switch {
+		case r.SupersededBy != "":
+			err = fmt.Errorf("%w (%s by %s)", ErrRevoked, r.Status, r.SupersededBy)
+		case r.Status != statement.Revoked:
+			err = fmt.Errorf("%w (%s)", ErrRevoked, r.Status)
+		default:
+			err = ErrRevoked
+		}
+
+		if r.Reason != "" {
+			err = fmt.Errorf("%w: %s", err, r.Reason)
+		}
+
+		return err
+	}
+
+	return nil
+}
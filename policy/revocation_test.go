@@ -0,0 +1,257 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/usbarmory/boot-transparency/statement"
+)
+
+type testSigner struct {
+	pubKey string
+	priv   ed25519.PrivateKey
+}
+
+func newTestSigner(t *testing.T) *testSigner {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testSigner{
+		pubKey: strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))),
+		priv:   priv,
+	}
+}
+
+func (ts *testSigner) sign(t *testing.T, s *statement.Statement) {
+	payload, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Signatures = append(s.Signatures, statement.Signature{
+		PubKey:    ts.pubKey,
+		Signature: hex.EncodeToString(ed25519.Sign(ts.priv, payload)),
+		Envelope:  statement.SignatureNamespace,
+	})
+}
+
+func newRevocation(t *testing.T, revoked *statement.Statement, status string) *statement.Statement {
+	digest, err := revoked.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &statement.Statement{
+		Kind: statement.KindRevocation,
+		Revokes: []statement.Revocation{
+			{
+				Digest: digest,
+				Status: status,
+				Reason: "CVE-2025-38236",
+			},
+		},
+	}
+}
+
+func TestCheckRevocation(t *testing.T) {
+	release := newTestSigner(t)
+	security := newTestSigner(t)
+
+	p, err := Parse([]byte(fmt.Sprintf(`[{
+    "artifacts": [{"category": 1, "requirements": {}}],
+    "signatures": {"signers": [{"pub_key": "%s"}], "quorum": 1},
+    "revokers": {"signers": [{"pub_key": "%s"}], "quorum": 1}
+}]`, release.pubKey, security.pubKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse(decisionStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release.sign(t, s)
+
+	// revocations of other statements are not affecting the bundle
+	other := *s
+	other.Version = "v2"
+
+	r := newRevocation(t, &other, statement.Revoked)
+	security.sign(t, r)
+
+	env := &Environment{
+		Revocations: []*statement.Statement{r},
+	}
+
+	d, err := EvaluateWith(p, s, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := d.Entries[0].Revocation; !d.Allowed || v == nil || !v.Passed || v.Statement != -1 {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+
+	// revocations by untrusted signers are ignored
+	r = newRevocation(t, s, statement.Revoked)
+	release.sign(t, r)
+
+	if err = CheckWith(p, s, &Environment{Revocations: []*statement.Statement{r}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeCheckRevocation(t *testing.T) {
+	release := newTestSigner(t)
+
+	// the revokers are not set, the release signers are trusted
+	p, err := Parse([]byte(fmt.Sprintf(`[{
+    "artifacts": [{"category": 1, "requirements": {}}],
+    "signatures": {"signers": [{"pub_key": "%s"}], "quorum": 1}
+}]`, release.pubKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse(decisionStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release.sign(t, s)
+
+	other := *s
+	other.Version = "v2"
+
+	for _, status := range []string{statement.Revoked, statement.Superseded} {
+		r := newRevocation(t, s, status)
+		release.sign(t, r)
+
+		env := &Environment{
+			Revocations: []*statement.Statement{newRevocation(t, &other, statement.Revoked), r},
+		}
+
+		d, err := EvaluateWith(p, s, env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// error expected here as the bundle is revoked
+		if v := d.Entries[0].Revocation; d.Allowed || !errors.Is(d.Err(), ErrRevoked) || v == nil || v.Statement != 1 || v.Status != status {
+			t.Fatalf("revoked bundle has been authorized: %+v", d)
+		}
+
+		// error expected here as revocation statements are not bundles
+		if _, err = EvaluateWith(p, r, nil); err == nil {
+			t.Fatal("revocation statement has been evaluated as a bundle")
+		}
+	}
+}
+
+func TestNegativeCheckRevocationLegacy(t *testing.T) {
+	release := newTestSigner(t)
+
+	p, err := Parse([]byte(fmt.Sprintf(`[{
+    "artifacts": [{"category": 1, "requirements": {}}],
+    "signatures": {"signers": [{"pub_key": "%s"}], "quorum": 1, "legacy": true}
+}]`, release.pubKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse(decisionStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := s.LegacySigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Signatures = []statement.Signature{
+		{
+			PubKey:    release.pubKey,
+			Signature: hex.EncodeToString(ed25519.Sign(release.priv, payload)),
+		},
+	}
+
+	if err = Check(p, s); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newRevocation(t, s, statement.Revoked)
+	release.sign(t, r)
+
+	env := &Environment{
+		Revocations: []*statement.Statement{r},
+	}
+
+	// legacy signatures do not cover the description, therefore they
+	// still verify on a copy of the revoked statement with a different
+	// digest
+	edited := *s
+	edited.Description = "Linux bundle (re-logged)"
+
+	// error expected here as legacy signatures are not accepted along
+	// with revocations
+	for _, s := range []*statement.Statement{s, &edited} {
+		if err = CheckWith(p, s, env); err == nil {
+			t.Fatal("revoked bundle has been authorized")
+		}
+	}
+}
+
+func TestNegativeCheckRevocationUntrusted(t *testing.T) {
+	release := newTestSigner(t)
+
+	// neither revokers nor signatures quorum are set
+	p, err := Parse([]byte(`[{"artifacts": [{"category": 1, "requirements": {}}]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := statement.Parse(decisionStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = CheckWith(p, s, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newRevocation(t, s, statement.Revoked)
+	release.sign(t, r)
+
+	d, err := EvaluateWith(p, s, &Environment{Revocations: []*statement.Statement{r}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the revocations cannot be enforced
+	if v := d.Entries[0].Revocation; d.Allowed || !errors.Is(d.Err(), ErrUntrustedRevocations) || v == nil || v.Passed {
+		t.Fatalf("revocations have been ignored: %+v", d)
+	}
+}
@@ -0,0 +1,156 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// Define a signer backed by a key held by an SSH agent.
+//
+// SSH agents sign messages, rather than digests, therefore the signer is
+// meant to be used through crypto.MessageSigner (e.g. by Sign()). Ed25519
+// and ECDSA keys are supported, while RSA keys are not as SSH agents do not
+// support RSA-PSS signatures.
+type AgentSigner struct {
+	agent agent.Agent
+	key   ssh.PublicKey
+	pub   crypto.PublicKey
+}
+
+// Connect to the SSH agent listening on the socket set in the SSH_AUTH_SOCK
+// environment variable.
+//
+// Return error if:
+//   - the SSH_AUTH_SOCK environment variable is not set
+//   - the connection to the agent socket fails
+func DialAgent() (agent.ExtendedAgent, io.Closer, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+
+	if socket == "" {
+		return nil, nil, fmt.Errorf("SSH_AUTH_SOCK not set")
+	}
+
+	conn, err := net.Dial("unix", socket)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot connect to SSH agent: %v", err)
+	}
+
+	return agent.NewClient(conn), conn, nil
+}
+
+// Return a signer for the SSH agent key matching the given public key.
+//
+// Return error if:
+//   - the public key type is not supported
+//   - the key listing fails
+//   - the key is not held by the agent
+func NewAgentSigner(a agent.Agent, pub crypto.PublicKey) (*AgentSigner, error) {
+	key, err := ssh.NewPublicKey(pub)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := a.List()
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot list SSH agent keys: %v", err)
+	}
+
+	for _, k := range keys {
+		if bytes.Equal(k.Marshal(), key.Marshal()) {
+			return &AgentSigner{agent: a, key: key, pub: pub}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("key %s not held by SSH agent", ssh.FingerprintSHA256(key))
+}
+
+// Return the public key of the signer.
+func (s *AgentSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign a message, pre-hashed messages are not supported therefore only
+// Ed25519 signatures (i.e. opts.HashFunc() equal to zero) are produced.
+func (s *AgentSigner) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != 0 {
+		return nil, fmt.Errorf("SSH agent cannot sign pre-hashed messages")
+	}
+
+	return s.SignMessage(rand, message, opts)
+}
+
+// Sign a message with the SSH agent, the signature is returned in the
+// format expected by the corresponding Algorithm (i.e. raw for Ed25519,
+// ASN.1 DER for ECDSA).
+func (s *AgentSigner) SignMessage(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	switch k := s.pub.(type) {
+	case ed25519.PublicKey:
+		if opts.HashFunc() != 0 {
+			return nil, fmt.Errorf("invalid hash function for Ed25519 key")
+		}
+	case *ecdsa.PublicKey:
+		// SSH agents select the hash function according to the curve
+		if opts.HashFunc() != ecdsaHash(k.Curve) {
+			return nil, fmt.Errorf("invalid hash function for ECDSA key")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported SSH agent key type %T", s.pub)
+	}
+
+	sig, err := s.agent.Sign(s.key, message)
+
+	if err != nil {
+		return nil, fmt.Errorf("SSH agent signing failed: %v", err)
+	}
+
+	if _, ok := s.pub.(ed25519.PublicKey); ok {
+		return sig.Blob, nil
+	}
+
+	// RFC 5656 ECDSA signature blob
+	var rs struct {
+		R *big.Int
+		S *big.Int
+	}
+
+	if err = ssh.Unmarshal(sig.Blob, &rs); err != nil {
+		return nil, fmt.Errorf("invalid SSH agent signature: %v", err)
+	}
+
+	return asn1.Marshal(rs)
+}
+
+func ecdsaHash(curve elliptic.Curve) crypto.Hash {
+	switch curve {
+	case elliptic.P256():
+		return crypto.SHA256
+	case elliptic.P384():
+		return crypto.SHA384
+	case elliptic.P521():
+		return crypto.SHA512
+	}
+
+	return 0
+}
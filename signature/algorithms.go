@@ -0,0 +1,135 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+)
+
+// minimum RSA modulus size, in bits
+const minRSAKeySize = 2048
+
+func init() {
+	Register(Ed25519, &ed25519Algorithm{})
+	Register(ECDSAP256SHA256, &ecdsaAlgorithm{curve: elliptic.P256(), hash: crypto.SHA256})
+	Register(ECDSAP384SHA384, &ecdsaAlgorithm{curve: elliptic.P384(), hash: crypto.SHA384})
+	Register(RSAPSSSHA256, &rsaPSSAlgorithm{hash: crypto.SHA256})
+}
+
+// Define Ed25519 signatures, in their 64 bytes raw format
+type ed25519Algorithm struct{}
+
+func (a *ed25519Algorithm) Supports(pub crypto.PublicKey) bool {
+	_, ok := pub.(ed25519.PublicKey)
+	return ok
+}
+
+func (a *ed25519Algorithm) Sign(signer crypto.Signer, message []byte) ([]byte, error) {
+	return crypto.SignMessage(signer, rand.Reader, message, crypto.Hash(0))
+}
+
+func (a *ed25519Algorithm) Verify(pub crypto.PublicKey, message []byte, signature []byte) error {
+	if !a.Supports(pub) || !ed25519.Verify(pub.(ed25519.PublicKey), message, signature) {
+		return fmt.Errorf("invalid Ed25519 signature")
+	}
+
+	return nil
+}
+
+// Define ECDSA signatures, in ASN.1 DER format or, as commonly returned by
+// hardware security modules, in raw r || s format.
+type ecdsaAlgorithm struct {
+	curve elliptic.Curve
+	hash  crypto.Hash
+}
+
+func (a *ecdsaAlgorithm) Supports(pub crypto.PublicKey) bool {
+	k, ok := pub.(*ecdsa.PublicKey)
+	return ok && k.Curve == a.curve
+}
+
+func (a *ecdsaAlgorithm) digest(message []byte) []byte {
+	h := a.hash.New()
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+func (a *ecdsaAlgorithm) Sign(signer crypto.Signer, message []byte) ([]byte, error) {
+	return crypto.SignMessage(signer, rand.Reader, message, a.hash)
+}
+
+func (a *ecdsaAlgorithm) Verify(pub crypto.PublicKey, message []byte, signature []byte) error {
+	if !a.Supports(pub) {
+		return fmt.Errorf("invalid ECDSA public key")
+	}
+
+	k := pub.(*ecdsa.PublicKey)
+	digest := a.digest(message)
+	size := (a.curve.Params().BitSize + 7) / 8
+
+	if len(signature) == 2*size {
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+
+		if ecdsa.Verify(k, digest, r, s) {
+			return nil
+		}
+	} else if ecdsa.VerifyASN1(k, digest, signature) {
+		return nil
+	}
+
+	return fmt.Errorf("invalid ECDSA signature")
+}
+
+// Define RSASSA-PSS signatures, signed with a salt length equal to the
+// hash length and verified with any salt length.
+type rsaPSSAlgorithm struct {
+	hash crypto.Hash
+}
+
+func (a *rsaPSSAlgorithm) Supports(pub crypto.PublicKey) bool {
+	k, ok := pub.(*rsa.PublicKey)
+	return ok && k.N.BitLen() >= minRSAKeySize
+}
+
+func (a *rsaPSSAlgorithm) digest(message []byte) []byte {
+	h := a.hash.New()
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+func (a *rsaPSSAlgorithm) Sign(signer crypto.Signer, message []byte) ([]byte, error) {
+	return crypto.SignMessage(signer, rand.Reader, message, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       a.hash,
+	})
+}
+
+func (a *rsaPSSAlgorithm) Verify(pub crypto.PublicKey, message []byte, signature []byte) error {
+	if !a.Supports(pub) {
+		return fmt.Errorf("invalid RSA public key")
+	}
+
+	opts := &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthAuto,
+		Hash:       a.hash,
+	}
+
+	if err := rsa.VerifyPSS(pub.(*rsa.PublicKey), a.hash, a.digest(message), signature, opts); err != nil {
+		return fmt.Errorf("invalid RSA-PSS signature: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// Define a signer backed by an external helper command, allowing the use of
+// keys held by tokens (e.g. PKCS #11) or remote signing services.
+//
+// The helper is invoked with its configured arguments followed by:
+//   - `public-key`: the helper prints the public key, in OpenSSH or PEM
+//     format, to stdout
+//   - `sign <algorithm>`: the helper reads the message to sign from stdin,
+//     and prints the hex encoded signature, in the format expected by the
+//     named signature algorithm, to stdout
+//
+// A non-zero exit status of the helper is treated as error.
+//
+// The helper signs messages, rather than digests, therefore the signer is
+// meant to be used through crypto.MessageSigner (e.g. by Sign()).
+type ExternalSigner struct {
+	// signature algorithm passed to the helper, when empty it is
+	// inferred from the public key (see ForKey())
+	Algorithm string
+
+	path string
+	args []string
+	pub  crypto.PublicKey
+}
+
+// Return a signer for the given helper command, the helper is invoked to
+// retrieve the signer public key.
+//
+// Return error if:
+//   - the helper invocation fails
+//   - the public key parsing fails
+func NewExternalSigner(path string, args ...string) (*ExternalSigner, error) {
+	s := &ExternalSigner{
+		path: path,
+		args: args,
+	}
+
+	out, err := s.run(nil, "public-key")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.pub, err = ParsePublicKey(string(out)); err != nil {
+		return nil, fmt.Errorf("invalid signing helper public key: %v", err)
+	}
+
+	return s, nil
+}
+
+func (s *ExternalSigner) run(stdin []byte, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(s.path, append(s.args, args...)...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("signing helper %s failed: %v (%s)", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Return the public key of the signer.
+func (s *ExternalSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign a message, pre-hashed messages are not supported therefore only
+// Ed25519 signatures (i.e. opts.HashFunc() equal to zero) are produced.
+func (s *ExternalSigner) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != 0 {
+		return nil, fmt.Errorf("signing helper cannot sign pre-hashed messages")
+	}
+
+	return s.SignMessage(rand, message, opts)
+}
+
+// Sign a message with the helper, the signature algorithm is selected by
+// the signer Algorithm field rather than by the signer options.
+func (s *ExternalSigner) SignMessage(_ io.Reader, message []byte, _ crypto.SignerOpts) ([]byte, error) {
+	var err error
+
+	algorithm := s.Algorithm
+
+	if algorithm == "" {
+		if algorithm, err = ForKey(s.pub); err != nil {
+			return nil, err
+		}
+	}
+
+	out, err := s.run(message, "sign", algorithm)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := hex.DecodeString(strings.TrimSpace(string(out)))
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing helper signature: %v", err)
+	}
+
+	return sig, nil
+}
@@ -0,0 +1,104 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Parse a public key in OpenSSH (i.e. authorized_keys line) or PEM
+// (i.e. PKIX "PUBLIC KEY" block) format.
+//
+// Return error if:
+//   - the key encoding is not supported
+//   - the key parsing fails
+func ParsePublicKey(encoded string) (crypto.PublicKey, error) {
+	encoded = strings.TrimSpace(encoded)
+
+	if strings.HasPrefix(encoded, "-----BEGIN") {
+		block, _ := pem.Decode([]byte(encoded))
+
+		if block == nil || block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("invalid PEM public key")
+		}
+
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(encoded))
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenSSH public key: %v", err)
+	}
+
+	cpub, ok := pub.(ssh.CryptoPublicKey)
+
+	if !ok {
+		return nil, fmt.Errorf("unsupported OpenSSH public key type %s", pub.Type())
+	}
+
+	return cpub.CryptoPublicKey(), nil
+}
+
+// Serialize a public key in OpenSSH format (i.e. authorized_keys line,
+// without comment).
+func MarshalPublicKey(pub crypto.PublicKey) (string, error) {
+	sshPub, err := ssh.NewPublicKey(pub)
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))), nil
+}
+
+// Return the SHA-256 fingerprint of a public key, in OpenSSH format
+// (e.g. SHA256:4oGv9XNlFIe5KL/iVagaBVXrx0RBZ6vjv9yHF5hNnLA).
+func Fingerprint(pub crypto.PublicKey) (string, error) {
+	sshPub, err := ssh.NewPublicKey(pub)
+
+	if err != nil {
+		return "", err
+	}
+
+	return ssh.FingerprintSHA256(sshPub), nil
+}
+
+// Parse an unencrypted private key in OpenSSH or PEM (i.e. PKCS #1,
+// PKCS #8 or SEC 1) format.
+//
+// Return error if:
+//   - the key encoding is not supported
+//   - the key parsing fails
+func ParsePrivateKey(encoded []byte) (crypto.Signer, error) {
+	key, err := ssh.ParseRawPrivateKey(encoded)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+
+	// OpenSSH Ed25519 keys are returned by reference
+	if k, ok := key.(*ed25519.PrivateKey); ok {
+		key = *k
+	}
+
+	signer, ok := key.(crypto.Signer)
+
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return signer, nil
+}
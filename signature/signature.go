@@ -0,0 +1,149 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package signature
+
+import (
+	"crypto"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Supported signature algorithms
+const (
+	// Ed25519 (RFC 8032), this is the default algorithm
+	Ed25519 = "ed25519"
+
+	// ECDSA over the NIST P-256 curve, with SHA-256
+	ECDSAP256SHA256 = "ecdsa-p256-sha256"
+
+	// ECDSA over the NIST P-384 curve, with SHA-384
+	ECDSAP384SHA384 = "ecdsa-p384-sha384"
+
+	// RSASSA-PSS (RFC 8017), with SHA-256
+	RSAPSSSHA256 = "rsa-pss-sha256"
+)
+
+// Define a high-level interface for signature algorithms.
+//
+// Signatures are exchanged as raw bytes, their encoding depends by the
+// algorithm.
+type Algorithm interface {
+	// Return true if the public key can be used with the algorithm
+	Supports(pub crypto.PublicKey) bool
+	// Sign a message with the given signer, signers implementing
+	// crypto.MessageSigner are passed the message without pre-hashing
+	Sign(signer crypto.Signer, message []byte) ([]byte, error)
+	// Verify a signature over a message with the given public key
+	Verify(pub crypto.PublicKey, message []byte, signature []byte) error
+}
+
+var (
+	mu         sync.RWMutex
+	algorithms = make(map[string]Algorithm)
+)
+
+// Register a signature algorithm with the given name, registering an
+// algorithm with the name of an existing one replaces it.
+func Register(name string, a Algorithm) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	algorithms[name] = a
+}
+
+// Return the registered signature algorithm, if any, with the given name.
+// The name is optional, the default algorithm (i.e. Ed25519) is returned
+// when empty.
+func Get(name string) (Algorithm, error) {
+	if name == "" {
+		name = Ed25519
+	}
+
+	mu.RLock()
+	a := algorithms[name]
+	mu.RUnlock()
+
+	if a == nil {
+		return nil, fmt.Errorf("signature algorithm %q not registered", name)
+	}
+
+	return a, nil
+}
+
+// Return the names of the registered signature algorithms
+func Algorithms() (names []string) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for name := range algorithms {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return
+}
+
+// Return the name of the first registered signature algorithm, in name
+// order, supporting the given public key. The default algorithm is preferred
+// when it supports the key.
+func ForKey(pub crypto.PublicKey) (string, error) {
+	if a, err := Get(Ed25519); err == nil && a.Supports(pub) {
+		return Ed25519, nil
+	}
+
+	for _, name := range Algorithms() {
+		if a, err := Get(name); err == nil && a.Supports(pub) {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported public key type %T", pub)
+}
+
+// Sign a message with the given signer, using the named signature algorithm.
+//
+// Return error if:
+//   - the signature algorithm is not registered
+//   - the signer public key is not supported by the algorithm
+//   - the signing fails
+func Sign(algorithm string, signer crypto.Signer, message []byte) ([]byte, error) {
+	a, err := Get(algorithm)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.Supports(signer.Public()) {
+		return nil, fmt.Errorf("public key type %T not supported by %q", signer.Public(), algorithm)
+	}
+
+	return a.Sign(signer, message)
+}
+
+// Verify a signature over a message with the given public key, using the
+// named signature algorithm.
+//
+// Return error if:
+//   - the signature algorithm is not registered
+//   - the public key is not supported by the algorithm
+//   - the signature is not valid
+func Verify(algorithm string, pub crypto.PublicKey, message []byte, signature []byte) error {
+	a, err := Get(algorithm)
+
+	if err != nil {
+		return err
+	}
+
+	if !a.Supports(pub) {
+		return fmt.Errorf("public key type %T not supported by %q", pub, algorithm)
+	}
+
+	return a.Verify(pub, message, signature)
+}
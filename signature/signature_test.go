@@ -0,0 +1,204 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+var message = []byte("boot-transparency-statement-v1\x00{}")
+
+func generateKeys(t *testing.T) map[string]crypto.Signer {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]crypto.Signer{
+		Ed25519:         ed25519Key,
+		ECDSAP256SHA256: p256Key,
+		ECDSAP384SHA384: p384Key,
+		RSAPSSSHA256:    rsaKey,
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	for algorithm, signer := range generateKeys(t) {
+		name, err := ForKey(signer.Public())
+		if err != nil || name != algorithm {
+			t.Fatalf("unexpected algorithm for %s key: %s (%v)", algorithm, name, err)
+		}
+
+		sig, err := Sign(algorithm, signer, message)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = Verify(algorithm, signer.Public(), message, sig); err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+
+		// error expected here as the message has been modified
+		if err = Verify(algorithm, signer.Public(), message[1:], sig); err == nil {
+			t.Fatalf("%s: invalid signature has been verified", algorithm)
+		}
+	}
+}
+
+func TestVerifyECDSARaw(t *testing.T) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := Get(ECDSAP256SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, k, a.(*ecdsaAlgorithm).digest(message))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// raw r || s format, as commonly returned by hardware security modules
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	if err = Verify(ECDSAP256SHA256, &k.PublicKey, message, sig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeVerify(t *testing.T) {
+	keys := generateKeys(t)
+
+	sig, err := Sign(ECDSAP256SHA256, keys[ECDSAP256SHA256], message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the key does not match the algorithm curve
+	if err = Verify(ECDSAP384SHA384, keys[ECDSAP256SHA256].Public(), message, sig); err == nil {
+		t.Fatal("signature with unsupported key has been verified")
+	}
+
+	// error expected here as the key does not match the algorithm
+	if err = Verify(Ed25519, keys[ECDSAP256SHA256].Public(), message, sig); err == nil {
+		t.Fatal("signature with unsupported key has been verified")
+	}
+
+	// error expected here as the algorithm is not registered
+	if err = Verify("ml-dsa-65", keys[ECDSAP256SHA256].Public(), message, sig); err == nil {
+		t.Fatal("signature with unregistered algorithm has been verified")
+	}
+
+	// error expected here as the RSA key is too small
+	k, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Sign(RSAPSSSHA256, k, message); err == nil {
+		t.Fatal("signature with weak key has been produced")
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	for algorithm, signer := range generateKeys(t) {
+		// OpenSSH format
+		encoded, err := MarshalPublicKey(signer.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pub, err := ParsePublicKey(encoded)
+		if err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+
+		if !pub.(interface{ Equal(crypto.PublicKey) bool }).Equal(signer.Public()) {
+			t.Fatalf("%s: public key mismatch", algorithm)
+		}
+
+		// PEM format
+		der, err := x509.MarshalPKIXPublicKey(signer.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		encoded = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+		if pub, err = ParsePublicKey(encoded); err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+
+		if !pub.(interface{ Equal(crypto.PublicKey) bool }).Equal(signer.Public()) {
+			t.Fatalf("%s: public key mismatch", algorithm)
+		}
+	}
+
+	// error expected here as the key is not valid
+	for _, encoded := range []string{"ssh-ed25519 invalid", "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----"} {
+		if _, err := ParsePublicKey(encoded); err == nil {
+			t.Fatalf("invalid public key has been parsed: %s", encoded)
+		}
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	for algorithm, signer := range generateKeys(t) {
+		der, err := x509.MarshalPKCS8PrivateKey(signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		k, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+		if err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+
+		sig, err := Sign(algorithm, k, message)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = Verify(algorithm, signer.Public(), message, sig); err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+	}
+
+	// error expected here as the key is not valid
+	if _, err := ParsePrivateKey([]byte("invalid")); err == nil {
+		t.Fatal("invalid private key has been parsed")
+	}
+}
@@ -0,0 +1,184 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package signature
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestAgentSigner(t *testing.T) {
+	keyring := agent.NewKeyring()
+
+	// the agent is served in-process, over the SSH agent protocol
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	go func() {
+		_ = agent.ServeAgent(keyring, c2)
+	}()
+
+	client := agent.NewClient(c1)
+
+	for algorithm, k := range generateKeys(t) {
+		if err := keyring.Add(agent.AddedKey{PrivateKey: k}); err != nil {
+			t.Fatal(err)
+		}
+
+		signer, err := NewAgentSigner(client, k.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sig, err := Sign(algorithm, signer, message)
+
+		// error expected here as RSA-PSS is not supported by SSH agents
+		if algorithm == RSAPSSSHA256 {
+			if err == nil {
+				t.Fatal("RSA-PSS signature has been produced by SSH agent")
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+
+		if err = Verify(algorithm, k.Public(), message, sig); err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+	}
+}
+
+func TestNegativeAgentSigner(t *testing.T) {
+	keys := generateKeys(t)
+
+	// error expected here as the key is not held by the agent
+	if _, err := NewAgentSigner(agent.NewKeyring(), keys[Ed25519].Public()); err == nil {
+		t.Fatal("signer for missing SSH agent key has been returned")
+	}
+
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	// error expected here as no agent is available
+	if _, _, err := DialAgent(); err == nil {
+		t.Fatal("SSH agent connection without socket succeeded")
+	}
+}
+
+// The test binary acts as external signing helper when the
+// BT_TEST_SIGNING_KEY environment variable is set.
+func TestMain(m *testing.M) {
+	if k := os.Getenv("BT_TEST_SIGNING_KEY"); k != "" {
+		os.Exit(signingHelper(k, os.Args[len(os.Args)-2:]))
+	}
+
+	os.Exit(m.Run())
+}
+
+func signingHelper(encoded string, args []string) int {
+	signer, err := ParsePrivateKey([]byte(encoded))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch {
+	case args[1] == "public-key":
+		pub, err := MarshalPublicKey(signer.Public())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		fmt.Println(pub)
+	case args[0] == "sign":
+		msg, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		sig, err := Sign(args[1], signer, msg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		fmt.Println(hex.EncodeToString(sig))
+	default:
+		return 2
+	}
+
+	return 0
+}
+
+func helperSigner(t *testing.T, k crypto.Signer) (*ExternalSigner, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("BT_TEST_SIGNING_KEY", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+
+	return NewExternalSigner(os.Args[0], "-test.run=^$")
+}
+
+func TestExternalSigner(t *testing.T) {
+	for algorithm, k := range generateKeys(t) {
+		signer, err := helperSigner(t, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signer.Algorithm = algorithm
+
+		sig, err := Sign(algorithm, signer, message)
+		if err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+
+		if err = Verify(algorithm, k.Public(), message, sig); err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+	}
+}
+
+func TestNegativeExternalSigner(t *testing.T) {
+	keys := generateKeys(t)
+
+	signer, err := helperSigner(t, keys[ECDSAP256SHA256])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the helper fails signing with an algorithm
+	// not matching its key
+	signer.Algorithm = ECDSAP384SHA384
+
+	if _, err = Sign(ECDSAP256SHA256, signer, message); err == nil {
+		t.Fatal("signature with invalid algorithm has been produced")
+	}
+
+	// error expected here as the helper fails
+	t.Setenv("BT_TEST_SIGNING_KEY", "invalid")
+
+	if _, err = NewExternalSigner(os.Args[0], "-test.run=^$"); err == nil {
+		t.Fatal("signer for failing helper has been returned")
+	}
+}
@@ -0,0 +1,303 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Serialize JSON data in its canonical form, as defined by RFC 8785 (JSON
+// Canonicalization Scheme): object members are sorted by their UTF-16 key
+// code units, whitespaces are removed and strings and numbers are
+// serialized as defined by ECMAScript.
+//
+// The canonical form is used for signing the statement artifacts and for
+// hashing the statement logged by the transparency engines, so that it can
+// be reproduced by any tooling regardless of the formatting of the data.
+//
+// Return error if:
+//   - the JSON parsing fails
+//   - the data is not valid UTF-8
+//   - an object includes duplicate keys
+//   - a number cannot be represented as IEEE 754 double precision value
+func Canonicalize(jsonData []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if !utf8.Valid(jsonData) {
+		return nil, fmt.Errorf("invalid JSON: not valid UTF-8")
+	}
+
+	d := json.NewDecoder(bytes.NewReader(jsonData))
+	d.UseNumber()
+
+	if err := canonicalize(d, &buf); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	if _, err := d.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after top-level value")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Return the canonical form of the given JSON data and, when different,
+// its compact form (i.e. json.Marshal()) which was hashed, or signed, by
+// earlier releases. The canonical form is always the first one.
+func CanonicalForms(jsonData []byte) (forms [][]byte, err error) {
+	canonical, err := Canonicalize(jsonData)
+
+	if err != nil {
+		return
+	}
+
+	compact, err := json.Marshal(json.RawMessage(jsonData))
+
+	if err != nil {
+		return
+	}
+
+	forms = append(forms, canonical)
+
+	if !bytes.Equal(canonical, compact) {
+		forms = append(forms, compact)
+	}
+
+	return
+}
+
+type member struct {
+	key   string
+	value []byte
+}
+
+func canonicalize(d *json.Decoder, buf *bytes.Buffer) (err error) {
+	t, err := d.Token()
+
+	if err != nil {
+		return
+	}
+
+	switch v := t.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			return canonicalizeObject(d, buf)
+		case '[':
+			return canonicalizeArray(d, buf)
+		default:
+			return fmt.Errorf("unexpected delimiter %q", v)
+		}
+	case string:
+		writeString(buf, v)
+	case json.Number:
+		var n string
+
+		if n, err = formatNumber(v); err != nil {
+			return
+		}
+
+		buf.WriteString(n)
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case nil:
+		buf.WriteString("null")
+	default:
+		return fmt.Errorf("unexpected token %v", t)
+	}
+
+	return
+}
+
+func canonicalizeObject(d *json.Decoder, buf *bytes.Buffer) (err error) {
+	var members []member
+
+	keys := make(map[string]bool)
+
+	for d.More() {
+		var t json.Token
+		var value bytes.Buffer
+
+		if t, err = d.Token(); err != nil {
+			return
+		}
+
+		key, ok := t.(string)
+
+		if !ok {
+			return fmt.Errorf("invalid object key %v", t)
+		}
+
+		if keys[key] {
+			return fmt.Errorf("duplicate object key %q", key)
+		}
+
+		keys[key] = true
+
+		if err = canonicalize(d, &value); err != nil {
+			return
+		}
+
+		members = append(members, member{key, value.Bytes()})
+	}
+
+	// consume the closing delimiter
+	if _, err = d.Token(); err != nil {
+		return
+	}
+
+	// object members are sorted by comparing their keys as arrays of
+	// UTF-16 code units
+	slices.SortFunc(members, func(a, b member) int {
+		return slices.Compare(utf16.Encode([]rune(a.key)), utf16.Encode([]rune(b.key)))
+	})
+
+	buf.WriteByte('{')
+
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		writeString(buf, m.key)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+
+	buf.WriteByte('}')
+
+	return
+}
+
+func canonicalizeArray(d *json.Decoder, buf *bytes.Buffer) (err error) {
+	buf.WriteByte('[')
+
+	for i := 0; d.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		if err = canonicalize(d, buf); err != nil {
+			return
+		}
+	}
+
+	// consume the closing delimiter
+	if _, err = d.Token(); err != nil {
+		return
+	}
+
+	buf.WriteByte(']')
+
+	return
+}
+
+// serialize a string as defined by ECMAScript JSON.stringify()
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+
+	buf.WriteByte('"')
+}
+
+// serialize a number as defined by ECMAScript Number.prototype.toString()
+func formatNumber(n json.Number) (string, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return "", err
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("number %s out of range", n)
+	}
+
+	// this also covers negative zero
+	if f == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// shortest decimal digits uniquely identifying the number, with the
+	// exponent of the first digit
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	i := strings.IndexByte(s, 'e')
+	digits := strings.Replace(s[:i], ".", "", 1)
+	exp, err := strconv.Atoi(s[i+1:])
+
+	if err != nil {
+		return "", err
+	}
+
+	// position of the decimal point relative to the digits
+	k := len(digits)
+	p := exp + 1
+
+	switch {
+	case k <= p && p <= 21:
+		s = digits + strings.Repeat("0", p-k)
+	case 0 < p && p <= 21:
+		s = digits[:p] + "." + digits[p:]
+	case -6 < p && p <= 0:
+		s = "0." + strings.Repeat("0", -p) + digits
+	default:
+		s = digits[:1]
+
+		if k > 1 {
+			s += "." + digits[1:]
+		}
+
+		if p-1 < 0 {
+			s += "e-" + strconv.Itoa(1-p)
+		} else {
+			s += "e+" + strconv.Itoa(p-1)
+		}
+	}
+
+	return sign + s, nil
+}
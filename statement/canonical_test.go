@@ -0,0 +1,143 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// RFC 8785 test vectors
+func TestCanonicalize(t *testing.T) {
+	for _, v := range []struct {
+		input    string
+		expected string
+	}{
+		// RFC 8785 Section 3.2.2
+		{
+			`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		// RFC 8785 Section 3.2.3
+		{
+			`{
+  "\u20ac": "Euro Sign",
+  "\r": "Carriage Return",
+  "\ufb33": "Hebrew Letter Dalet With Dagesh",
+  "1": "One",
+  "\ud83d\ude00": "Emoji: Grinning Face",
+  "\u0080": "Control",
+  "\u00f6": "Latin Small Letter O With Diaeresis"
+}`,
+			"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		// nested structures
+		{
+			`[56, {"d": true, "10": null, "1": [ ]}, {"a": {"b": "<&>", "a": "\u2028"}}]`,
+			"[56,{\"1\":[],\"10\":null,\"d\":true},{\"a\":{\"a\":\"\u2028\",\"b\":\"<&>\"}}]",
+		},
+	} {
+		c, err := Canonicalize([]byte(v.input))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(c) != v.expected {
+			t.Fatalf("unexpected canonical form:\n%s\nexpected:\n%s", c, v.expected)
+		}
+	}
+}
+
+// RFC 8785 Appendix B test vectors
+func TestCanonicalizeNumbers(t *testing.T) {
+	for _, v := range []struct {
+		bits     uint64
+		expected string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	} {
+		f := math.Float64frombits(v.bits)
+		n := json.Number(formatFloat(f))
+
+		s, err := formatNumber(n)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if s != v.expected {
+			t.Fatalf("unexpected serialization for %016x: %s, expected %s", v.bits, s, v.expected)
+		}
+	}
+}
+
+func formatFloat(f float64) string {
+	b, _ := json.Marshal(f)
+	return string(b)
+}
+
+func TestNegativeCanonicalize(t *testing.T) {
+	for _, input := range []string{
+		`{"a": 1, "a": 2}`,
+		`{"a": 1`,
+		`[1e400]`,
+		`{"a": 1} {}`,
+		"\"\xff\"",
+	} {
+		// error expected here as the input is not valid I-JSON
+		if _, err := Canonicalize([]byte(input)); err == nil {
+			t.Fatalf("invalid JSON has been canonicalized: %s", input)
+		}
+	}
+}
+
+func TestCanonicalForms(t *testing.T) {
+	forms, err := CanonicalForms([]byte(`{"b": "<", "a": 1}`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(forms) != 2 || string(forms[0]) != `{"a":1,"b":"<"}` || string(forms[1]) != `{"b":"\u003c","a":1}` {
+		t.Fatalf("unexpected forms: %q", forms)
+	}
+
+	// the compact form is omitted when matching the canonical one
+	if forms, err = CanonicalForms([]byte(`{ "a": 1 }`)); err != nil || len(forms) != 1 {
+		t.Fatalf("unexpected forms: %q (%v)", forms, err)
+	}
+}
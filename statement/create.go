@@ -0,0 +1,110 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// Define an artifact file to be claimed by a statement
+type ArtifactFile struct {
+	// artifact category (e.g. 1: LinuxKernel, 2: Initrd, 3: Dtb, ...)
+	Category uint
+
+	// file path, the path relative to the file system root is claimed as
+	// artifact file name
+	Path string
+}
+
+// Return an artifact claiming a given file, the SHA-512 hash, file name and
+// size are claimed along with any claim (e.g. version, architecture)
+// extracted by the artifact handler for the given category (see
+// artifact.Extractor).
+//
+// Return error if:
+//   - the artifact handler is not registered
+//   - the artifact handler does not support claims extraction
+//   - the claims extraction fails
+func NewArtifact(category uint, name string, data []byte) (*Artifact, error) {
+	hash, err := Hash(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	f := &artifact.File{
+		Name: name,
+		Data: data,
+		Hash: hash,
+	}
+
+	claims, err := artifact.ExtractClaims(category, f)
+
+	if err != nil {
+		return nil, err
+	}
+
+	jsonClaims, err := json.Marshal(claims)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// validate the extracted claims
+	h, err := artifact.GetHandler(category)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = h.ParseClaims(jsonClaims); err != nil {
+		return nil, fmt.Errorf("invalid extracted claims: %w", err)
+	}
+
+	return &Artifact{Category: category, Claims: jsonClaims}, nil
+}
+
+// Create a statement claiming the given artifact files, read from the file
+// system fsys (e.g. os.DirFS("/boot")), so that the resulting statement can
+// be verified against the same file system with VerifyFiles().
+//
+// The statement description and version are left to the caller, before
+// signing.
+//
+// Return error if:
+//   - an artifact file cannot be read
+//   - the artifact creation fails (see NewArtifact())
+func Create(fsys fs.FS, files []ArtifactFile) (*Statement, error) {
+	s := &Statement{}
+
+	for _, f := range files {
+		name := strings.TrimPrefix(path.Clean(f.Path), "/")
+		data, err := fs.ReadFile(fsys, name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		a, err := NewArtifact(f.Category, name, data)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		s.Artifacts = append(s.Artifacts, *a)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,83 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+func TestCreate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"boot/vmlinuz":    {Data: kernel},
+		"boot/initrd.img": {Data: initrd},
+	}
+
+	s, err := Create(fsys, []ArtifactFile{
+		{Category: artifact.LinuxKernel, Path: "/boot/vmlinuz"},
+		{Category: artifact.Initrd, Path: "boot/initrd.img"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Description = "Linux bundle"
+	s.Version = "v1"
+
+	var claims struct {
+		FileName string `json:"file_name"`
+		Size     uint64 `json:"size"`
+	}
+
+	if err = json.Unmarshal(s.Artifacts[0].Claims, &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.FileName != "boot/vmlinuz" || claims.Size != uint64(len(kernel)) {
+		t.Fatalf("unexpected claims: %s", s.Artifacts[0].Claims)
+	}
+
+	// the created statement must be valid, and verifiable against the
+	// same files
+	jsonStatement, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err = Parse(jsonStatement); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.VerifyFiles(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != 2 || !res[0].Matched || !res[1].Matched {
+		t.Fatalf("unexpected results: %+v", res)
+	}
+}
+
+func TestNegativeCreate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"vmlinuz": {Data: kernel},
+	}
+
+	// error expected here as the file does not exist
+	if _, err := Create(fsys, []ArtifactFile{{Category: artifact.LinuxKernel, Path: "initrd.img"}}); err == nil {
+		t.Fatal("statement for missing file has been created")
+	}
+
+	// error expected here as the category does not support claims extraction
+	if _, err := Create(fsys, []ArtifactFile{{Category: artifact.UEFIBIOS, Path: "vmlinuz"}}); err == nil {
+		t.Fatal("statement for unsupported category has been created")
+	}
+}
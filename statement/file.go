@@ -0,0 +1,234 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+var (
+	// ErrMissingHash is returned when an artifact claims a file name
+	// without claiming its hash.
+	ErrMissingHash = errors.New("the artifact does not claim any hash")
+
+	// ErrMissingFileName is returned when an artifact does not claim any
+	// file name, therefore it cannot be measured from a file system.
+	ErrMissingFileName = errors.New("the artifact does not claim any file name")
+
+	// ErrHashMismatch is returned when the hash of an artifact does not
+	// match the claimed one.
+	ErrHashMismatch = errors.New("the artifact hash does not match the claimed one")
+
+	// ErrMissingArtifact is returned when the statement does not include
+	// any artifact matching a measured one.
+	ErrMissingArtifact = errors.New("the statement does not include the artifact")
+)
+
+// Define the outcome of the measurement of a statement artifact.
+type FileResult struct {
+	// index of the artifact in the statement
+	Artifact int `json:"artifact"`
+
+	// artifact category
+	Category uint `json:"category"`
+
+	// claimed file name
+	FileName string `json:"file_name,omitempty"`
+
+	// measured SHA-512 hash, in hex format, empty if the artifact could
+	// not be measured
+	Hash string `json:"hash,omitempty"`
+
+	// true if the measured hash matches the claimed one
+	Matched bool `json:"matched"`
+
+	// failure reason
+	Err error `json:"-"`
+}
+
+// claims shared by all artifact categories representing a file
+type fileClaims struct {
+	FileName string `json:"file_name"`
+	Hash     string `json:"hash"`
+}
+
+// Compute the SHA-512 hash, in hex format, of the data read from r
+func Hash(r io.Reader) (string, error) {
+	h := sha512.New()
+
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (a *Artifact) fileClaims() (c fileClaims, err error) {
+	err = json.Unmarshal(a.Claims, &c)
+	return
+}
+
+// compare the measured hash against the claimed one
+func checkHash(claimHash string, hash string) error {
+	if claimHash == "" {
+		return ErrMissingHash
+	}
+
+	if err := artifact.CheckHash(claimHash, hash); err != nil {
+		return fmt.Errorf("%w (%v)", ErrHashMismatch, err)
+	}
+
+	return nil
+}
+
+func measureFile(fsys fs.FS, name string) (hash string, err error) {
+	f, err := fsys.Open(name)
+
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	return Hash(f)
+}
+
+// Verify that the artifacts included in the statement are matching the
+// files present in the given file system. Each artifact claiming a file
+// name is streamed from fsys (e.g. os.DirFS("/boot")) to verify that its
+// SHA-512 hash matches the claimed one.
+//
+// Artifacts of the unmeasured categories (e.g. artifact.UEFIBIOS), which
+// are not represented by a file, are neither measured nor reported, any
+// other artifact not claiming a file name is reported as not matched.
+//
+// The outcome of each measured artifact is reported, even when an error
+// is returned.
+//
+// Return error if:
+//   - the claims parsing fails
+//   - an artifact, not of the unmeasured categories, does not claim a file name
+//   - an artifact claims a file name, but not its hash
+//   - a claimed file cannot be read from fsys
+//   - a claimed hash does not match the file one
+func (s *Statement) VerifyFiles(fsys fs.FS, unmeasured ...uint) (results []FileResult, err error) {
+	for i, a := range s.Artifacts {
+		if slices.Contains(unmeasured, a.Category) {
+			continue
+		}
+
+		c, parseErr := a.fileClaims()
+
+		if parseErr != nil {
+			return results, fmt.Errorf("invalid claims for artifact %d: %v", i, parseErr)
+		}
+
+		r := FileResult{
+			Artifact: i,
+			Category: a.Category,
+			FileName: c.FileName,
+		}
+
+		if c.FileName == "" {
+			r.Err = ErrMissingFileName
+
+			if err == nil {
+				err = fmt.Errorf("artifact %d: %w", i, r.Err)
+			}
+
+			results = append(results, r)
+			continue
+		}
+
+		// file names are claimed as base names or, in any case, relative
+		// to the root of the boot file system
+		name := strings.TrimPrefix(path.Clean(c.FileName), "/")
+
+		if r.Hash, r.Err = measureFile(fsys, name); r.Err == nil {
+			r.Err = checkHash(c.Hash, r.Hash)
+		}
+
+		r.Matched = r.Err == nil
+
+		if r.Err != nil && err == nil {
+			err = fmt.Errorf("artifact %d (%s): %w", i, c.FileName, r.Err)
+		}
+
+		results = append(results, r)
+	}
+
+	return
+}
+
+// Verify that the artifacts read from the given readers, indexed by
+// artifact category (e.g. artifact.LinuxKernel), are included in the
+// statement. Each reader is streamed to compute its SHA-512 hash which
+// must match the claimed hash of at least one statement artifact of the
+// same category.
+//
+// The outcome of each statement artifact of the given categories is
+// reported, even when an error is returned.
+//
+// Return error if:
+//   - the claims parsing fails
+//   - a reader fails
+//   - the statement does not include any artifact, for a given category,
+//     matching the hash of the corresponding reader
+func (s *Statement) VerifyReaders(readers map[uint]io.Reader) (results []FileResult, err error) {
+	for _, category := range slices.Sorted(maps.Keys(readers)) {
+		hash, readErr := Hash(readers[category])
+
+		if readErr != nil {
+			return results, fmt.Errorf("cannot measure artifact category %d: %v", category, readErr)
+		}
+
+		matched := false
+
+		for i, a := range s.Artifacts {
+			if a.Category != category {
+				continue
+			}
+
+			c, parseErr := a.fileClaims()
+
+			if parseErr != nil {
+				return results, fmt.Errorf("invalid claims for artifact %d: %v", i, parseErr)
+			}
+
+			r := FileResult{
+				Artifact: i,
+				Category: category,
+				FileName: c.FileName,
+				Hash:     hash,
+				Err:      checkHash(c.Hash, hash),
+			}
+
+			r.Matched = r.Err == nil
+			matched = matched || r.Matched
+
+			results = append(results, r)
+		}
+
+		if !matched && err == nil {
+			err = fmt.Errorf("%w (category %d, hash %s)", ErrMissingArtifact, category, hash)
+		}
+	}
+
+	return
+}
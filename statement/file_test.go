@@ -0,0 +1,181 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"testing/fstest"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+	_ "github.com/usbarmory/boot-transparency/artifact/initrd"
+	_ "github.com/usbarmory/boot-transparency/artifact/linux_kernel"
+	_ "github.com/usbarmory/boot-transparency/artifact/uefi_bios"
+)
+
+var kernel = []byte("linux kernel image")
+var initrd = []byte("initial ramdisk")
+
+func newStatement(t *testing.T) *Statement {
+	kernelHash, _ := Hash(bytes.NewReader(kernel))
+	initrdHash, _ := Hash(bytes.NewReader(initrd))
+
+	s, err := Parse([]byte(fmt.Sprintf(`{
+    "artifacts": [
+        {
+            "category": 1,
+            "claims": {
+                "file_name": "vmlinuz-6.14.0-29-generic",
+                "hash": "%s"
+            }
+        },
+        {
+            "category": 2,
+            "claims": {
+                "file_name": "/initrd.img-6.14.0-29-generic",
+                "hash": "%s"
+            }
+        },
+        {
+            "category": %d,
+            "claims": {
+                "firmware_vendor": "Example Inc."
+            }
+        }
+    ]
+}`, kernelHash, initrdHash, artifact.UEFIBIOS)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestVerifyFiles(t *testing.T) {
+	s := newStatement(t)
+
+	fsys := fstest.MapFS{
+		"vmlinuz-6.14.0-29-generic":    {Data: kernel},
+		"initrd.img-6.14.0-29-generic": {Data: initrd},
+	}
+
+	res, err := s.VerifyFiles(fsys, artifact.UEFIBIOS)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the UEFI BIOS artifact is not measured
+	if len(res) != 2 || !res[0].Matched || !res[1].Matched || res[1].Artifact != 1 {
+		t.Fatalf("unexpected results: %+v", res)
+	}
+}
+
+func TestNegativeVerifyFiles(t *testing.T) {
+	s := newStatement(t)
+
+	fsys := fstest.MapFS{
+		"vmlinuz-6.14.0-29-generic":    {Data: kernel},
+		"initrd.img-6.14.0-29-generic": {Data: kernel},
+	}
+
+	// error expected here as the initrd has been replaced
+	res, err := s.VerifyFiles(fsys, artifact.UEFIBIOS)
+
+	if !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(res) != 2 || !res[0].Matched || res[1].Matched {
+		t.Fatalf("unexpected results: %+v", res)
+	}
+
+	delete(fsys, "initrd.img-6.14.0-29-generic")
+
+	// error expected here as the initrd is missing
+	if _, err = s.VerifyFiles(fsys, artifact.UEFIBIOS); err == nil {
+		t.Fatal("missing artifact has been verified")
+	}
+}
+
+func TestNegativeVerifyFilesMissingFileName(t *testing.T) {
+	kernelHash, _ := Hash(bytes.NewReader(kernel))
+
+	s, err := Parse([]byte(fmt.Sprintf(`{
+    "artifacts": [
+        {
+            "category": 1,
+            "claims": {
+                "hash": "%s"
+            }
+        }
+    ]
+}`, kernelHash)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the kernel cannot be measured
+	res, err := s.VerifyFiles(fstest.MapFS{})
+
+	if !errors.Is(err, ErrMissingFileName) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(res) != 1 || res[0].Matched || !errors.Is(res[0].Err, ErrMissingFileName) {
+		t.Fatalf("unexpected results: %+v", res)
+	}
+
+	// error expected here as the UEFI BIOS artifact is not reported as
+	// unmeasured when not requested
+	if _, err = newStatement(t).VerifyFiles(fstest.MapFS{
+		"vmlinuz-6.14.0-29-generic":    {Data: kernel},
+		"initrd.img-6.14.0-29-generic": {Data: initrd},
+	}); !errors.Is(err, ErrMissingFileName) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestVerifyReaders(t *testing.T) {
+	s := newStatement(t)
+
+	res, err := s.VerifyReaders(map[uint]io.Reader{
+		artifact.LinuxKernel: bytes.NewReader(kernel),
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != 1 || !res[0].Matched || res[0].FileName != "vmlinuz-6.14.0-29-generic" {
+		t.Fatalf("unexpected results: %+v", res)
+	}
+}
+
+func TestNegativeVerifyReaders(t *testing.T) {
+	s := newStatement(t)
+
+	// error expected here as the initrd is not the logged one
+	res, err := s.VerifyReaders(map[uint]io.Reader{
+		artifact.LinuxKernel: bytes.NewReader(kernel),
+		artifact.Initrd:      bytes.NewReader(kernel),
+	})
+
+	if !errors.Is(err, ErrMissingArtifact) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(res) != 2 || !res[0].Matched || res[1].Matched {
+		t.Fatalf("unexpected results: %+v", res)
+	}
+}
@@ -0,0 +1,106 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+// Statement kinds
+const (
+	// boot bundle release, claiming its artifacts
+	KindBundle = ""
+	// revocation, or supersession, of earlier statements
+	KindRevocation = "revocation"
+)
+
+// Revocation statuses
+const (
+	// the statement must not be trusted anymore (e.g. vulnerable bundle)
+	Revoked = "revoked"
+	// the statement has been replaced by a newer one
+	Superseded = "superseded"
+)
+
+// Define the revocation of an earlier statement
+type Revocation struct {
+	// digest of the revoked statement (see Statement.Digest())
+	Digest string `json:"digest" validate:"required,hash"`
+
+	// revocation status (i.e. Revoked or Superseded)
+	Status string `json:"status" validate:"required"`
+
+	// digest of the superseding statement, if any
+	SupersededBy string `json:"superseded_by,omitempty" validate:"hash"`
+
+	// human-readable revocation reason (e.g. CVE-2025-38236)
+	Reason string `json:"reason,omitempty"`
+}
+
+// Return the statement digest, that is the SHA-512 hash, in hex format, of
+// its signing payload (see SigningPayload()). The digest identifies the
+// statement content regardless of its signatures.
+func (s *Statement) Digest() (string, error) {
+	payload, err := s.SigningPayload()
+
+	if err != nil {
+		return "", err
+	}
+
+	h := sha512.Sum512(payload)
+
+	return hex.EncodeToString(h[:]), nil
+}
+
+// Return the revocation, by a revocation statement, of the statement with
+// a given digest, nil if the statement is not revoked.
+func (s *Statement) Revocation(digest string) *Revocation {
+	if s.Kind != KindRevocation {
+		return nil
+	}
+
+	for i, r := range s.Revokes {
+		if r.Digest == digest {
+			return &s.Revokes[i]
+		}
+	}
+
+	return nil
+}
+
+// check consistency between the statement kind and its content
+func (s *Statement) checkKind() error {
+	switch s.Kind {
+	case KindBundle:
+		if len(s.Revokes) > 0 {
+			return artifact.AtPath("revokes", fmt.Errorf("%w: revocations are only allowed in %s statements", artifact.ErrInvalidFormat, KindRevocation))
+		}
+	case KindRevocation:
+		if len(s.Revokes) == 0 {
+			return artifact.AtPath("revokes", artifact.ErrMissingField)
+		}
+
+		if len(s.Artifacts) > 0 {
+			return artifact.AtPath("artifacts", fmt.Errorf("%w: artifacts are not allowed in %s statements", artifact.ErrInvalidFormat, KindRevocation))
+		}
+
+		for i, r := range s.Revokes {
+			if r.Status != Revoked && r.Status != Superseded {
+				return artifact.AtPath(fmt.Sprintf("revokes[%d].status", i), fmt.Errorf("%w: %s or %s expected", artifact.ErrInvalidFormat, Revoked, Superseded))
+			}
+		}
+	default:
+		return artifact.AtPath("kind", fmt.Errorf("%w: unsupported statement kind %q", artifact.ErrInvalidFormat, s.Kind))
+	}
+
+	return nil
+}
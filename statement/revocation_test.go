@@ -0,0 +1,125 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+func TestRevocation(t *testing.T) {
+	s, err := Parse(signedStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest, err := s.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the digest does not depend on the statement signatures
+	s.Signatures = append(s.Signatures, Signature{PubKey: "ssh-ed25519 AAAA", Signature: "00"})
+
+	if d, err := s.Digest(); err != nil || d != digest {
+		t.Fatalf("unexpected digest %s: %v", d, err)
+	}
+
+	r := &Statement{
+		Kind: KindRevocation,
+		Revokes: []Revocation{
+			{
+				Digest: digest,
+				Status: Revoked,
+				Reason: "CVE-2025-38236",
+			},
+		},
+	}
+
+	jsonRevocation, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, err = Parse(jsonRevocation); err != nil {
+		t.Fatal(err)
+	}
+
+	if rr := r.Revocation(digest); rr == nil || rr.Status != Revoked {
+		t.Fatalf("unexpected revocation: %+v", rr)
+	}
+
+	if rr := s.Revocation(digest); rr != nil {
+		t.Fatalf("unexpected revocation by bundle statement: %+v", rr)
+	}
+
+	rd, err := r.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the statement kind is bound by the signatures
+	r.Kind = KindBundle
+
+	if d, err := r.Digest(); err != nil || d == rd {
+		t.Fatalf("statement kind change did not alter the digest: %v", err)
+	}
+}
+
+func TestNegativeParseRevocation(t *testing.T) {
+	const digest = "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59"
+
+	for _, v := range []struct {
+		statement string
+		path      string
+		err       error
+	}{
+		{
+			`{"kind": "revocaton", "artifacts": []}`,
+			"kind",
+			artifact.ErrInvalidFormat,
+		},
+		{
+			`{"kind": "revocation", "artifacts": []}`,
+			"revokes",
+			artifact.ErrMissingField,
+		},
+		{
+			`{"kind": "revocation", "revokes": [{"digest": "` + digest + `", "status": "expired"}], "artifacts": []}`,
+			"revokes[0].status",
+			artifact.ErrInvalidFormat,
+		},
+		{
+			`{"kind": "revocation", "revokes": [{"digest": "8ba6bc3d", "status": "revoked"}], "artifacts": []}`,
+			"revokes[0].digest",
+			artifact.ErrInvalidFormat,
+		},
+		{
+			`{"kind": "revocation", "revokes": [{"digest": "` + digest + `", "status": "revoked"}], "artifacts": [{"category": 1, "claims": {"hash": "` + digest + `"}}]}`,
+			"artifacts",
+			artifact.ErrInvalidFormat,
+		},
+		{
+			`{"revokes": [{"digest": "` + digest + `", "status": "revoked"}], "artifacts": []}`,
+			"revokes",
+			artifact.ErrInvalidFormat,
+		},
+	} {
+		var fe *artifact.FieldError
+
+		// error expected here as the statement is not valid
+		_, err := Parse([]byte(v.statement))
+
+		if !errors.As(err, &fe) || fe.Path != v.path || !errors.Is(err, v.err) {
+			t.Fatalf("unexpected error for %s: %v", v.statement, err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/usbarmory/boot-transparency/signature"
+)
+
+// ErrContentMismatch is returned when merging signatures of statements
+// that do not claim the same content.
+var ErrContentMismatch = errors.New("the statements do not claim the same content")
+
+// Return true if two encoded public keys (see signature.ParsePublicKey())
+// represent the same key, regardless of their encoding.
+func samePublicKey(a string, b string) bool {
+	if a == b {
+		return true
+	}
+
+	pa, err := signature.ParsePublicKey(a)
+
+	if err != nil {
+		return false
+	}
+
+	pb, err := signature.ParsePublicKey(b)
+
+	if err != nil {
+		return false
+	}
+
+	k, ok := pa.(interface{ Equal(crypto.PublicKey) bool })
+
+	return ok && k.Equal(pb)
+}
+
+// Verify a statement signature with the given public key, any signature
+// envelope supported by SignedPayloads() is accepted, including legacy ones.
+//
+// Return error if:
+//   - the signature envelope is not supported
+//   - the signature encoding is not valid
+//   - the signature is not valid
+func (s *Statement) VerifySignature(sig *Signature, pub crypto.PublicKey) error {
+	payloads, err := s.SignedPayloads(sig)
+
+	if err != nil {
+		return err
+	}
+
+	rawSig, err := hex.DecodeString(sig.Signature)
+
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %v", err)
+	}
+
+	for _, payload := range payloads {
+		if err = signature.Verify(sig.Algorithm, pub, payload, rawSig); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// Return the index of the statement signature by a given public key, in
+// OpenSSH or PEM format, or -1 if not present.
+func (s *Statement) SignatureIndex(pubKey string) int {
+	for i, sig := range s.Signatures {
+		if samePublicKey(sig.PubKey, pubKey) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Add a signature to the statement, signatures are de-duplicated by public
+// key therefore a signature by a key that already signed the statement
+// replaces the existing one.
+//
+// Return true if an existing signature has been replaced.
+func (s *Statement) AddSignature(sig Signature) (replaced bool) {
+	if i := s.SignatureIndex(sig.PubKey); i >= 0 {
+		s.Signatures[i] = sig
+		return true
+	}
+
+	s.Signatures = append(s.Signatures, sig)
+
+	return false
+}
+
+// Remove the signature by a given public key, in OpenSSH or PEM format.
+//
+// Return false if the statement is not signed by the key.
+func (s *Statement) RemoveSignature(pubKey string) bool {
+	i := s.SignatureIndex(pubKey)
+
+	if i < 0 {
+		return false
+	}
+
+	s.Signatures = append(s.Signatures[:i], s.Signatures[i+1:]...)
+
+	return true
+}
+
+// Merge the signatures of another copy of the statement, signatures by keys
+// that already signed the statement are ignored.
+//
+// Return the number of merged signatures, or error if:
+//   - the statements description, version or artifacts do not match
+//     (ErrContentMismatch)
+func (s *Statement) Merge(other *Statement) (merged int, err error) {
+	payload, err := s.SigningPayload()
+
+	if err != nil {
+		return
+	}
+
+	otherPayload, err := other.SigningPayload()
+
+	if err != nil {
+		return
+	}
+
+	if !bytes.Equal(payload, otherPayload) {
+		return 0, ErrContentMismatch
+	}
+
+	for _, sig := range other.Signatures {
+		if s.SignatureIndex(sig.PubKey) >= 0 {
+			continue
+		}
+
+		s.Signatures = append(s.Signatures, sig)
+		merged += 1
+	}
+
+	return
+}
@@ -0,0 +1,168 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/usbarmory/boot-transparency/signature"
+)
+
+func sign(t *testing.T, s *Statement, signer crypto.Signer) Signature {
+	algorithm, err := signature.ForKey(signer.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey, err := signature.MarshalPublicKey(signer.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := signature.Sign(algorithm, signer, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Signature{
+		PubKey:    pubKey,
+		Signature: hex.EncodeToString(sig),
+		Algorithm: algorithm,
+		Envelope:  SignatureNamespace,
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	_, k1, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k2, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Parse(signedStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig1 := sign(t, s, k1)
+	sig2 := sign(t, s, k2)
+
+	if err = s.VerifySignature(&sig1, k1.Public()); err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the signature is verified with another key
+	if err = s.VerifySignature(&sig1, k2.Public()); err == nil {
+		t.Fatal("signature has been verified with another key")
+	}
+
+	if s.AddSignature(sig1) || s.AddSignature(sig2) || len(s.Signatures) != 2 {
+		t.Fatalf("unexpected signatures: %+v", s.Signatures)
+	}
+
+	// signatures are de-duplicated by public key, regardless of its
+	// encoding
+	der, err := x509.MarshalPKIXPublicKey(k2.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig2.PubKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	if !s.AddSignature(sig2) || len(s.Signatures) != 2 || s.SignatureIndex(sig2.PubKey) != 1 {
+		t.Fatalf("unexpected signatures: %+v", s.Signatures)
+	}
+
+	if !s.RemoveSignature(sig1.PubKey) || len(s.Signatures) != 1 || s.SignatureIndex(sig1.PubKey) != -1 {
+		t.Fatalf("unexpected signatures: %+v", s.Signatures)
+	}
+
+	// error expected here as the signature has already been removed
+	if s.RemoveSignature(sig1.PubKey) {
+		t.Fatal("missing signature has been removed")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	_, k1, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, k2, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s1, err := Parse(signedStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s2, err := Parse(signedStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s1.AddSignature(sign(t, s1, k1))
+	s2.AddSignature(sign(t, s2, k1))
+	s2.AddSignature(sign(t, s2, k2))
+
+	// the signature by k1 is already present
+	if n, err := s1.Merge(s2); err != nil || n != 1 || len(s1.Signatures) != 2 {
+		t.Fatalf("unexpected merge: %d (%v)", n, err)
+	}
+
+	for _, sig := range s1.Signatures {
+		pub, err := signature.ParsePublicKey(sig.PubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = s1.VerifySignature(&sig, pub); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestNegativeMerge(t *testing.T) {
+	s1, err := Parse(signedStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s2, err := Parse(signedStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the statements content does not match
+	s2.Version = "v2"
+
+	if _, err = s1.Merge(s2); !errors.Is(err, ErrContentMismatch) {
+		t.Fatalf("statements with different content have been merged: %v", err)
+	}
+}
@@ -9,18 +9,44 @@ package statement
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/usbarmory/boot-transparency/artifact"
 )
 
+// Define the namespace of the signing envelope, which binds statement
+// signatures to the approval of a boot bundle, so that signatures produced
+// by the same key for other purposes cannot be replayed as approvals.
+const SignatureNamespace = "boot-transparency-statement-v1"
+
 // Signature including the signer's public key to ease the verifier while
 // checking its validity
 type Signature struct {
-	// Ed25519 signer public key in OpenSSH format
-	PubKey string `json:"pub_key"`
+	// signer public key in OpenSSH, or PEM, format
+	PubKey string `json:"pub_key" validate:"required"`
+
+	// signature in hex format
+	Signature string `json:"signature" validate:"required,hex"`
+
+	// signature algorithm (e.g. signature.ECDSAP256SHA256), Ed25519
+	// when empty
+	Algorithm string `json:"algorithm,omitempty"`
 
-	// Ed25519 signature in hex format
-	Signature string `json:"signature"`
+	// signing envelope namespace (i.e. SignatureNamespace), empty for
+	// legacy signatures computed over the statement artifacts only
+	Envelope string `json:"envelope,omitempty"`
+}
+
+// Define the statement fields bound by the signing envelope
+type envelope struct {
+	Kind        string       `json:"kind,omitempty"`
+	Description string       `json:"description"`
+	Version     string       `json:"version"`
+	NotBefore   string       `json:"not_before,omitempty"`
+	NotAfter    string       `json:"not_after,omitempty"`
+	Target      *Target      `json:"target,omitempty"`
+	Revokes     []Revocation `json:"revokes,omitempty"`
+	Artifacts   []Artifact   `json:"artifacts"`
 }
 
 // Define Artifact structure as a container for claims for a given artifact
@@ -37,11 +63,26 @@ type Artifact struct {
 
 // Define the statement that will be logged when releasing a new bundle of artifacts
 type Statement struct {
+	// statement kind (e.g. KindRevocation), KindBundle when empty
+	Kind string `json:"kind,omitempty"`
+
 	// human-readable title for the bundle
 	Description string `json:"description,omitempty"`
 
 	// bundle version, using Semantic Versioning 2.0.0 (see semver.org)
-	Version string `json:"version,omitempty"`
+	Version string `json:"version,omitempty" validate:"version"`
+
+	// start of the statement validity period, in RFC3339 format
+	NotBefore string `json:"not_before,omitempty" validate:"timestamp"`
+
+	// end of the statement validity period, in RFC3339 format
+	NotAfter string `json:"not_after,omitempty" validate:"timestamp"`
+
+	// devices targeted by the bundle, any device when unset
+	Target *Target `json:"target,omitempty"`
+
+	// revoked statements, only for KindRevocation statements
+	Revokes []Revocation `json:"revokes,omitempty"`
 
 	// artifact claims
 	Artifacts []Artifact `json:"artifacts"`
@@ -51,26 +92,121 @@ type Statement struct {
 }
 
 // Parse the logged statement which is included as serialized JSON in the proof bundle
+//
+// The parsing is strict, unknown fields are rejected and the claims are
+// validated according to their artifact category (see artifact.Unmarshal()).
+//
+// Return error, as *artifact.FieldError pinpointing the JSON path of the
+// offending field when applicable, if:
+//   - the parsing fails
+//   - the validity period is not valid (see ValidityPeriod())
+//   - the statement content does not match its kind
+//   - an artifact category is not registered
+//   - the claims parsing fails
 func Parse(jsonStatement []byte) (s *Statement, err error) {
 	var h artifact.Handler
 
-	if err = json.Unmarshal(jsonStatement, &s); err != nil {
-		return
+	if err = artifact.Unmarshal(jsonStatement, &s); err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, fmt.Errorf("empty statement")
+	}
+
+	if _, _, err = s.ValidityPeriod(); err != nil {
+		return nil, err
 	}
 
-	for _, a := range s.Artifacts {
+	if err = s.checkKind(); err != nil {
+		return nil, err
+	}
+
+	for i, a := range s.Artifacts {
 		// check if an artifact handler is registered for the given artifact category
 		h, err = artifact.GetHandler(a.Category)
 
 		if err != nil {
-			return
+			return nil, artifact.AtPath(fmt.Sprintf("artifacts[%d].category", i), err)
 		}
 
 		// invoke the correspondent claims parser for the given artifact category
 		if _, err = h.ParseClaims(a.Claims); err != nil {
-			return
+			return nil, artifact.AtPath(fmt.Sprintf("artifacts[%d].claims", i), err)
 		}
 	}
 
 	return
 }
+
+// Return the message to be signed by the statement signers, that is the
+// signing envelope namespace (i.e. SignatureNamespace), followed by a NUL
+// byte, and the canonical form (see Canonicalize()) of the statement kind,
+// description, version, validity period, target, revocations and artifacts.
+func (s *Statement) SigningPayload() ([]byte, error) {
+	e, err := json.Marshal(&envelope{
+		Kind:        s.Kind,
+		Description: s.Description,
+		Version:     s.Version,
+		NotBefore:   s.NotBefore,
+		NotAfter:    s.NotAfter,
+		Target:      s.Target,
+		Revokes:     s.Revokes,
+		Artifacts:   s.Artifacts,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	canonical, err := Canonicalize(e)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(SignatureNamespace+"\x00"), canonical...), nil
+}
+
+// Return the message to be signed by the statement signers for legacy
+// signatures, that is the canonical form of the statement artifacts only.
+func (s *Statement) LegacySigningPayload() ([]byte, error) {
+	artifacts, err := json.Marshal(s.Artifacts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Canonicalize(artifacts)
+}
+
+// Return the messages that might have been signed with the given signature,
+// depending on its signing envelope. Legacy signatures might have been
+// computed over the LegacySigningPayload() or, by earlier releases, over the
+// compact form of the statement artifacts.
+//
+// Return error if:
+//   - the signing envelope is not supported
+//   - the statement serialization fails
+func (s *Statement) SignedPayloads(sig *Signature) ([][]byte, error) {
+	switch sig.Envelope {
+	case SignatureNamespace:
+		payload, err := s.SigningPayload()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return [][]byte{payload}, nil
+	case "":
+		artifacts, err := json.Marshal(s.Artifacts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return CanonicalForms(artifacts)
+	default:
+		return nil, fmt.Errorf("unsupported signature envelope %q", sig.Envelope)
+	}
+}
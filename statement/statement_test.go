@@ -0,0 +1,126 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+var signedStatement = []byte(`{
+    "version": "v1",
+    "description": "Linux bundle",
+    "artifacts": [{"category": 1, "claims": {"version": "v6.14.0-29-generic", "architecture": "x64", "hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59"}}]
+}`)
+
+func TestSigningPayload(t *testing.T) {
+	s, err := Parse(signedStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "boot-transparency-statement-v1\x00" +
+		`{"artifacts":[{"category":1,"claims":{"architecture":"x64","hash":"8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59","version":"v6.14.0-29-generic"}}],"description":"Linux bundle","version":"v1"}`
+
+	if string(payload) != expected {
+		t.Fatalf("unexpected signing payload: %q", payload)
+	}
+
+	payloads, err := s.SignedPayloads(&Signature{Envelope: SignatureNamespace})
+	if err != nil || len(payloads) != 1 || string(payloads[0]) != expected {
+		t.Fatalf("unexpected signed payloads: %q (%v)", payloads, err)
+	}
+
+	// legacy signatures might be computed over the canonical, or compact,
+	// form of the artifacts
+	payloads, err = s.SignedPayloads(&Signature{})
+	if err != nil || len(payloads) != 2 {
+		t.Fatalf("unexpected legacy signed payloads: %q (%v)", payloads, err)
+	}
+
+	if legacy, err := s.LegacySigningPayload(); err != nil || string(legacy) != string(payloads[0]) {
+		t.Fatalf("unexpected legacy signing payload: %q (%v)", legacy, err)
+	}
+}
+
+func TestNegativeSignedPayloads(t *testing.T) {
+	s, err := Parse(signedStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the envelope is not supported
+	if _, err = s.SignedPayloads(&Signature{Envelope: "boot-transparency-statement-v0"}); err == nil {
+		t.Fatal("unsupported signing envelope has been accepted")
+	}
+}
+
+func TestNegativeParse(t *testing.T) {
+	for _, v := range []struct {
+		statement string
+		path      string
+		err       error
+	}{
+		{
+			`{"artifacts": [{"category": 1, "claims": {"hahs": "8ba6bc3d"}}]}`,
+			"artifacts[0].claims.hahs",
+			artifact.ErrUnknownField,
+		},
+		{
+			`{"artifacts": [{"category": 1, "claims": {"version": "v6.14.0"}}]}`,
+			"artifacts[0].claims.hash",
+			artifact.ErrMissingField,
+		},
+		{
+			`{"artifacts": [{"category": 2, "claims": {"hash": "8ba6bc3d"}}]}`,
+			"artifacts[0].claims.hash",
+			artifact.ErrInvalidFormat,
+		},
+		{
+			`{"artifacts": [{"category": 1, "claims": {"hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59", "timestamp": "2025-10-21"}}]}`,
+			"artifacts[0].claims.timestamp",
+			artifact.ErrInvalidFormat,
+		},
+		{
+			`{"version": "v1", "artifact": []}`,
+			"artifact",
+			artifact.ErrUnknownField,
+		},
+		{
+			`{"artifacts": [], "signatures": [{"pub_key": "ssh-ed25519 AAAA", "signature": "zz"}]}`,
+			"signatures[0].signature",
+			artifact.ErrInvalidFormat,
+		},
+		{
+			`{"not_after": "2026-01-01", "artifacts": []}`,
+			"not_after",
+			artifact.ErrInvalidFormat,
+		},
+		{
+			`{"not_before": "2026-01-01T00:00:00Z", "not_after": "2025-01-01T00:00:00Z", "artifacts": []}`,
+			"not_after",
+			artifact.ErrInvalidFormat,
+		},
+	} {
+		var fe *artifact.FieldError
+
+		// error expected here as the statement is not valid
+		_, err := Parse([]byte(v.statement))
+
+		if !errors.As(err, &fe) || fe.Path != v.path || !errors.Is(err, v.err) {
+			t.Fatalf("unexpected error for %s: %v", v.statement, err)
+		}
+	}
+}
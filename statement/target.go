@@ -0,0 +1,109 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrTargetMismatch is returned when the device is not targeted by the
+// statement.
+var ErrTargetMismatch = errors.New("the device is not targeted by the statement")
+
+// Define the devices targeted by a statement. Each selector restricts the
+// targeted devices to the ones matching at least one of its values, unset
+// selectors match any device.
+type Target struct {
+	// product identifiers (e.g. usbarmory-mk2)
+	Products []string `json:"products,omitempty"`
+
+	// device tree board compatible strings (e.g. usbarmory,imx6ulz-usbarmory)
+	Compatible []string `json:"compatible,omitempty"`
+
+	// machine types, using the EFI specification vocabulary (e.g. x64, AA64)
+	Machines []string `json:"machines,omitempty"`
+
+	// DMI system vendors (e.g. LENOVO)
+	DMIVendors []string `json:"dmi_vendors,omitempty"`
+
+	// DMI system product names (e.g. 21KCCTO1WW)
+	DMIModels []string `json:"dmi_models,omitempty"`
+}
+
+// Define the identity of a device, as supplied by the caller (e.g. read
+// from the device tree, DMI tables or fuses), unknown fields are left unset.
+type Device struct {
+	// product identifier
+	Product string
+
+	// device tree board compatible strings, from the most specific one
+	Compatible []string
+
+	// machine type, using the EFI specification vocabulary
+	Machine string
+
+	// DMI system vendor
+	DMIVendor string
+
+	// DMI system product name
+	DMIModel string
+}
+
+func matchSelector(name string, values []string, id ...string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	for _, v := range id {
+		if v != "" && slices.Contains(values, v) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w (%s)", ErrTargetMismatch, name)
+}
+
+// Check that a device matches all the target selectors, a nil target
+// matches any device.
+//
+// Return error if:
+//   - any selector does not match the device (ErrTargetMismatch)
+func (t *Target) Matches(d *Device) (err error) {
+	if t == nil {
+		return
+	}
+
+	if d == nil {
+		d = &Device{}
+	}
+
+	if err = matchSelector("products", t.Products, d.Product); err != nil {
+		return
+	}
+
+	if err = matchSelector("compatible", t.Compatible, d.Compatible...); err != nil {
+		return
+	}
+
+	if err = matchSelector("machines", t.Machines, d.Machine); err != nil {
+		return
+	}
+
+	if err = matchSelector("dmi_vendors", t.DMIVendors, d.DMIVendor); err != nil {
+		return
+	}
+
+	return matchSelector("dmi_models", t.DMIModels, d.DMIModel)
+}
+
+// Return true if the target does not restrict the targeted devices.
+func (t *Target) Empty() bool {
+	return t == nil || len(t.Products)+len(t.Compatible)+len(t.Machines)+len(t.DMIVendors)+len(t.DMIModels) == 0
+}
@@ -0,0 +1,93 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var targetStatement = []byte(`{
+    "version": "v1",
+    "target": {
+        "products": ["usbarmory-mk2"],
+        "compatible": ["usbarmory,imx6ulz-usbarmory", "usbarmory,imx6ul-usbarmory"],
+        "machines": ["ARM"]
+    },
+    "artifacts": [{"category": 1, "claims": {"hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59"}}]
+}`)
+
+func TestTargetMatches(t *testing.T) {
+	s, err := Parse(targetStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Device{
+		Product:    "usbarmory-mk2",
+		Compatible: []string{"usbarmory,imx6ul-usbarmory", "fsl,imx6ul"},
+		Machine:    "ARM",
+		DMIVendor:  "WithSecure",
+	}
+
+	if err = s.Target.Matches(d); err != nil {
+		t.Fatal(err)
+	}
+
+	// statements without target match any device
+	var target *Target
+
+	if err = target.Matches(d); err != nil || !target.Empty() {
+		t.Fatal(err)
+	}
+}
+
+func TestNegativeTargetMatches(t *testing.T) {
+	s, err := Parse(targetStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range []*Device{
+		nil,
+		{Product: "usbarmory-mk2", Compatible: []string{"fsl,imx6ul"}, Machine: "ARM"},
+		{Product: "usbarmory-mk1", Compatible: []string{"usbarmory,imx6ul-usbarmory"}, Machine: "ARM"},
+		{Product: "usbarmory-mk2", Compatible: []string{"usbarmory,imx6ul-usbarmory"}, Machine: "AA64"},
+		{Product: "usbarmory-mk2", Compatible: []string{"usbarmory,imx6ul-usbarmory"}},
+	} {
+		// error expected here as the device is not targeted
+		if err = s.Target.Matches(d); !errors.Is(err, ErrTargetMismatch) {
+			t.Fatalf("unexpected error for %+v: %v", d, err)
+		}
+	}
+}
+
+func TestTargetSigningPayload(t *testing.T) {
+	s, err := Parse(targetStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the target must be bound by the signatures
+	s.Target.Products = append(s.Target.Products, "usbarmory-mk1")
+
+	extended, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(payload, extended) {
+		t.Fatal("target change did not alter the signing payload")
+	}
+}
@@ -0,0 +1,76 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/usbarmory/boot-transparency/artifact"
+)
+
+var (
+	// ErrNotYetValid is returned when the statement validity period
+	// did not start yet.
+	ErrNotYetValid = errors.New("the statement is not yet valid")
+
+	// ErrExpired is returned when the statement validity period ended.
+	ErrExpired = errors.New("the statement is expired")
+)
+
+// Return the statement validity period, a zero time is returned for
+// unset bounds.
+//
+// Return error if:
+//   - a bound is not an RFC3339 timestamp
+//   - the period ends before its start
+func (s *Statement) ValidityPeriod() (notBefore time.Time, notAfter time.Time, err error) {
+	if s.NotBefore != "" {
+		if notBefore, err = time.Parse(time.RFC3339, s.NotBefore); err != nil {
+			return notBefore, notAfter, artifact.AtPath("not_before", fmt.Errorf("%w: RFC3339 timestamp expected", artifact.ErrInvalidFormat))
+		}
+	}
+
+	if s.NotAfter != "" {
+		if notAfter, err = time.Parse(time.RFC3339, s.NotAfter); err != nil {
+			return notBefore, notAfter, artifact.AtPath("not_after", fmt.Errorf("%w: RFC3339 timestamp expected", artifact.ErrInvalidFormat))
+		}
+	}
+
+	if !notBefore.IsZero() && !notAfter.IsZero() && notAfter.Before(notBefore) {
+		return notBefore, notAfter, artifact.AtPath("not_after", fmt.Errorf("%w: validity period ends before its start", artifact.ErrInvalidFormat))
+	}
+
+	return
+}
+
+// Check that the statement is valid at a given time, statements without
+// validity period bounds are valid at any time.
+//
+// Return error if:
+//   - the validity period is not valid (see ValidityPeriod())
+//   - the time is before the validity period start (ErrNotYetValid)
+//   - the time is after the validity period end (ErrExpired)
+func (s *Statement) ValidAt(t time.Time) error {
+	notBefore, notAfter, err := s.ValidityPeriod()
+
+	if err != nil {
+		return err
+	}
+
+	if !notBefore.IsZero() && t.Before(notBefore) {
+		return fmt.Errorf("%w (not before %s)", ErrNotYetValid, s.NotBefore)
+	}
+
+	if !notAfter.IsZero() && t.After(notAfter) {
+		return fmt.Errorf("%w (not after %s)", ErrExpired, s.NotAfter)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package statement
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+var validityStatement = []byte(`{
+    "version": "v1",
+    "not_before": "2025-10-01T00:00:00Z",
+    "not_after": "2026-10-01T00:00:00Z",
+    "artifacts": [{"category": 1, "claims": {"hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59"}}]
+}`)
+
+func TestValidAt(t *testing.T) {
+	s, err := Parse(validityStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct {
+		time string
+		err  error
+	}{
+		{"2025-09-30T23:59:59Z", ErrNotYetValid},
+		{"2025-10-01T00:00:00Z", nil},
+		{"2026-03-01T12:00:00+02:00", nil},
+		{"2026-10-01T00:00:00Z", nil},
+		{"2026-10-01T00:00:01Z", ErrExpired},
+	} {
+		now, err := time.Parse(time.RFC3339, v.time)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = s.ValidAt(now); !errors.Is(err, v.err) || (v.err == nil && err != nil) {
+			t.Fatalf("unexpected validity at %s: %v", v.time, err)
+		}
+	}
+
+	// statements without validity period are valid at any time
+	s.NotBefore = ""
+	s.NotAfter = ""
+
+	if err = s.ValidAt(time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValiditySigningPayload(t *testing.T) {
+	s, err := Parse(validityStatement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(payload, []byte(`"not_after":"2026-10-01T00:00:00Z","not_before":"2025-10-01T00:00:00Z"`)) {
+		t.Fatalf("validity period not covered by the signing payload: %q", payload)
+	}
+
+	// the validity period must be bound by the signatures
+	s.NotAfter = "2036-10-01T00:00:00Z"
+
+	extended, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(payload, extended) {
+		t.Fatal("validity period change did not alter the signing payload")
+	}
+}
@@ -0,0 +1,149 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package transparency
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Define an engine-agnostic proof bundle, as returned by ParseBundle().
+// The bundle can be passed as is to the Engine functions expecting a
+// proof bundle (e.g. VerifyProof).
+type Bundle interface {
+	// Return the transparency engine format (i.e. Sigsum, Tessera).
+	Format() uint
+
+	// Return the logged statement, in compact JSON format.
+	Statement() []byte
+
+	// Return the serialized probing data, its format depends by the
+	// transparency engine.
+	Probe() json.RawMessage
+
+	// Return the serialized inclusion proof, its format depends by the
+	// transparency engine.
+	Proof() json.RawMessage
+
+	// Return the proof bundle in the format expected by the transparency
+	// engine, as returned by Engine.ParseProof().
+	Native() interface{}
+}
+
+// Define a Bundle wrapping the proof bundle of a transparency engine.
+type bundle struct {
+	format uint
+	native interface{}
+	// engine-agnostic form of the native proof bundle
+	generic ProofBundle
+}
+
+func (b *bundle) Format() uint {
+	return b.format
+}
+
+func (b *bundle) Statement() []byte {
+	return b.generic.Statement
+}
+
+func (b *bundle) Probe() json.RawMessage {
+	return b.generic.Probe
+}
+
+func (b *bundle) Proof() json.RawMessage {
+	return b.generic.Proof
+}
+
+func (b *bundle) Native() interface{} {
+	return b.native
+}
+
+// The engine proof bundle is converted to the engine-agnostic form through
+// its JSON serialization.
+func (b *bundle) update() error {
+	var pb ProofBundle
+
+	buf, err := json.Marshal(b.native)
+
+	if err != nil {
+		return fmt.Errorf("invalid proof bundle: %v", err)
+	}
+
+	if err = json.Unmarshal(buf, &pb); err != nil {
+		return fmt.Errorf("invalid proof bundle: %v", err)
+	}
+
+	b.generic = pb
+
+	return nil
+}
+
+// Update the engine-agnostic form of a proof bundle, when the given proof
+// bundle is a Bundle, from its engine proof bundle. It is meant to be
+// invoked by the transparency engines once they updated the proof bundle
+// (e.g. Engine.GetProof()), so that the Bundle is never stale.
+//
+// Return error if the engine proof bundle cannot be serialized.
+func UpdateBundle(proofBundle interface{}) error {
+	if b, ok := proofBundle.(*bundle); ok {
+		return b.update()
+	}
+
+	return nil
+}
+
+// Parse a proof bundle in JSON format, the bundle is parsed by the
+// registered transparency engine matching its format field.
+//
+// Return error if:
+//   - the JSON parsing fails
+//   - the bundle format is not a registered transparency engine
+//   - the parsing of the bundle by the transparency engine fails
+//   - the bundle parsed by the transparency engine cannot be serialized
+func ParseBundle(jsonProofBundle []byte) (Bundle, error) {
+	var pb ProofBundle
+
+	if err := json.Unmarshal(jsonProofBundle, &pb); err != nil {
+		return nil, fmt.Errorf("invalid proof bundle: %v", err)
+	}
+
+	// the parsing does not depend on the engine configuration
+	e, err := NewEngine(pb.Format, Config{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	native, _, err := e.ParseProof(jsonProofBundle)
+
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bundle{
+		format: pb.Format,
+		native: native,
+	}
+
+	if err = b.update(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Return the proof bundle in the format expected by the transparency
+// engine, when the given proof bundle is a Bundle, or the proof bundle
+// itself otherwise.
+func NativeBundle(proofBundle interface{}) interface{} {
+	if b, ok := proofBundle.(Bundle); ok {
+		return b.Native()
+	}
+
+	return proofBundle
+}
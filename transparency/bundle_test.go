@@ -0,0 +1,61 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package transparency
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testProofBundle struct {
+	Format    uint            `json:"format"`
+	Statement json.RawMessage `json:"statement"`
+	Probe     interface{}     `json:"probe,omitempty"`
+	Proof     string          `json:"proof,omitempty"`
+}
+
+func TestUpdateBundle(t *testing.T) {
+	pb := &testProofBundle{
+		Statement: json.RawMessage(`{"description":"test"}`),
+		Probe:     map[string]string{"origin": "https://log.example.org/"},
+	}
+
+	b := &bundle{native: pb}
+
+	if err := UpdateBundle(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b.Statement()) != `{"description":"test"}` || b.Proof() != nil {
+		t.Fatalf("unexpected bundle: %s %s", b.Statement(), b.Proof())
+	}
+
+	// the engine-agnostic form is updated along with the engine proof
+	// bundle
+	pb.Proof = "proof"
+
+	if err := UpdateBundle(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b.Proof()) != `"proof"` {
+		t.Fatalf("unexpected proof: %s", b.Proof())
+	}
+
+	// error expected here as the engine proof bundle cannot be serialized
+	pb.Probe = make(chan int)
+
+	if err := UpdateBundle(b); err == nil {
+		t.Fatal("invalid proof bundle has been accepted")
+	}
+
+	// the last valid engine-agnostic form is retained
+	if string(b.Proof()) != `"proof"` {
+		t.Fatalf("unexpected proof: %s", b.Proof())
+	}
+}
@@ -0,0 +1,169 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package transparency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	// default user agent for log requests
+	DefaultUserAgent = "boot-transparency"
+	// default timeout of each log request attempt
+	DefaultTimeout = 30 * time.Second
+	// default delay before the first retry of a failed log request
+	DefaultBackoff = 1 * time.Second
+	// maximum delay reached by doubling the backoff between retries
+	MaxBackoff = 5 * time.Minute
+)
+
+// Define the network configuration used by a transparency engine
+// instance to reach the logs.
+type Network struct {
+	// HTTP client used for all log requests, it allows the configuration
+	// of proxies, custom CAs or client certificates. When not set, a
+	// default client is used.
+	HTTPClient *http.Client
+
+	// user agent for log requests, DefaultUserAgent when empty
+	UserAgent string
+
+	// timeout of each log request attempt, DefaultTimeout when zero
+	Timeout time.Duration
+
+	// number of retries for failed log requests, zero disables retries
+	Retries uint
+
+	// delay before the first retry, doubled for each further retry up
+	// to MaxBackoff, DefaultBackoff when zero
+	Backoff time.Duration
+
+	// alternative URLs for each log origin, attempted in order when
+	// a request to the log origin fails. Responses from mirrors are
+	// verified against the trusted log keys as for the log origin.
+	Mirrors map[string][]string
+}
+
+// Define an HTTP transport which sets the user agent on requests
+// that do not already carry one.
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// Return the user agent for log requests.
+func (n *Network) Agent() string {
+	if n.UserAgent == "" {
+		return DefaultUserAgent
+	}
+
+	return n.UserAgent
+}
+
+// Return the HTTP client for log requests, the returned client sets the
+// configured user agent on all requests.
+func (n *Network) Client() *http.Client {
+	var c http.Client
+
+	if n.HTTPClient != nil {
+		c = *n.HTTPClient
+	} else {
+		c.Transport = &http.Transport{
+			MaxIdleConns:       10,
+			IdleConnTimeout:    29 * time.Second,
+			DisableCompression: true,
+		}
+	}
+
+	base := c.Transport
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	c.Transport = &userAgentTransport{
+		base:      base,
+		userAgent: n.Agent(),
+	}
+
+	return &c
+}
+
+// Invoke the given request function against the log origin and, when
+// it fails, against each configured mirror. The whole sequence is
+// repeated for the configured number of retries, waiting an exponential
+// backoff in between. Each attempt is bound to the configured timeout.
+//
+// Return the error of the last attempt, or the context error when the
+// context is done.
+func (n *Network) Do(ctx context.Context, origin string, f func(ctx context.Context, url string) error) (err error) {
+	timeout := n.Timeout
+	backoff := n.Backoff
+
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	if backoff == 0 {
+		backoff = DefaultBackoff
+	}
+
+	urls := append([]string{origin}, n.Mirrors[origin]...)
+
+	for retry := uint(0); ; retry++ {
+		for _, u := range urls {
+			if err = attempt(ctx, timeout, u, f); err == nil {
+				return
+			}
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+
+		if retry >= n.Retries {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryDelay(backoff, retry)):
+		}
+	}
+}
+
+// Return the delay before a given retry, that is the backoff doubled for
+// each previous retry, capped to MaxBackoff unless the backoff exceeds it.
+func retryDelay(backoff time.Duration, retry uint) time.Duration {
+	delay := backoff
+
+	for i := uint(0); i < retry && delay < MaxBackoff; i++ {
+		delay <<= 1
+	}
+
+	return max(min(delay, MaxBackoff), backoff)
+}
+
+func attempt(ctx context.Context, timeout time.Duration, url string, f func(ctx context.Context, url string) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return f(ctx, url)
+}
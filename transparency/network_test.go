@@ -0,0 +1,180 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package transparency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNetworkDoRetries(t *testing.T) {
+	var attempts int
+
+	n := Network{
+		Retries: 2,
+		Backoff: time.Millisecond,
+	}
+
+	err := n.Do(context.Background(), "https://log.example.org/", func(ctx context.Context, url string) error {
+		attempts++
+
+		if attempts < 3 {
+			return fmt.Errorf("unreachable")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attempts != 3 {
+		t.Fatalf("unexpected number of attempts: %d", attempts)
+	}
+}
+
+func TestNegativeNetworkDoRetries(t *testing.T) {
+	var attempts int
+
+	n := Network{
+		Retries: 2,
+		Backoff: time.Millisecond,
+	}
+
+	err := n.Do(context.Background(), "https://log.example.org/", func(ctx context.Context, url string) error {
+		attempts++
+		return fmt.Errorf("unreachable")
+	})
+
+	if err == nil || err.Error() != "unreachable" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Fatalf("unexpected number of attempts: %d", attempts)
+	}
+}
+
+func TestNetworkRetryDelay(t *testing.T) {
+	for _, v := range []struct {
+		backoff time.Duration
+		retry   uint
+		delay   time.Duration
+	}{
+		{time.Second, 0, time.Second},
+		{time.Second, 3, 8 * time.Second},
+		{time.Second, 64, MaxBackoff},
+		{time.Second, ^uint(0), MaxBackoff},
+		{time.Hour, 64, time.Hour},
+	} {
+		if delay := retryDelay(v.backoff, v.retry); delay != v.delay {
+			t.Fatalf("unexpected delay for retry %d: %v, expected %v", v.retry, delay, v.delay)
+		}
+	}
+}
+
+func TestNetworkDoMirrors(t *testing.T) {
+	var urls []string
+
+	origin := "https://log.example.org/"
+
+	n := Network{
+		Mirrors: map[string][]string{
+			origin: {"https://mirror1.example.org/", "https://mirror2.example.org/"},
+		},
+	}
+
+	err := n.Do(context.Background(), origin, func(ctx context.Context, url string) error {
+		urls = append(urls, url)
+
+		if url != "https://mirror2.example.org/" {
+			return fmt.Errorf("unreachable")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(urls) != 3 || urls[0] != origin || urls[1] != "https://mirror1.example.org/" {
+		t.Fatalf("unexpected attempted URLs: %v", urls)
+	}
+}
+
+func TestNetworkDoTimeout(t *testing.T) {
+	n := Network{
+		Timeout: time.Millisecond,
+	}
+
+	err := n.Do(context.Background(), "https://log.example.org/", func(ctx context.Context, url string) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNegativeNetworkDoContext(t *testing.T) {
+	var attempts int
+
+	n := Network{
+		Retries: 10,
+		Backoff: time.Hour,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := n.Do(ctx, "https://log.example.org/", func(ctx context.Context, url string) error {
+		attempts++
+		cancel()
+		return fmt.Errorf("unreachable")
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if attempts != 1 {
+		t.Fatalf("unexpected number of attempts: %d", attempts)
+	}
+}
+
+func TestNetworkClient(t *testing.T) {
+	var userAgent string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+	}))
+	defer srv.Close()
+
+	for _, n := range []Network{
+		{},
+		{UserAgent: "update-agent/1.0", HTTPClient: &http.Client{}},
+	} {
+		res, err := n.Client().Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = res.Body.Close()
+
+		if userAgent != n.Agent() {
+			t.Fatalf("unexpected user agent: %s", userAgent)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package transparency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// serializes the trust state checks
+var trustStateMutex sync.Mutex
+
+// Define a tree head that has been verified by a transparency engine.
+type TreeHead struct {
+	// log origin, as authenticated by the log signature
+	Origin string `json:"origin"`
+
+	// number of leaves in the tree
+	Size uint64 `json:"size"`
+
+	// root hash of the tree
+	RootHash []byte `json:"root_hash"`
+}
+
+// Define a consistency proof between two tree heads of the same log.
+type ConsistencyProof struct {
+	// size of the older tree
+	OldSize uint64 `json:"old_size"`
+
+	// size of the newer tree
+	NewSize uint64 `json:"new_size"`
+
+	// consistency proof node hashes
+	Path [][]byte `json:"path"`
+}
+
+// Define a high-level interface to persist the latest trusted tree head
+// for each log origin, it allows transparency engines to detect rollbacks.
+type TrustState interface {
+	// Return the latest trusted tree head for the given log origin,
+	// nil is returned if no tree head has been stored yet.
+	Load(origin string) (*TreeHead, error)
+
+	// Store the latest trusted tree head for its log origin.
+	Store(th *TreeHead) error
+}
+
+// Check a verified tree head against the latest trusted one for the same
+// log origin, the trust state is updated if the check passes.
+//
+// The consistency function is invoked to verify that the stored tree head
+// is a prefix of the given one whenever the log has grown.
+//
+// Checks are serialized, so that concurrent verifications sharing the same
+// trust state cannot replace a tree head with an older one.
+//
+// Return error if:
+//   - the tree head is older than the trusted one (i.e. rollback)
+//   - the tree head has the same size but a different root hash
+//   - the consistency function returns error
+//   - the trust state cannot be loaded or stored
+func CheckTrustState(ts TrustState, th *TreeHead, consistency func(trusted *TreeHead) error) (err error) {
+	trustStateMutex.Lock()
+	defer trustStateMutex.Unlock()
+
+	trusted, err := ts.Load(th.Origin)
+
+	if err != nil {
+		return fmt.Errorf("cannot load trusted tree head: %v", err)
+	}
+
+	switch {
+	case trusted == nil:
+		// trust on first use
+	case th.Size < trusted.Size:
+		return fmt.Errorf("tree head rollback detected, size %d is older than trusted size %d", th.Size, trusted.Size)
+	case th.Size == trusted.Size:
+		if !bytes.Equal(th.RootHash, trusted.RootHash) {
+			return fmt.Errorf("tree head root hash does not match the trusted one for size %d", th.Size)
+		}
+
+		// nothing to update
+		return
+	default:
+		if err = consistency(trusted); err != nil {
+			return fmt.Errorf("tree head is not consistent with the trusted one: %v", err)
+		}
+	}
+
+	if err = ts.Store(th); err != nil {
+		return fmt.Errorf("cannot store trusted tree head: %v", err)
+	}
+
+	return
+}
+
+// Define a trust state kept in memory.
+type MemoryTrustState struct {
+	sync.Mutex
+	treeHeads map[string]TreeHead
+}
+
+// Return the latest trusted tree head for the given log origin.
+func (m *MemoryTrustState) Load(origin string) (*TreeHead, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	th, ok := m.treeHeads[origin]
+
+	if !ok {
+		return nil, nil
+	}
+
+	return &th, nil
+}
+
+// Store the latest trusted tree head for its log origin.
+func (m *MemoryTrustState) Store(th *TreeHead) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.treeHeads == nil {
+		m.treeHeads = make(map[string]TreeHead)
+	}
+
+	m.treeHeads[th.Origin] = *th
+
+	return nil
+}
+
+// Define a trust state persisted on disk, each log origin is stored
+// as a JSON file within the given directory.
+type FileTrustState struct {
+	// directory where trusted tree heads are stored
+	Dir string
+}
+
+// Return the latest trusted tree head for the given log origin.
+func (f *FileTrustState) Load(origin string) (*TreeHead, error) {
+	var th TreeHead
+
+	buf, err := os.ReadFile(f.path(origin))
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(buf, &th); err != nil {
+		return nil, err
+	}
+
+	if th.Origin != origin {
+		return nil, fmt.Errorf("stored tree head origin %q does not match %q", th.Origin, origin)
+	}
+
+	return &th, nil
+}
+
+// Store the latest trusted tree head for its log origin.
+func (f *FileTrustState) Store(th *TreeHead) (err error) {
+	buf, err := json.MarshalIndent(th, "", "\t")
+
+	if err != nil {
+		return
+	}
+
+	// write to a temporary file first, then rename it to atomically
+	// replace any previously stored tree head
+	tmp, err := os.CreateTemp(f.Dir, ".tree-head-*")
+
+	if err != nil {
+		return
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+		return
+	}
+
+	if err = tmp.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(tmp.Name(), f.path(th.Origin))
+}
+
+// the file name is derived from the origin hash, as the origin
+// may contain characters which are not allowed in file names
+func (f *FileTrustState) path(origin string) string {
+	return filepath.Join(f.Dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(origin))))
+}
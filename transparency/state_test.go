@@ -0,0 +1,117 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package transparency
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestCheckTrustState(t *testing.T) {
+	var consistencyChecks int
+
+	ts := &MemoryTrustState{}
+
+	consistency := func(trusted *TreeHead) error {
+		consistencyChecks++
+		return nil
+	}
+
+	th := &TreeHead{Origin: "example.org/log", Size: 10, RootHash: []byte{10}}
+
+	// trust on first use
+	if err := CheckTrustState(ts, th, consistency); err != nil {
+		t.Fatal(err)
+	}
+
+	// same tree head
+	if err := CheckTrustState(ts, th, consistency); err != nil {
+		t.Fatal(err)
+	}
+
+	th = &TreeHead{Origin: "example.org/log", Size: 20, RootHash: []byte{20}}
+
+	if err := CheckTrustState(ts, th, consistency); err != nil {
+		t.Fatal(err)
+	}
+
+	if consistencyChecks != 1 {
+		t.Fatalf("unexpected number of consistency checks: %d", consistencyChecks)
+	}
+
+	trusted, err := ts.Load("example.org/log")
+
+	if err != nil || trusted == nil || trusted.Size != 20 {
+		t.Fatalf("unexpected trusted tree head: %v %v", trusted, err)
+	}
+}
+
+func TestNegativeCheckTrustState(t *testing.T) {
+	ts := &MemoryTrustState{}
+
+	consistency := func(trusted *TreeHead) error {
+		return fmt.Errorf("inconsistent")
+	}
+
+	th := &TreeHead{Origin: "example.org/log", Size: 10, RootHash: []byte{10}}
+
+	if err := ts.Store(th); err != nil {
+		t.Fatal(err)
+	}
+
+	// rollback
+	if err := CheckTrustState(ts, &TreeHead{Origin: "example.org/log", Size: 9, RootHash: []byte{9}}, consistency); err == nil {
+		t.Fatal(err)
+	}
+
+	// split view
+	if err := CheckTrustState(ts, &TreeHead{Origin: "example.org/log", Size: 10, RootHash: []byte{11}}, consistency); err == nil {
+		t.Fatal(err)
+	}
+
+	// failing consistency check
+	if err := CheckTrustState(ts, &TreeHead{Origin: "example.org/log", Size: 11, RootHash: []byte{11}}, consistency); err == nil {
+		t.Fatal(err)
+	}
+
+	// the trust state must not be updated on failure
+	trusted, err := ts.Load("example.org/log")
+
+	if err != nil || trusted == nil || trusted.Size != 10 {
+		t.Fatalf("unexpected trusted tree head: %v %v", trusted, err)
+	}
+}
+
+func TestFileTrustState(t *testing.T) {
+	ts := &FileTrustState{Dir: t.TempDir()}
+
+	th, err := ts.Load("example.org/log")
+
+	if err != nil || th != nil {
+		t.Fatalf("unexpected trusted tree head: %v %v", th, err)
+	}
+
+	for _, size := range []uint64{10, 20} {
+		th = &TreeHead{Origin: "example.org/log", Size: size, RootHash: []byte{byte(size)}}
+
+		if err = ts.Store(th); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	trusted, err := ts.Load("example.org/log")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if trusted.Origin != th.Origin || trusted.Size != th.Size || !bytes.Equal(trusted.RootHash, th.RootHash) {
+		t.Fatalf("unexpected trusted tree head: %v", trusted)
+	}
+}
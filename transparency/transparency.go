@@ -8,8 +8,12 @@
 package transparency
 
 import (
+	"context"
+	"crypto"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 )
 
 // Supported transparency engines
@@ -23,13 +27,14 @@ const (
 // - the logged statement (i.e. claims)
 // - the probing data to request the inclusion proof to the log
 // - the inclusion proof
+// - the consistency proof (optional) from the verifier trusted tree head
 type ProofBundle struct {
 	// specify which transparency engine should be used to
 	// verify this proof (i.e. Sigsum, Tessera)
 	Format uint `json:"format"`
 
 	// serialized JSON of Statement struct
-	Statement []byte `json:"statement"`
+	Statement json.RawMessage `json:"statement"`
 
 	// serialized inclusion proof probing data,
 	// its format depends by the chosen transparency engine
@@ -38,6 +43,10 @@ type ProofBundle struct {
 	// inclusion proof, its format depends by the chosen
 	// transparency engine
 	Proof json.RawMessage `json:"proof,omitempty"`
+
+	// consistency proof from the latest tree head trusted by the
+	// verifier to the one included in the inclusion proof
+	Consistency *ConsistencyProof `json:"consistency,omitempty"`
 }
 
 // Define high-level interface for transparency layer.
@@ -49,49 +58,78 @@ type Engine interface {
 	// proof bundle.
 	// The public log is identified via its origin while the information
 	// from ProofBundle allow to assemble the request for the log leaf.
-	// The function expects as input a ProofBundle as returned by ProofParse().
+	// The function expects as input a ProofBundle as returned by ProofParse(),
+	// or ParseBundle().
 	// The function does not require any previous log status (i.e. checkpoint).
 	// The latest signed tree-head is fetched from the log along with the leaf
-	// inclusion proof.
+	// inclusion proof. Log requests are bound to the given context, and are
+	// performed according to the engine network configuration.
 	// The inclusion proof is returned as []byte where its actual
 	// content depends by the chosen transparency engine.
+	// The inclusion proof of the given ProofBundle is replaced with the
+	// returned one, and its probing data might be updated to keep it
+	// consistent with the returned proof (e.g. Tessera signed checkpoint).
+	// The engine-agnostic form of a Bundle is updated as well (see
+	// UpdateBundle()).
+	// When the trust state is set, and the log has grown since the trusted
+	// tree head, the consistency proof between the trusted tree head and the
+	// fetched one is set in the proof bundle, so that it can be verified
+	// (see VerifyProof()).
 	//
 	// Return error if:
 	//   - the transparency engine is configured off-line
 	//   - the log key is not configured
 	//   - the submitter key is not configured
 	//   - the statement leaf is not present in the log
+	//   - the trust state cannot be loaded
 	//   - any other error is returned by the public log
-	GetProof(proofBundle interface{}) ([]byte, error)
-
-	// Parse the witness policy according with the format expected by the
-	// chosen transparency engine.
+	//   - the context is done
+	//   - the updated proof bundle cannot be serialized
+	GetProof(ctx context.Context, proofBundle interface{}) ([]byte, error)
+
+	// Submit a statement to the log reachable at the given URL, and wait
+	// for its inclusion. When required by the transparency engine, the
+	// log leaf is signed with the given signer (i.e. submitter key).
+	// The returned proof bundle, in the format returned by ParseProof(),
+	// includes the statement, the probing data and the inclusion proof.
+	//
 	// Return error if:
-	//   - the parsing of the policy fails
-	ParseWitnessPolicy(wp []byte) (interface{}, error)
-
-	// Set log and submitter keys that will be used by the transparency
-	// engine to fetch, or verify, the proof.
+	//   - the log key, or the witness policy, is not configured
+	//   - the statement is not a valid JSON document
+	//   - the signer is not supported by the transparency engine
+	//   - the log rejects the submission
+	//   - the statement inclusion is not proven before the timeout
+	//   - the context is done
+	Submit(ctx context.Context, logURL string, statement []byte, signer crypto.Signer) (interface{}, error)
+
+	// Fetch, from the log reachable at the given origin (i.e. log URL),
+	// a consistency proof between two tree sizes.
 	// Return error if:
-	//    - the parsing of the public keys fails
-	SetKey(logKey []string, submitKey []string) error
-
-	// Set the witness policy for the transparency engine.
-	// The function expects in input a policy as returned by ParseWitnessPolicy()
+	//    - the old size is greater than the new size
+	//    - the log origin is invalid or the log is not reachable
+	//    - the log cannot provide a proof for the requested tree sizes
+	//    - the context is done
+	GetConsistencyProof(ctx context.Context, origin string, oldSize uint64, newSize uint64) (*ConsistencyProof, error)
+
+	// Verify that the older tree head is a prefix of the newer one,
+	// given a consistency proof between their sizes. Tree heads are
+	// expected to be already verified against a trusted log key.
 	// Return error if:
-	//   - the parsing of the policy fails
-	SetWitnessPolicy(wp interface{}) error
-
-	// Reset the witness policy for the transparency engine.
-	ResetWitnessPolicy()
+	//    - tree heads are not from the same log origin
+	//    - the proof sizes do not match the tree heads sizes
+	//    - the consistency proof is not valid
+	VerifyConsistency(oldTreeHead *TreeHead, newTreeHead *TreeHead, proof *ConsistencyProof) error
 
 	// Verify the proof of the log, expects an input proof bundle
-	// as returned by ParseProof().
+	// as returned by ParseProof() or ParseBundle().
 	// Return error if:
 	//    - the proof verification fails
 	//    - the parsing of the proof bundle fails
 	//    - public keys for log, submitter or cosigners are not set
 	//    - the witness signing quorum is not reached
+	//    - the tree head is older than the trusted one (i.e. rollback)
+	//    - the consistency proof from the trusted tree head is not valid
+	//    - the tree head cosignatures are older than the maximum age
 	VerifyProof(proofBundle interface{}) error
 
 	// Parse the probing data, and the inclusion proof (if present)
@@ -103,20 +141,69 @@ type Engine interface {
 	ParseProof(jsonProofBundle []byte) (interface{}, []byte, error)
 }
 
+// Define the configuration of a transparency engine instance.
+type Config struct {
+	// list of trusted public keys to verify log signatures,
+	// their format depends by the chosen transparency engine
+	LogKey []string
+
+	// list of trusted public keys to verify leaf signatures,
+	// their format depends by the chosen transparency engine
+	SubmitKey []string
+
+	// witness policy, its format depends by the chosen transparency
+	// engine. The Tessera engine refuses to get, or verify, proofs when
+	// it is empty.
+	WitnessPolicy []byte
+
+	// trust state used to persist the latest verified tree head for
+	// each log origin. When set, VerifyProof rejects tree heads older
+	// than the trusted one and requires a consistency proof whenever
+	// the tree head is more recent than the trusted one.
+	TrustState TrustState
+
+	// maximum age of the verified tree head, the age is measured against
+	// the timestamp of the witness cosignatures that are satisfying the
+	// witness policy. A zero value disables the check.
+	MaxAge time.Duration
+
+	// network configuration used to reach the logs
+	Network Network
+}
+
+// Define the constructor of a transparency engine instance.
+type Factory func(cfg Config) (Engine, error)
+
 // Define the list of registered transparency engines
-var engines = make(map[uint]*Engine)
+var (
+	mu        sync.RWMutex
+	factories = make(map[uint]Factory)
+)
+
+// Register a transparency engine constructor, it is meant to
+// be invoked by the transparency engine package init().
+func Register(t uint, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
 
-// Register a transparency engine
-func Add(e Engine, t uint) {
-	engines[t] = &e
+	factories[t] = f
 }
 
-// Return the registered transparency engine, if present
-func GetEngine(t uint) (Engine, error) {
-	e := engines[t]
-	if e == nil {
-		return nil, fmt.Errorf("Transparency engine not registered")
+// Return a new instance of the registered transparency engine, configured
+// with the given configuration.
+// Each instance is independent from others, its configuration cannot be
+// modified after creation, therefore it is safe for concurrent use.
+// Return error if:
+//   - the transparency engine is not registered
+//   - any of the configured keys, or the witness policy, is not valid
+func NewEngine(t uint, cfg Config) (Engine, error) {
+	mu.RLock()
+	f := factories[t]
+	mu.RUnlock()
+
+	if f == nil {
+		return nil, fmt.Errorf("transparency engine %d not registered", t)
 	}
 
-	return *e, nil
+	return f(cfg)
 }
@@ -0,0 +1,13 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package verifier
+
+import (
+	_ "github.com/usbarmory/boot-transparency/engine/sigsum"
+	_ "github.com/usbarmory/boot-transparency/engine/tessera"
+)
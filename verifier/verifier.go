@@ -0,0 +1,244 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/usbarmory/boot-transparency/policy"
+	"github.com/usbarmory/boot-transparency/statement"
+	"github.com/usbarmory/boot-transparency/transparency"
+)
+
+// Define the trust anchors and the boot policy of a Verifier.
+type Config struct {
+	// transparency engines configuration (i.e. log, submitter and witness
+	// keys), indexed by transparency engine (e.g. transparency.Sigsum).
+	// Only proof bundles for the configured engines are accepted.
+	Engines map[uint]transparency.Config
+
+	// boot policy, in JSON format
+	BootPolicy []byte
+
+	// time source used to check the statement validity period, when
+	// required by the boot policy (see policy.Environment)
+	Time func() (time.Time, error)
+
+	// identity of the booted device, used to check the statement target
+	// when required by the boot policy
+	Device *statement.Device
+}
+
+// Define the result of a successful verification.
+type Result struct {
+	// verified proof bundle
+	Bundle transparency.Bundle
+
+	// logged statement, authorized by the boot policy
+	Statement *statement.Statement
+
+	// outcome of each boot policy entry, artifact and requirement
+	Decision *policy.Decision
+}
+
+// Define a verifier of proof bundles, its configuration cannot be changed
+// after its creation so that it can be safely used concurrently.
+type Verifier struct {
+	engines map[uint]transparency.Engine
+	// engines verifying revocation proof bundles, without trust state
+	revocationEngines map[uint]transparency.Engine
+	policy            *[]policy.PolicyEntry
+	env               *policy.Environment
+}
+
+// Return a new Verifier for the given configuration.
+//
+// Return error if:
+//   - no transparency engine is configured
+//   - any transparency engine configuration is invalid
+//   - the boot policy parsing fails
+func New(cfg Config) (v *Verifier, err error) {
+	if len(cfg.Engines) == 0 {
+		return nil, fmt.Errorf("no transparency engine configured")
+	}
+
+	v = &Verifier{
+		engines:           make(map[uint]transparency.Engine),
+		revocationEngines: make(map[uint]transparency.Engine),
+		env: &policy.Environment{
+			Time:   cfg.Time,
+			Device: cfg.Device,
+		},
+	}
+
+	for format, c := range cfg.Engines {
+		if v.engines[format], err = transparency.NewEngine(format, c); err != nil {
+			return nil, fmt.Errorf("invalid transparency engine %d configuration: %w", format, err)
+		}
+
+		// revocation proof bundles might be at any tree head, either
+		// older or newer than the verified bundle one, therefore they
+		// are neither checked against, nor updating, the trust state
+		c.TrustState = nil
+
+		if v.revocationEngines[format], err = transparency.NewEngine(format, c); err != nil {
+			return nil, fmt.Errorf("invalid transparency engine %d configuration: %w", format, err)
+		}
+	}
+
+	if v.policy, err = policy.Parse(cfg.BootPolicy); err != nil {
+		return nil, fmt.Errorf("invalid boot policy: %w", err)
+	}
+
+	return
+}
+
+// Verify a proof bundle, in JSON format, off-line (i.e. using the inclusion
+// proof included in the bundle).
+//
+// The proof bundles of the revocation statements (see
+// statement.KindRevocation) known to the caller can be passed as well, they
+// are verified off-line, without any trust state, and the bundle is refused
+// if revoked by any of them (see policy.EvaluateWith()).
+//
+// Return error if:
+//   - the proof bundle parsing fails
+//   - the transparency engine of the bundle is not configured
+//   - the transparency proof verification fails
+//   - the logged statement parsing fails
+//   - any revocation proof bundle verification fails
+//   - the logged claims do not meet the boot policy requirements
+//   - the logged statement is revoked
+func (v *Verifier) Verify(jsonProofBundle []byte, revocations ...[]byte) (*Result, error) {
+	b, e, err := v.parse(jsonProofBundle)
+
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := v.environment(revocations)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.verify(b, e, env)
+}
+
+// Verify a proof bundle, in JSON format, on-line. The inclusion proof
+// included in the bundle, if any, is replaced with a fresh one fetched
+// from the log before the verification.
+//
+// The revocation proof bundles, if any, are verified off-line (see
+// Verify()).
+//
+// Return error if:
+//   - any of the Verify() conditions is met
+//   - the inclusion proof cannot be fetched from the log
+func (v *Verifier) VerifyOnline(ctx context.Context, jsonProofBundle []byte, revocations ...[]byte) (*Result, error) {
+	b, e, err := v.parse(jsonProofBundle)
+
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := v.environment(revocations)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = e.GetProof(ctx, b); err != nil {
+		return nil, fmt.Errorf("fetching inclusion proof: %w", err)
+	}
+
+	return v.verify(b, e, env)
+}
+
+// Return the policy evaluation environment, including the revocation
+// statements of the given proof bundles once verified.
+func (v *Verifier) environment(revocations [][]byte) (*policy.Environment, error) {
+	if len(revocations) == 0 {
+		return v.env, nil
+	}
+
+	env := *v.env
+	env.Revocations = nil
+
+	for i, jsonProofBundle := range revocations {
+		b, _, err := v.parse(jsonProofBundle)
+
+		if err != nil {
+			return nil, fmt.Errorf("revocation %d: %w", i, err)
+		}
+
+		e := v.revocationEngines[b.Format()]
+
+		if err = e.VerifyProof(b); err != nil {
+			return nil, fmt.Errorf("revocation %d: transparency check failed: %w", i, err)
+		}
+
+		s, err := statement.Parse(b.Statement())
+
+		if err != nil {
+			return nil, fmt.Errorf("revocation %d: invalid statement: %w", i, err)
+		}
+
+		if s.Kind != statement.KindRevocation {
+			return nil, fmt.Errorf("revocation %d: not a revocation statement", i)
+		}
+
+		env.Revocations = append(env.Revocations, s)
+	}
+
+	return &env, nil
+}
+
+func (v *Verifier) parse(jsonProofBundle []byte) (b transparency.Bundle, e transparency.Engine, err error) {
+	if b, err = transparency.ParseBundle(jsonProofBundle); err != nil {
+		return nil, nil, fmt.Errorf("invalid proof bundle: %w", err)
+	}
+
+	e, ok := v.engines[b.Format()]
+
+	if !ok {
+		return nil, nil, fmt.Errorf("transparency engine %d not configured", b.Format())
+	}
+
+	return
+}
+
+func (v *Verifier) verify(b transparency.Bundle, e transparency.Engine, env *policy.Environment) (*Result, error) {
+	if err := e.VerifyProof(b); err != nil {
+		return nil, fmt.Errorf("transparency check failed: %w", err)
+	}
+
+	s, err := statement.Parse(b.Statement())
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement: %w", err)
+	}
+
+	d, err := policy.EvaluateWith(v.policy, s, env)
+
+	if err != nil {
+		return nil, fmt.Errorf("boot policy check failed: %w", err)
+	}
+
+	if err = d.Err(); err != nil {
+		return nil, fmt.Errorf("boot policy check failed: %w", err)
+	}
+
+	return &Result{
+		Bundle:    b,
+		Statement: s,
+		Decision:  d,
+	}, nil
+}
@@ -0,0 +1,372 @@
+// https://github.com/usbarmory/boot-transparency
+//
+// Copyright (c) The boot-transparency authors. All Rights Reserved.
+//
+// Use of this source code is governed by the license
+// that can be found in the LICENSE file.
+
+package verifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/usbarmory/boot-transparency/engine/tessera/testlog"
+	"github.com/usbarmory/boot-transparency/policy"
+	"github.com/usbarmory/boot-transparency/statement"
+	"github.com/usbarmory/boot-transparency/transparency"
+)
+
+var bootPolicy []byte
+var sigsumProofBundle []byte
+var sigsumWitnessPolicy []byte
+var tesseraProofBundle []byte
+var tesseraWitnessPolicy []byte
+
+var sigsumLogKey = []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEZEryq9QPSJWgA7yjUPnVkSqzAaScd/E+W22QXCCl/m"}
+var sigsumSubmitKey = []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMqym9S/tFn6B/Eri5hGJiEV8BpGumEPcm65uxC+FG6K"}
+var tesseraLogKey = []string{"boot-transparency.example.org/test-log+ac6201cc+AcKTHxA3E5VGzAl479kMdlO8aG+56P+kQ11M5uo5CA6K"}
+
+func TestLoadTestData(t *testing.T) {
+	var err error
+
+	for _, f := range []struct {
+		path string
+		data *[]byte
+	}{
+		{"../testdata/policy/policy.json", &bootPolicy},
+		{"../testdata/sigsum/bt-proof-bundle.json", &sigsumProofBundle},
+		{"../testdata/sigsum/witness_policy.txt", &sigsumWitnessPolicy},
+		{"../testdata/tessera/proof_bundle.json", &tesseraProofBundle},
+		{"../testdata/tessera/witness_policy.txt", &tesseraWitnessPolicy},
+	} {
+		if *f.data, err = os.ReadFile(f.path); err != nil {
+			t.Errorf("failed to load test data: %s", err)
+		}
+	}
+}
+
+func newVerifier(t *testing.T, bootPolicy []byte) *Verifier {
+	v, err := New(Config{
+		Engines: map[uint]transparency.Config{
+			transparency.Sigsum: {
+				LogKey:        sigsumLogKey,
+				SubmitKey:     sigsumSubmitKey,
+				WitnessPolicy: sigsumWitnessPolicy,
+			},
+			transparency.Tessera: {
+				LogKey:        tesseraLogKey,
+				WitnessPolicy: tesseraWitnessPolicy,
+			},
+		},
+		BootPolicy: bootPolicy,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
+func TestVerify(t *testing.T) {
+	v := newVerifier(t, bootPolicy)
+
+	for _, pb := range [][]byte{sigsumProofBundle, tesseraProofBundle} {
+		r, err := v.Verify(pb)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.Statement == nil || r.Statement.Description != "Linux bundle" {
+			t.Fatalf("unexpected statement: %+v", r.Statement)
+		}
+
+		if r.Bundle == nil || r.Bundle.Native() == nil {
+			t.Fatal("verified bundle is not set")
+		}
+
+		if r.Decision == nil || !r.Decision.Allowed {
+			t.Fatalf("unexpected policy decision: %+v", r.Decision)
+		}
+	}
+}
+
+func TestNegativeVerify(t *testing.T) {
+	// the statement does not include a Linux kernel this recent
+	restrictivePolicy := []byte(`[{"artifacts": [{"category": 1, "requirements": {"min_version": "v7.0.0"}}]}]`)
+
+	v := newVerifier(t, restrictivePolicy)
+
+	// error expected here as the policy is not satisfied
+	if _, err := v.Verify(sigsumProofBundle); !errors.Is(err, policy.ErrRequirementNotMet) {
+		t.Fatalf("bundle not satisfying the boot policy has been authorized: %v", err)
+	}
+
+	// the statement validity cannot be checked without a time source
+	currentPolicy := []byte(`[{"artifacts": [{"category": 1, "requirements": {}}], "validity": {"current": true}}]`)
+
+	v = newVerifier(t, currentPolicy)
+
+	// error expected here as the verifier time source is not configured
+	if _, err := v.Verify(sigsumProofBundle); !errors.Is(err, policy.ErrNoTimeSource) {
+		t.Fatalf("bundle with unchecked validity has been authorized: %v", err)
+	}
+
+	// the statement target cannot be checked without a device identity
+	targetPolicy := []byte(`[{"artifacts": [{"category": 1, "requirements": {}}], "target": {"declared": true}}]`)
+
+	v = newVerifier(t, targetPolicy)
+
+	// error expected here as the statement does not declare its target
+	if _, err := v.Verify(sigsumProofBundle); !errors.Is(err, policy.ErrTargetNotMet) {
+		t.Fatalf("bundle without target has been authorized: %v", err)
+	}
+
+	v = newVerifier(t, bootPolicy)
+
+	// error expected here as the revocation bundle is not a valid JSON
+	if _, err := v.Verify(sigsumProofBundle, tesseraProofBundle[1:]); err == nil {
+		t.Fatal("invalid revocation bundle has been accepted")
+	}
+
+	// error expected here as the logged statement is not a revocation
+	if _, err := v.Verify(sigsumProofBundle, tesseraProofBundle); err == nil {
+		t.Fatal("bundle has been accepted as revocation")
+	}
+
+	// error expected here as the bundle is not a valid JSON
+	if _, err := v.Verify(sigsumProofBundle[1:]); err == nil {
+		t.Fatal("invalid bundle has been authorized")
+	}
+
+	// error expected here as the checkpoint has been tampered with
+	forged := bytes.Replace(tesseraProofBundle, []byte(`test-log\n8\n`), []byte(`test-log\n9\n`), 1)
+
+	if _, err := v.Verify(forged); err == nil {
+		t.Fatal("forged bundle has been authorized")
+	}
+
+	v, err := New(Config{
+		Engines: map[uint]transparency.Config{
+			transparency.Sigsum: {
+				LogKey:        sigsumLogKey,
+				SubmitKey:     sigsumSubmitKey,
+				WitnessPolicy: sigsumWitnessPolicy,
+			},
+		},
+		BootPolicy: bootPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error expected here as the Tessera engine is not configured
+	if _, err := v.Verify(tesseraProofBundle); err == nil {
+		t.Fatal("bundle for an unconfigured engine has been authorized")
+	}
+}
+
+func TestNegativeNew(t *testing.T) {
+	// error expected here as no engine is configured
+	if _, err := New(Config{BootPolicy: bootPolicy}); err == nil {
+		t.Fatal("verifier without transparency engines has been created")
+	}
+
+	// error expected here as the log key is not valid
+	if _, err := New(Config{
+		Engines: map[uint]transparency.Config{
+			transparency.Tessera: {LogKey: []string{"invalid"}},
+		},
+		BootPolicy: bootPolicy,
+	}); err == nil {
+		t.Fatal("verifier with invalid log key has been created")
+	}
+
+	// error expected here as the boot policy is not valid
+	if _, err := New(Config{
+		Engines: map[uint]transparency.Config{
+			transparency.Tessera: {LogKey: tesseraLogKey},
+		},
+		BootPolicy: []byte(`{`),
+	}); err == nil {
+		t.Fatal("verifier with invalid boot policy has been created")
+	}
+}
+
+func TestNegativeVerifyOnline(t *testing.T) {
+	v := newVerifier(t, bootPolicy)
+
+	// unreachable log origin
+	pb := bytes.Replace(tesseraProofBundle, []byte(`https://boot-transparency.example.org/test-log/`), []byte(`http://127.0.0.1:0/`), 1)
+
+	// error expected here as the log cannot be reached
+	if _, err := v.VerifyOnline(context.Background(), pb); err == nil {
+		t.Fatal("bundle has been authorized without a fresh inclusion proof")
+	}
+}
+
+// Return a statement, and a boot policy requiring its signature, signed
+// with an ephemeral key.
+func newSignedStatement(t *testing.T) (jsonStatement []byte, bootPolicy []byte) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+
+	s, err := statement.Parse([]byte(`{"description": "online", "artifacts": [{"category": 1, "claims": {"hash": "8ba6bc3d9ccfe9c17ad7482d6c0160150c7d1da4b4a4f464744ce069291d6174ea9949574002f022e18585df04f57c192431794f36f40659930bd5c0b470eb59"}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := s.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Signatures = append(s.Signatures, statement.Signature{
+		PubKey:    pubKey,
+		Signature: hex.EncodeToString(ed25519.Sign(priv, payload)),
+		Envelope:  statement.SignatureNamespace,
+	})
+
+	if jsonStatement, err = json.Marshal(s); err != nil {
+		t.Fatal(err)
+	}
+
+	bootPolicy = fmt.Appendf(nil, `[{"artifacts": [{"category": 1, "requirements": {}}], "signatures": {"signers": [{"pub_key": "%s"}], "quorum": 1}}]`, pubKey)
+
+	return
+}
+
+func TestVerifyOnlineTrustState(t *testing.T) {
+	ctx := context.Background()
+	logURL, logKey, witnessPolicy := testlog.New(t)
+	jsonStatement, bootPolicy := newSignedStatement(t)
+
+	cfg := transparency.Config{
+		LogKey:        []string{logKey},
+		WitnessPolicy: witnessPolicy,
+	}
+
+	e, err := transparency.NewEngine(transparency.Tessera, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pb, err := e.Submit(ctx, logURL, jsonStatement, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonProofBundle, err := json.Marshal(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the trust state is shared by all verifications
+	ts := &transparency.MemoryTrustState{}
+	cfg.TrustState = ts
+
+	v, err := New(Config{
+		Engines:    map[uint]transparency.Config{transparency.Tessera: cfg},
+		BootPolicy: bootPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if _, err = v.VerifyOnline(ctx, jsonProofBundle); err != nil {
+			t.Fatalf("verification %d: %v", i, err)
+		}
+
+		th, err := ts.Load("example.org/test-log")
+		if err != nil || th == nil || th.Size != uint64(i) {
+			t.Fatalf("unexpected trusted tree head after verification %d: %+v, %v", i, th, err)
+		}
+
+		// grow the log, the next verification requires a consistency
+		// proof from the trusted tree head
+		if _, err = e.Submit(ctx, logURL, []byte(fmt.Sprintf(`{"description": "entry %d"}`, i)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestVerifyRevocationTrustState(t *testing.T) {
+	ctx := context.Background()
+	logURL, logKey, witnessPolicy := testlog.New(t)
+	jsonStatement, bootPolicy := newSignedStatement(t)
+
+	cfg := transparency.Config{
+		LogKey:        []string{logKey},
+		WitnessPolicy: witnessPolicy,
+	}
+
+	e, err := transparency.NewEngine(transparency.Tessera, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the revocation is logged after the statement, its proof is
+	// relative to a more recent tree head
+	var proofBundles [][]byte
+
+	for _, s := range [][]byte{
+		jsonStatement,
+		fmt.Appendf(nil, `{"kind": "revocation", "revokes": [{"digest": "%s", "status": "revoked"}], "artifacts": []}`, strings.Repeat("ab", 64)),
+	} {
+		pb, err := e.Submit(ctx, logURL, s, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		jsonProofBundle, err := json.Marshal(pb)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		proofBundles = append(proofBundles, jsonProofBundle)
+	}
+
+	ts := &transparency.MemoryTrustState{}
+	cfg.TrustState = ts
+
+	v, err := New(Config{
+		Engines:    map[uint]transparency.Config{transparency.Tessera: cfg},
+		BootPolicy: bootPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = v.Verify(proofBundles[0], proofBundles[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	// the trust state is only updated by the verified bundle
+	th, err := ts.Load("example.org/test-log")
+	if err != nil || th == nil || th.Size != 1 {
+		t.Fatalf("unexpected trusted tree head: %+v, %v", th, err)
+	}
+}